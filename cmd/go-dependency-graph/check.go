@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/rules"
+)
+
+var (
+	errMissingRules    = errors.New("rules is required")
+	errRulesViolations = errors.New("architecture rules violated")
+)
+
+// runCheck checks the dependencies against the rules file, it fails when a rule is violated.
+func runCheck(args []string) error {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	pf := addParseFlags(fs)
+	rulesFile := fs.String("rules", "", "the path of the rules file, YAML or JSON")
+	format := fs.String("format", formatText, "the format of the violations, [text, json], default text")
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("fs.Parse: %w", err)
+	}
+	if *rulesFile == "" {
+		return errMissingRules
+	}
+
+	c, err := rules.Load(*rulesFile)
+	if err != nil {
+		return fmt.Errorf("rules.Load: %w", err)
+	}
+	project, err := pf.getProject()
+	if err != nil {
+		return fmt.Errorf("getProject: %w", err)
+	}
+	as, err := pf.getAst()
+	if err != nil {
+		return fmt.Errorf("getAst: %w", err)
+	}
+
+	violations, err := rules.Check(as, c, project)
+	if err != nil {
+		return fmt.Errorf("rules.Check: %w", err)
+	}
+	switch *format {
+	case formatText:
+		err = rules.WriteText(os.Stdout, violations)
+	case formatJSON:
+		err = rules.WriteJSON(os.Stdout, violations)
+	default:
+		return fmt.Errorf("%s: %w", *format, errUnknownFormat)
+	}
+	if err != nil {
+		return fmt.Errorf("write violations: %w", err)
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%d violations: %w", len(violations), errRulesViolations)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/config"
+	mocksconfig "github.com/emilien-puget/go-dependency-graph/pkg/mocks/config"
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+)
+
+// commands are the sub commands, without one the diagram and the mocks are generated.
+var commands = map[string]func(args []string) error{
+	"check":   runCheck,
+	"cycles":  runCycles,
+	"diff":    runDiff,
+	"export":  runExport,
+	"impact":  runImpact,
+	"metrics": runMetrics,
+	"unused":  runUnused,
+	"why":     runWhy,
+}
+
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+var errUnknownFormat = errors.New("unknown format")
+
+// parseFlags are the flags of the commands parsing a project.
+type parseFlags struct {
+	project          *string
+	skipFolders      *string
+	parseMode        *string
+	providerPatterns *patternsFlag
+	providerMethods  *bool
+}
+
+func addParseFlags(fs *flag.FlagSet) parseFlags {
+	providerPatterns := &patternsFlag{}
+	fs.Var(providerPatterns, "provider-pattern", "a regular expression matching the provider names in the provider parse mode, repeat the flag for several, default is "+parse.DefaultProviderNamePattern)
+	return parseFlags{
+		project:          fs.String("project", "", "the path of the project to inspect, default is current dir"),
+		skipFolders:      fs.String("skip-dirs", mocksconfig.DefaultOutOfPackageDirectory+","+config.VendorDir, "a comma separate list of directory to ignore, default value is the mocks and vendor directory"),
+		parseMode:        fs.String("parse-mode", string(parse.ModeProvider), "how providers are discovered, [provider, fx, wire, composition], default provider"),
+		providerPatterns: providerPatterns,
+		providerMethods:  fs.Bool("provider-methods", false, "whether the methods matching a provider pattern are providers in the provider parse mode, the methods named New* always are whatever the patterns, default is false"),
+	}
+}
+
+// patternsFlag is a repeatable flag, each occurrence adds a pattern, a pattern may contain a comma.
+type patternsFlag []string
+
+func (p *patternsFlag) String() string {
+	if p == nil {
+		return ""
+	}
+	return strings.Join(*p, " ")
+}
+
+func (p *patternsFlag) Set(value string) error {
+	*p = append(*p, value)
+	return nil
+}
+
+// getProject returns the project dir, the current dir if none is given.
+func (pf parseFlags) getProject() (string, error) {
+	if *pf.project != "" {
+		return *pf.project, nil
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("os.Getwd: %w", err)
+	}
+	return dir, nil
+}
+
+func (pf parseFlags) getAst() (parse.AstSchema, error) {
+	project, err := pf.getProject()
+	if err != nil {
+		return parse.AstSchema{}, fmt.Errorf("getProject: %w", err)
+	}
+	return getAst(&project, pf.skipFolders, pf.parseMode, pf.providerPatterns, pf.providerMethods)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/cycles"
+)
+
+var errCyclesFound = errors.New("dependency cycles found")
+
+// runCycles reports the dependency cycles, it fails when there is any.
+func runCycles(args []string) error {
+	fs := flag.NewFlagSet("cycles", flag.ExitOnError)
+	pf := addParseFlags(fs)
+	format := fs.String("format", formatText, "the format of the report, [text, json], default text")
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("fs.Parse: %w", err)
+	}
+
+	as, err := pf.getAst()
+	if err != nil {
+		return fmt.Errorf("getAst: %w", err)
+	}
+
+	report := cycles.Analyze(as)
+	switch *format {
+	case formatText:
+		err = cycles.WriteText(os.Stdout, report)
+	case formatJSON:
+		err = cycles.WriteJSON(os.Stdout, report)
+	default:
+		return fmt.Errorf("%s: %w", *format, errUnknownFormat)
+	}
+	if err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+	if len(report) > 0 {
+		return fmt.Errorf("%d cycles: %w", len(report), errCyclesFound)
+	}
+	return nil
+}
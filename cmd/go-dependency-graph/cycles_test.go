@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+
+	diagconfig "github.com/emilien-puget/go-dependency-graph/pkg/diagrams/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunCycles(t *testing.T) {
+	err := runCycles([]string{"--project=../../pkg/cycles/testdata/cycle"})
+	assert.ErrorIs(t, err, errCyclesFound)
+
+	err = runCycles([]string{"--project=../../pkg/impact/testdata/shop"})
+	assert.NoError(t, err)
+}
+
+func TestRun_cycles(t *testing.T) {
+	project, disabled, empty := "../../pkg/cycles/testdata/cycle", false, ""
+	failOnCycles := true
+	err := run(&project, &disabled, &disabled, &failOnCycles, &empty, &empty, &empty, diagconfig.Config{}, &empty, &empty, &empty, &empty, &patternsFlag{}, &disabled)
+	assert.ErrorIs(t, err, errCyclesFound)
+
+	failOnCycles = false
+	err = run(&project, &disabled, &disabled, &failOnCycles, &empty, &empty, &empty, diagconfig.Config{}, &empty, &empty, &empty, &empty, &patternsFlag{}, &disabled)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/graphdiff"
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/emilien-puget/go-dependency-graph/pkg/snapshot"
+)
+
+const (
+	formatC4      = "c4"
+	formatMermaid = "mermaid"
+)
+
+var errDiffUsage = errors.New("usage: diff [flags] <from> [<to>]")
+
+// runDiff prints how the graph changes between two revisions, or two snapshots.
+// Each argument is a snapshot file when such a file exists, a git revision otherwise, the project as it is on disk is
+// compared to the first when the second is omitted.
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	pf := addParseFlags(fs)
+	format := fs.String("format", formatText, "the format of the diff, [text, json, c4, mermaid], default text")
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("fs.Parse: %w", err)
+	}
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		return errDiffUsage
+	}
+
+	ctx := context.Background()
+	before, err := getDiffSchema(ctx, pf, fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("%s: %w", fs.Arg(0), err)
+	}
+	var after parse.AstSchema
+	if fs.NArg() == 2 {
+		after, err = getDiffSchema(ctx, pf, fs.Arg(1))
+		if err != nil {
+			return fmt.Errorf("%s: %w", fs.Arg(1), err)
+		}
+	} else {
+		after, err = pf.getAst()
+		if err != nil {
+			return fmt.Errorf("getAst: %w", err)
+		}
+	}
+
+	d := graphdiff.Compare(before, after)
+	switch *format {
+	case formatText:
+		err = graphdiff.WriteText(os.Stdout, d)
+	case formatJSON:
+		err = graphdiff.WriteJSON(os.Stdout, d)
+	case formatC4:
+		err = graphdiff.WriteC4(os.Stdout, d)
+	case formatMermaid:
+		err = graphdiff.WriteMermaid(os.Stdout, d)
+	default:
+		return fmt.Errorf("%s: %w", *format, errUnknownFormat)
+	}
+	if err != nil {
+		return fmt.Errorf("write diff: %w", err)
+	}
+	return nil
+}
+
+// getDiffSchema reads the snapshot file named ref, or parses the project checked out at the git revision ref.
+func getDiffSchema(ctx context.Context, pf parseFlags, ref string) (parse.AstSchema, error) {
+	if info, err := os.Stat(ref); err == nil && !info.IsDir() {
+		s, err := snapshot.ReadFile(ref)
+		if err != nil {
+			return parse.AstSchema{}, fmt.Errorf("snapshot.ReadFile: %w", err)
+		}
+		return s.ToSchema(), nil
+	}
+
+	project, err := pf.getProject()
+	if err != nil {
+		return parse.AstSchema{}, fmt.Errorf("getProject: %w", err)
+	}
+	dir, remove, err := graphdiff.Checkout(ctx, project, ref)
+	if err != nil {
+		return parse.AstSchema{}, fmt.Errorf("graphdiff.Checkout: %w", err)
+	}
+	defer func() {
+		_ = remove()
+	}()
+	as, err := getAst(&dir, pf.skipFolders, pf.parseMode, pf.providerPatterns, pf.providerMethods)
+	if err != nil {
+		return parse.AstSchema{}, fmt.Errorf("getAst: %w", err)
+	}
+	return as, nil
+}
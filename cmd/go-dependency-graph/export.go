@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/neo4j"
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/emilien-puget/go-dependency-graph/pkg/snapshot"
+)
+
+const (
+	formatCypher   = "cypher"
+	formatNeo4jCSV = "neo4j_csv"
+)
+
+var errMissingExportOut = errors.New("out is required with the neo4j_csv format")
+
+// runExport writes the graph to be imported in a graph database, it never connects to one.
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	pf := addParseFlags(fs)
+	format := fs.String("format", formatCypher, "the format of the export, [cypher, neo4j_csv], default cypher")
+	out := fs.String("out", "", "the file the cypher statements are written to, default is stdout, or the directory the neo4j-admin import files are written to")
+	snapshotFile := fs.String("snapshot", "", "the path of a snapshot written by the json generator to export instead of parsing the project")
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("fs.Parse: %w", err)
+	}
+
+	var as parse.AstSchema
+	if *snapshotFile != "" {
+		s, err := snapshot.ReadFile(*snapshotFile)
+		if err != nil {
+			return fmt.Errorf("snapshot.ReadFile: %w", err)
+		}
+		as = s.ToSchema()
+	} else {
+		as, err = pf.getAst()
+		if err != nil {
+			return fmt.Errorf("getAst: %w", err)
+		}
+	}
+
+	switch *format {
+	case formatCypher:
+		w := os.Stdout
+		if *out != "" {
+			w, err = os.Create(*out)
+			if err != nil {
+				return fmt.Errorf("os.Create: %w", err)
+			}
+			defer w.Close()
+		}
+		err = neo4j.WriteCypher(w, as)
+		if err != nil {
+			return fmt.Errorf("neo4j.WriteCypher: %w", err)
+		}
+		if *out != "" {
+			return w.Close()
+		}
+	case formatNeo4jCSV:
+		if *out == "" {
+			return errMissingExportOut
+		}
+		err = neo4j.WriteCSV(*out, as)
+		if err != nil {
+			return fmt.Errorf("neo4j.WriteCSV: %w", err)
+		}
+	default:
+		return fmt.Errorf("%s: %w", *format, errUnknownFormat)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/impact"
+)
+
+// runImpact reports the components affected by the changed files given as arguments, or by the unified diff read on stdin.
+func runImpact(args []string) error {
+	fs := flag.NewFlagSet("impact", flag.ExitOnError)
+	pf := addParseFlags(fs)
+	format := fs.String("format", formatText, "the format of the report, [text, json], default text")
+	baseDir := fs.String("base-dir", "", "the directory the changed files are relative to, such as the root of the git repository, default is the project dir")
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("fs.Parse: %w", err)
+	}
+
+	var changes []impact.Change
+	if fs.NArg() > 0 {
+		for _, file := range fs.Args() {
+			changes = append(changes, impact.Change{File: file})
+		}
+	} else {
+		changes, err = impact.ParseDiff(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("impact.ParseDiff: %w", err)
+		}
+	}
+
+	c := impact.Config{BaseDir: *baseDir}
+	if c.BaseDir == "" {
+		c.BaseDir, err = pf.getProject()
+		if err != nil {
+			return fmt.Errorf("getProject: %w", err)
+		}
+	}
+	as, err := pf.getAst()
+	if err != nil {
+		return fmt.Errorf("getAst: %w", err)
+	}
+
+	report := impact.Analyze(as, changes, c)
+	switch *format {
+	case formatText:
+		err = impact.WriteText(os.Stdout, report)
+	case formatJSON:
+		err = impact.WriteJSON(os.Stdout, report)
+	default:
+		return fmt.Errorf("%s: %w", *format, errUnknownFormat)
+	}
+	if err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+	return nil
+}
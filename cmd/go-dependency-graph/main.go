@@ -9,27 +9,59 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/emilien-puget/go-dependency-graph/pkg/config"
 	"github.com/emilien-puget/go-dependency-graph/pkg/diagrams"
+	diagconfig "github.com/emilien-puget/go-dependency-graph/pkg/diagrams/config"
+	"github.com/emilien-puget/go-dependency-graph/pkg/diagrams/sequence"
 	"github.com/emilien-puget/go-dependency-graph/pkg/mocks"
 	mocksconfig "github.com/emilien-puget/go-dependency-graph/pkg/mocks/config"
 	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/emilien-puget/go-dependency-graph/pkg/snapshot"
 	"github.com/emilien-puget/go-dependency-graph/pkg/writer"
 	"golang.org/x/sync/errgroup"
 )
 
 func main() {
-	project := flag.String("project", "", "the path of the project to inspect, default is current dir")
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			err := command(os.Args[2:])
+			if err != nil {
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	pf := addParseFlags(flag.CommandLine)
 	diagEnable := flag.Bool("generate-diag", true, "enable diagram generation, default is true")
 	mocksEnable := flag.Bool("generate-mocks", true, "enable mocks generation, default is true")
 	diagResult := flag.String("diag-result", "", "the path of the generated file, not used if stdout is piped, default is in the project dir")
-	diagGenerator := flag.String("diag-generator", "c4_plantuml_component", "the name of the generator to use, [c4_plantuml_component, mermaid_class], default c4_plantuml_component")
+	diagGenerator := flag.String("diag-generator", diagrams.GeneratorC4PlantumlComponent, fmt.Sprintf("the name of the generator to use, [%s], default %s", strings.Join(diagrams.GetGeneratorNames(), ", "), diagrams.GeneratorC4PlantumlComponent))
+	diagRankDir := flag.String("diag-rank-dir", "", "the direction of the layout, [TB, LR, BT, RL], used by mermaid_flowchart, graphviz_dot and d2, default is the one of the generator")
+	diagLinkBase := flag.String("diag-link-base", "", "the URL the source files, relative to the project, are appended to in the links of mermaid_flowchart, such as https://github.com/org/repo/blob/main, default is the relative path")
+	diagDSMLevel := flag.String("diag-dsm-level", "", "the rows of the dependency structure matrix, [package, component], used by dsm_csv and dsm_html, default package")
+	diagDSMValue := flag.String("diag-dsm-value", "", "what the cells of the dependency structure matrix count, [edges, funcs], used by dsm_csv and dsm_html, default edges")
+	diagSequenceEntry := flag.String("diag-sequence-entry", "", "the method the sequence diagram starts from, such as handlers.OrderHandler.Create, required by plantuml_sequence and mermaid_sequence")
+	diagSequenceDepth := flag.Int("diag-sequence-depth", sequence.DefaultDepth, "the number of dependencies whose calls the sequence diagram follows, used by plantuml_sequence and mermaid_sequence")
+	diagC4Metadata := flag.String("diag-c4-metadata", "", "the path of a YAML or JSON file naming the system, its containers, their technology and description, its users and external systems, used by c4_plantuml_container and c4_plantuml_context")
+	diagClusters := flag.Bool("diag-clusters", true, "group the nodes of each package into a cluster, used by graphviz_dot, default is true")
 	mockGenerator := flag.String("mock-generator", "mockery", "the name of the generator to use, [mockery], default mockery")
 	mockResult := flag.String("mock-result", mocksconfig.DefaultOutOfPackageDirectory, "where the mocks will be written")
-	skipFolders := flag.String("skip-dirs", mocksconfig.DefaultOutOfPackageDirectory+","+config.VendorDir, "a comma separate list of directory to ignore, default value is the mocks and vendor directory")
+	failOnCycles := flag.Bool("fail-on-cycles", true, "fail once the diagram and mocks are generated when there is a dependency cycle, the mocks rely on an order of the providers a cycle breaks, default is true")
+	snapshotFile := flag.String("snapshot", "", "the path of a snapshot written by the json generator to generate the diagram from instead of parsing the project, the mocks must be disabled")
 	flag.Parse()
 
-	err := run(project, diagEnable, mocksEnable, diagResult, diagGenerator, mockGenerator, mockResult, skipFolders)
+	diagConfig := diagconfig.Config{
+		RankDir:         *diagRankDir,
+		DisableClusters: !*diagClusters,
+		LinkBase:        *diagLinkBase,
+		DSMLevel:        *diagDSMLevel,
+		DSMValue:        *diagDSMValue,
+		SequenceEntry:   *diagSequenceEntry,
+		SequenceDepth:   *diagSequenceDepth,
+		C4Metadata:      *diagC4Metadata,
+	}
+	err := run(pf.project, diagEnable, mocksEnable, failOnCycles, snapshotFile, diagResult, diagGenerator, diagConfig, mockGenerator, mockResult, pf.skipFolders, pf.parseMode, pf.providerPatterns, pf.providerMethods)
 	if err != nil {
 		_, _ = fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
@@ -40,9 +72,10 @@ var (
 	errMissingMockGenerator    = errors.New("mock-generator is required")
 	errMissingDiagramGenerator = errors.New("diag-generator is required")
 	errMissingMockResult       = errors.New("mock-result is required")
+	errSnapshotMocks           = errors.New("mocks cannot be generated from a snapshot")
 )
 
-func run(project *string, diagEnable, mocksEnable *bool, diagResult, diagGeneratorType, mockGeneratorType, mockResult, skipFolders *string) error {
+func run(project *string, diagEnable, mocksEnable, failOnCycles *bool, snapshotFile, diagResult, diagGeneratorType *string, diagConfig diagconfig.Config, mockGeneratorType, mockResult, skipFolders, parseMode *string, providerPatterns *patternsFlag, providerMethods *bool) error {
 	err := validateRequiredInput(diagEnable, mocksEnable, diagGeneratorType, mockGeneratorType, mockResult)
 	if err != nil {
 		return fmt.Errorf("validateRequiredInput: %w", err)
@@ -56,23 +89,41 @@ func run(project *string, diagEnable, mocksEnable *bool, diagResult, diagGenerat
 		project = &dir
 	}
 
-	as, err := getAst(project, skipFolders)
-	if err != nil {
-		return fmt.Errorf("getAst: %w", err)
+	var as parse.AstSchema
+	if snapshotFile != nil && *snapshotFile != "" {
+		if *mocksEnable {
+			return errSnapshotMocks
+		}
+		s, err := snapshot.ReadFile(*snapshotFile)
+		if err != nil {
+			return fmt.Errorf("snapshot.ReadFile: %w", err)
+		}
+		as = s.ToSchema()
+	} else {
+		as, err = getAst(project, skipFolders, parseMode, providerPatterns, providerMethods)
+		if err != nil {
+			return fmt.Errorf("getAst: %w", err)
+		}
 	}
 
-	err = runGenerators(as, project, diagEnable, mocksEnable, diagResult, diagGeneratorType, mockGeneratorType, mockResult)
+	err = runGenerators(as, project, diagEnable, mocksEnable, diagResult, diagGeneratorType, diagConfig, mockGeneratorType, mockResult)
 	if err != nil {
 		return fmt.Errorf("runGenerators: %w", err)
 	}
+
+	if *failOnCycles {
+		if c := as.Graph.GetCycles(); len(c) > 0 {
+			return fmt.Errorf("%d cycles: %w", len(c), errCyclesFound)
+		}
+	}
 	return nil
 }
 
-func runGenerators(as parse.AstSchema, project *string, diagEnable, mocksEnable *bool, diagResult, diagGeneratorType, mockGeneratorType, mockResult *string) (err error) {
+func runGenerators(as parse.AstSchema, project *string, diagEnable, mocksEnable *bool, diagResult, diagGeneratorType *string, diagConfig diagconfig.Config, mockGeneratorType, mockResult *string) (err error) {
 	group, ctx := errgroup.WithContext(context.Background())
 	if *diagEnable {
 		group.Go(func() error {
-			err = generateDiag(ctx, project, diagResult, diagGeneratorType, as)
+			err = generateDiag(ctx, project, diagResult, diagGeneratorType, diagConfig, as)
 			if err != nil {
 				return fmt.Errorf("generateDiag: %w", err)
 			}
@@ -96,8 +147,8 @@ func runGenerators(as parse.AstSchema, project *string, diagEnable, mocksEnable
 	return nil
 }
 
-func generateDiag(ctx context.Context, project, diagResult, diagGeneratorType *string, as parse.AstSchema) error {
-	diagGenerator, err := diagrams.GetGenerator(*diagGeneratorType)
+func generateDiag(ctx context.Context, project, diagResult, diagGeneratorType *string, diagConfig diagconfig.Config, as parse.AstSchema) error {
+	diagGenerator, err := diagrams.GetGenerator(*diagGeneratorType, diagConfig)
 	if err != nil {
 		return fmt.Errorf("diagrams.GetGenerator:%w", err)
 	}
@@ -152,15 +203,23 @@ func validateRequiredInput(diagEnable, mocksEnable *bool, diagGeneratorType, moc
 	return nil
 }
 
-func getAst(project, skipFolders *string) (parse.AstSchema, error) {
+func getAst(project, skipFolders, parseMode *string, providerPatterns *patternsFlag, providerMethods *bool) (parse.AstSchema, error) {
 	var skipDirs []string
 	if skipFolders != nil || *skipFolders != "" {
 		skipDirs = strings.Split(*skipFolders, ",")
 	}
 
-	as, err := parse.Parse(*project, skipDirs)
+	c := parse.Config{
+		Mode: parse.Mode(*parseMode),
+		Provider: parse.ProviderConfig{
+			NamePatterns: *providerPatterns,
+			Methods:      *providerMethods,
+		},
+	}
+
+	as, err := parse.ParseWithConfig(*project, skipDirs, c)
 	if err != nil {
-		return parse.AstSchema{}, fmt.Errorf("parse.Parse:%w", err)
+		return parse.AstSchema{}, fmt.Errorf("parse.ParseWithConfig:%w", err)
 	}
 	return as, nil
 }
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/metrics"
+)
+
+const (
+	formatTable = "table"
+	formatCSV   = "csv"
+)
+
+var errThresholdsExceeded = errors.New("metrics thresholds exceeded")
+
+// runMetrics reports the coupling metrics of the nodes and the packages, it fails when a threshold is exceeded.
+func runMetrics(args []string) error {
+	fs := flag.NewFlagSet("metrics", flag.ExitOnError)
+	pf := addParseFlags(fs)
+	format := fs.String("format", formatTable, "the format of the report, [table, csv, json], default table")
+	thresholds := metrics.Thresholds{}
+	fs.IntVar(&thresholds.MaxFanIn, "max-fan-in", 0, "the maximum fan-in of a node, disabled when 0")
+	fs.IntVar(&thresholds.MaxFanOut, "max-fan-out", 0, "the maximum fan-out of a node, disabled when 0")
+	fs.IntVar(&thresholds.MaxTransitive, "max-transitive", 0, "the maximum number of transitive dependencies of a node, disabled when 0")
+	fs.IntVar(&thresholds.MaxDepth, "max-depth", 0, "the maximum depth of a node from the entry points, disabled when 0")
+	fs.Float64Var(&thresholds.MaxInstability, "max-instability", 0, "the maximum instability of a package, disabled when 0")
+	fs.Float64Var(&thresholds.MaxDistance, "max-distance", 0, "the maximum distance from the main sequence of a package, disabled when 0")
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("fs.Parse: %w", err)
+	}
+
+	as, err := pf.getAst()
+	if err != nil {
+		return fmt.Errorf("getAst: %w", err)
+	}
+
+	report := metrics.Compute(as, thresholds)
+	switch *format {
+	case formatTable:
+		err = metrics.WriteTable(os.Stdout, report)
+	case formatCSV:
+		err = metrics.WriteCSV(os.Stdout, report)
+	case formatJSON:
+		err = metrics.WriteJSON(os.Stdout, report)
+	default:
+		return fmt.Errorf("%s: %w", *format, errUnknownFormat)
+	}
+	if err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+	if len(report.Violations) > 0 {
+		return fmt.Errorf("%d violations: %w", len(report.Violations), errThresholdsExceeded)
+	}
+	return nil
+}
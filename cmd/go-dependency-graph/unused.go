@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/unused"
+)
+
+var errUnusedFound = errors.New("unused dependencies found")
+
+// runUnused reports the dependencies never called, it fails when there is any.
+func runUnused(args []string) error {
+	fs := flag.NewFlagSet("unused", flag.ExitOnError)
+	pf := addParseFlags(fs)
+	format := fs.String("format", formatText, "the format of the report, [text, json], default text")
+	entryPoints := fs.String("entry-points", "", "a comma separated list of regular expressions matching the nodes not consumed on purpose, default is the nodes having dependencies")
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("fs.Parse: %w", err)
+	}
+
+	c := unused.Config{}
+	if *entryPoints != "" {
+		for _, pattern := range strings.Split(*entryPoints, ",") {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("regexp.Compile: %w", err)
+			}
+			c.EntryPoints = append(c.EntryPoints, re)
+		}
+	}
+
+	c.BaseDir, err = pf.getProject()
+	if err != nil {
+		return fmt.Errorf("getProject: %w", err)
+	}
+	as, err := pf.getAst()
+	if err != nil {
+		return fmt.Errorf("getAst: %w", err)
+	}
+
+	report := unused.Analyze(as, c)
+	switch *format {
+	case formatText:
+		err = unused.WriteText(os.Stdout, report)
+	case formatJSON:
+		err = unused.WriteJSON(os.Stdout, report)
+	default:
+		return fmt.Errorf("%s: %w", *format, errUnknownFormat)
+	}
+	if err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+	if report.Len() > 0 {
+		return fmt.Errorf("%d findings: %w", report.Len(), errUnusedFound)
+	}
+	return nil
+}
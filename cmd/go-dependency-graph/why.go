@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/diagrams"
+	diagconfig "github.com/emilien-puget/go-dependency-graph/pkg/diagrams/config"
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/emilien-puget/go-dependency-graph/pkg/why"
+)
+
+const formatDiagram = "diagram"
+
+var errWhyUsage = errors.New("usage: why [flags] <from> <to>")
+
+// runWhy prints the paths going from a node to another.
+func runWhy(args []string) error {
+	fs := flag.NewFlagSet("why", flag.ExitOnError)
+	pf := addParseFlags(fs)
+	format := fs.String("format", formatText, "the format of the paths, [text, json, diagram], default text")
+	diagGenerator := fs.String("diag-generator", diagrams.GeneratorC4PlantumlComponent, fmt.Sprintf("the name of the generator drawing the nodes of the paths with the diagram format, [%s], default %s", strings.Join(diagrams.GetNodeGeneratorNames(), ", "), diagrams.GeneratorC4PlantumlComponent))
+	k := fs.Int("k", 0, "the number of shortest paths to return, at most 1000 when 0, default 0")
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("fs.Parse: %w", err)
+	}
+	if fs.NArg() != 2 {
+		return errWhyUsage
+	}
+
+	as, err := pf.getAst()
+	if err != nil {
+		return fmt.Errorf("getAst: %w", err)
+	}
+
+	paths, err := why.Query(as, fs.Arg(0), fs.Arg(1), *k)
+	if err != nil {
+		return fmt.Errorf("why.Query: %w", err)
+	}
+	switch *format {
+	case formatText:
+		err = why.WriteText(os.Stdout, why.GetPaths(paths))
+	case formatJSON:
+		err = why.WriteJSON(os.Stdout, why.GetPaths(paths))
+	case formatDiagram:
+		err = writeWhyDiagram(*diagGenerator, why.GetSchema(as, paths))
+	default:
+		return fmt.Errorf("%s: %w", *format, errUnknownFormat)
+	}
+	if err != nil {
+		return fmt.Errorf("write paths: %w", err)
+	}
+	return nil
+}
+
+func writeWhyDiagram(generatorType string, s parse.AstSchema) error {
+	generator, err := diagrams.GetGenerator(generatorType, diagconfig.Config{})
+	if err != nil {
+		return fmt.Errorf("diagrams.GetGenerator:%w", err)
+	}
+	w := bufio.NewWriter(os.Stdout)
+	err = generator.GenerateFromSchema(context.Background(), w, s)
+	if err != nil {
+		return fmt.Errorf("generator.GenerateFromSchema:%w", err)
+	}
+	err = w.Flush()
+	if err != nil {
+		return fmt.Errorf("w.Flush:%w", err)
+	}
+	return nil
+}
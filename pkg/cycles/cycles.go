@@ -0,0 +1,94 @@
+package cycles
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+)
+
+// Cycle is a set of providers depending on each other.
+type Cycle struct {
+	Nodes []string `json:"nodes"`
+	Edges []Edge   `json:"edges"`
+}
+
+// Edge is a dependency between two providers of a cycle.
+type Edge struct {
+	From   string   `json:"from"`
+	To     string   `json:"to"`
+	Param  string   `json:"param,omitempty"`
+	Fields []string `json:"fields,omitempty"`
+	Via    string   `json:"via,omitempty"`
+}
+
+// Analyze returns the cycles of the graph, with the edges forming them.
+func Analyze(as parse.AstSchema) []Cycle {
+	cycleEdges := as.Graph.GetCycleEdges()
+	cycles := make([]Cycle, 0)
+	for _, nodes := range as.Graph.GetCycles() {
+		c := Cycle{}
+		for _, node := range nodes {
+			c.Nodes = append(c.Nodes, node.Name)
+			for _, adj := range cycleEdges[node] {
+				c.Edges = append(c.Edges, Edge{
+					From:   node.Name,
+					To:     adj.Node.Name,
+					Param:  adj.Param,
+					Fields: adj.Fields,
+					Via:    adj.Via,
+				})
+			}
+		}
+		cycles = append(cycles, c)
+	}
+	return cycles
+}
+
+// WriteText writes each cycle followed by its edges.
+func WriteText(w io.Writer, cycles []Cycle) error {
+	for i, c := range cycles {
+		_, err := fmt.Fprintf(w, "cycle %d: %s\n", i+1, strings.Join(c.Nodes, ", "))
+		if err != nil {
+			return fmt.Errorf("fmt.Fprintf:%w", err)
+		}
+		for _, e := range c.Edges {
+			_, err := fmt.Fprintf(w, "\t%s -> %s%s\n", e.From, e.To, getEdgeDetails(e))
+			if err != nil {
+				return fmt.Errorf("fmt.Fprintf:%w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// getEdgeDetails returns what forms the edge, the parameter of the provider, the fields and the interface.
+func getEdgeDetails(e Edge) string {
+	var details []string
+	if e.Param != "" {
+		details = append(details, "param "+e.Param)
+	}
+	if len(e.Fields) > 0 {
+		details = append(details, "field "+strings.Join(e.Fields, ", "))
+	}
+	if e.Via != "" {
+		details = append(details, "via "+e.Via)
+	}
+	if len(details) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(details, ", ") + ")"
+}
+
+// WriteJSON writes the cycles as an indented JSON document.
+func WriteJSON(w io.Writer, cycles []Cycle) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(cycles)
+	if err != nil {
+		return fmt.Errorf("encoder.Encode:%w", err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package cycles
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyze(t *testing.T) {
+	as, err := parse.Parse("testdata/cycle", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Cycle{
+		{
+			Nodes: []string{"testdata/cycle.Mailer", "testdata/cycle.Templates", "testdata/cycle.Users"},
+			Edges: []Edge{
+				{From: "testdata/cycle.Mailer", To: "testdata/cycle.Templates", Param: "templates", Fields: []string{"templates"}},
+				{From: "testdata/cycle.Templates", To: "testdata/cycle.Users", Param: "users", Fields: []string{"users"}},
+				{From: "testdata/cycle.Users", To: "testdata/cycle.Mailer", Param: "notifier", Fields: []string{"notifier"}, Via: "testdata/cycle.Notifier"},
+			},
+		},
+	}, Analyze(as))
+}
+
+func TestAnalyze_none(t *testing.T) {
+	as, err := parse.Parse("../parse/testdata/inter", nil)
+	require.NoError(t, err)
+
+	assert.Empty(t, Analyze(as))
+}
+
+func TestWriteText(t *testing.T) {
+	as, err := parse.Parse("testdata/cycle", nil)
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	err = WriteText(buf, Analyze(as))
+	require.NoError(t, err)
+
+	assert.Equal(t, `cycle 1: testdata/cycle.Mailer, testdata/cycle.Templates, testdata/cycle.Users
+	testdata/cycle.Mailer -> testdata/cycle.Templates (param templates, field templates)
+	testdata/cycle.Templates -> testdata/cycle.Users (param users, field users)
+	testdata/cycle.Users -> testdata/cycle.Mailer (param notifier, field notifier, via testdata/cycle.Notifier)
+`, buf.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := WriteJSON(buf, []Cycle{{Nodes: []string{"a.A"}, Edges: []Edge{{From: "a.A", To: "a.A", Param: "a"}}}})
+	require.NoError(t, err)
+
+	assert.Equal(t, `[
+  {
+    "nodes": [
+      "a.A"
+    ],
+    "edges": [
+      {
+        "from": "a.A",
+        "to": "a.A",
+        "param": "a"
+      }
+    ]
+  }
+]
+`, buf.String())
+}
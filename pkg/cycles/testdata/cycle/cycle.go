@@ -0,0 +1,56 @@
+package cycle
+
+// Notifier notifies the users.
+type Notifier interface {
+	Notify()
+}
+
+// Users manages the users.
+type Users struct {
+	notifier Notifier
+}
+
+func NewUsers(notifier Notifier) *Users {
+	return &Users{notifier: notifier}
+}
+
+func (u *Users) Find() {}
+
+// Mailer sends mails to the users.
+type Mailer struct {
+	templates *Templates
+}
+
+func NewMailer(templates *Templates) *Mailer {
+	return &Mailer{templates: templates}
+}
+
+func (m *Mailer) Notify() {
+	m.templates.Render()
+}
+
+// Templates renders the mails of the users.
+type Templates struct {
+	users *Users
+}
+
+func NewTemplates(users *Users) *Templates {
+	return &Templates{users: users}
+}
+
+func (t *Templates) Render() {
+	t.users.Find()
+}
+
+// Handler serves the users.
+type Handler struct {
+	users *Users
+}
+
+func NewHandler(users *Users) *Handler {
+	return &Handler{users: users}
+}
+
+func (h *Handler) Serve() {
+	h.users.Find()
+}
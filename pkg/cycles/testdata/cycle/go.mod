@@ -0,0 +1,3 @@
+module testdata/cycle
+
+go 1.19
@@ -11,7 +11,10 @@ import (
 	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
 )
 
-const umlSeparator = "_"
+const (
+	umlSeparator = "_"
+	cycleTag     = "cycle"
+)
 
 type Generator struct {
 	replacer *strings.Replacer
@@ -37,6 +40,13 @@ func (g Generator) GenerateFromSchema(ctx context.Context, writer *bufio.Writer,
 	if err != nil {
 		return err
 	}
+	cycleEdges := s.Graph.GetCycleEdges()
+	if len(cycleEdges) > 0 {
+		_, err = fmt.Fprintf(writer, "\nAddRelTag(%q, $textColor=\"red\", $lineColor=\"red\", $legendText=\"dependency cycle\")", cycleTag)
+		if err != nil {
+			return err
+		}
+	}
 
 	relations := ""
 	externalRelations := make(map[string]string)
@@ -47,7 +57,24 @@ func (g Generator) GenerateFromSchema(ctx context.Context, writer *bufio.Writer,
 			return ctx.Err()
 		default:
 		}
-		rel, err := g.handlePackages(writer, packageName, s.Graph.NodesByPackage[packageName], externalRelations, s.Graph, s.ModulePath)
+		services := s.Graph.GetPackageNodesOutsideModule(packageName)
+		if len(services) == 0 {
+			continue
+		}
+		rel, err := g.handlePackages(writer, packageName, services, externalRelations, s.Graph, cycleEdges, s.ModulePath)
+		if err != nil {
+			return err
+		}
+		relations += rel
+	}
+	nodesByModule := s.Graph.GetNodesByModule()
+	for _, module := range mymap.OrderedKeys(nodesByModule) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		rel, err := g.handleBoundary(writer, "module_"+g.replacer.Replace(module), module, s.ModulePath, nodesByModule[module], externalRelations, s.Graph, cycleEdges, s.ModulePath)
 		if err != nil {
 			return err
 		}
@@ -108,31 +135,40 @@ func (g Generator) printExternalRelations(writer *bufio.Writer, externalRelation
 	return nil
 }
 
-func (g Generator) handlePackages(writer *bufio.Writer, packageName string, services []*parse.Node, externalRelations map[string]string, graph *parse.Graph, modulePath string) (string, error) {
+func (g Generator) handlePackages(writer *bufio.Writer, packageName string, services []*parse.Node, externalRelations map[string]string, graph *parse.Graph, cycleEdges map[*parse.Node][]*parse.Adj, modulePath string) (string, error) {
 	name := g.trimPackageName(packageName, modulePath)
 	if name == "" {
 		name = packageName
 	}
-	packageUML := fmt.Sprintf("\n\nContainer_Boundary(%s, %q) {\n", name, name)
+	serviceLabelIgnorePrefix := modulePath
+	if modulePath != name {
+		serviceLabelIgnorePrefix = modulePath + "/" + name
+	}
+	return g.handleBoundary(writer, name, name, serviceLabelIgnorePrefix, services, externalRelations, graph, cycleEdges, modulePath)
+}
+
+// handleBoundary writes a Container_Boundary holding the services and returns the relations of those services.
+func (g Generator) handleBoundary(writer *bufio.Writer, boundaryID, boundaryLabel, serviceLabelIgnorePrefix string, services []*parse.Node, externalRelations map[string]string, graph *parse.Graph, cycleEdges map[*parse.Node][]*parse.Adj, modulePath string) (string, error) {
+	packageUML := fmt.Sprintf("\n\nContainer_Boundary(%s, %q) {\n", boundaryID, boundaryLabel)
 	relations := ""
 	sort.SliceStable(services, func(i, j int) bool {
 		return services[i].Name < services[j].Name
 	})
 	for _, service := range services {
-		serviceLabelIgnorePrefix := modulePath
-		if modulePath != name {
-			serviceLabelIgnorePrefix = modulePath + "/" + name
-		}
 		serviceLabel := g.getServiceLabel(service, serviceLabelIgnorePrefix)
 		serviceID := g.getServiceID(service, modulePath)
-		packageUML += fmt.Sprintf("Component(%s, %s, \"\", %q)\n", serviceID, serviceLabel, service.Doc)
+		technology := ""
+		if service.Instances > 1 {
+			technology = fmt.Sprintf("%d instances", service.Instances)
+		}
+		packageUML += fmt.Sprintf("Component(%s, %s, %q, %q)\n", serviceID, serviceLabel, technology, service.Doc)
 
 		for _, d := range graph.GetAdjacenciesSortedByName(service) {
 			if d.Node.External {
-				externalRelations[strings.ReplaceAll(d.Node.PackageName, "/", umlSeparator)+"."+d.Node.StructName] += g.getRelation(serviceID, d, "")
+				externalRelations[strings.ReplaceAll(d.Node.PackageName, "/", umlSeparator)+"."+d.Node.StructName] += g.getRelation(serviceID, d, "", parse.IsCycleEdge(cycleEdges, service, d))
 				continue
 			}
-			relations += g.getRelation(serviceID, d, modulePath)
+			relations += g.getRelation(serviceID, d, modulePath, parse.IsCycleEdge(cycleEdges, service, d))
 		}
 	}
 	packageUML += "\n}\n"
@@ -143,15 +179,30 @@ func (g Generator) handlePackages(writer *bufio.Writer, packageName string, serv
 	return relations, nil
 }
 
-func (g Generator) getRelation(sourceServiceID string, d *parse.Adj, path string) (relations string) {
+// getRelation returns the relations of an edge, an edge part of a cycle is tagged to be drawn in red.
+func (g Generator) getRelation(sourceServiceID string, d *parse.Adj, path string, cycle bool) (relations string) {
+	var technologies []string
+	if d.Via != "" {
+		technologies = append(technologies, "via interface "+strings.TrimPrefix(g.trimPackageName(d.Via, path), "."))
+	}
+	if d.Optional {
+		technologies = append(technologies, "optional")
+	}
+	technology := ""
+	if len(technologies) > 0 {
+		technology = fmt.Sprintf(", %q", strings.Join(technologies, ", "))
+	}
+	if cycle {
+		technology += fmt.Sprintf(", $tags=%q", cycleTag)
+	}
 	if len(d.Func) == 0 {
-		return fmt.Sprintf("Rel(%s, %s, %s)\n", sourceServiceID, g.getServiceID(d.Node, path), g.getServiceLabel(d.Node, path))
+		return fmt.Sprintf("Rel(%s, %s, %s%s)\n", sourceServiceID, g.getServiceID(d.Node, path), g.getServiceLabel(d.Node, path), technology)
 	}
 	sort.SliceStable(d.Func, func(i, j int) bool {
 		return d.Func[i] < d.Func[j]
 	})
 	for _, fn := range d.Func {
-		relations += fmt.Sprintf("Rel(%s, %s, %q)\n", sourceServiceID, g.getServiceID(d.Node, path), fn)
+		relations += fmt.Sprintf("Rel(%s, %s, %q%s)\n", sourceServiceID, g.getServiceID(d.Node, path), fn, technology)
 	}
 	return relations
 }
@@ -216,3 +216,225 @@ Rel("ext_dep_A", "net_http_Client", "net/http.Client")
 
 @enduml`, file.String())
 }
+
+func TestGenerateUmlFileFromSchema_via_interface(t *testing.T) {
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+
+	graph := parse.NewGraph()
+	a := &parse.Node{
+		Name:        "testdata/interface_param.A",
+		PackageName: "testdata/interface_param",
+		StructName:  "A",
+	}
+	graph.AddNode(a)
+	repo := &parse.Node{
+		Name:        "testdata/interface_param.SQLRepository",
+		PackageName: "testdata/interface_param",
+		StructName:  "SQLRepository",
+	}
+	graph.AddNode(repo)
+	graph.AddEdge(a, &parse.Adj{Node: repo, Func: []string{"Find"}, Via: "testdata/interface_param.UserRepository"})
+
+	err := NewGenerator().GenerateFromSchema(context.Background(), buff, parse.AstSchema{
+		ModulePath: "testdata/interface_param",
+		Graph:      graph,
+	})
+	buff.Flush()
+	assert.NoError(t, err)
+
+	assert.Equal(t, `@startuml
+!include https://raw.githubusercontent.com/plantuml-stdlib/C4-PlantUML/master/C4_Component.puml
+
+title testdata/interface_param
+
+Container_Boundary(testdata/interface_param, "testdata/interface_param") {
+Component("A", "A", "", "")
+Component("SQLRepository", "SQLRepository", "", "")
+
+}
+Rel("A", "SQLRepository", "Find", "via interface UserRepository")
+
+@enduml`, file.String())
+}
+
+func TestGenerateUmlFileFromSchema_module(t *testing.T) {
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+
+	graph := parse.NewGraph()
+	server := &parse.Node{
+		Name:        "testdata/fx_sample.Server",
+		PackageName: "testdata/fx_sample",
+		StructName:  "Server",
+	}
+	graph.AddNode(server)
+	repository := &parse.Node{
+		Name:        "testdata/fx_sample/user.Repository",
+		PackageName: "testdata/fx_sample/user",
+		StructName:  "Repository",
+		Module:      "user",
+	}
+	graph.AddNode(repository)
+	graph.AddEdge(server, &parse.Adj{Node: repository, Func: []string{"Find"}})
+
+	err := NewGenerator().GenerateFromSchema(context.Background(), buff, parse.AstSchema{
+		ModulePath: "testdata/fx_sample",
+		Graph:      graph,
+	})
+	buff.Flush()
+	assert.NoError(t, err)
+
+	assert.Equal(t, `@startuml
+!include https://raw.githubusercontent.com/plantuml-stdlib/C4-PlantUML/master/C4_Component.puml
+
+title testdata/fx_sample
+
+Container_Boundary(testdata/fx_sample, "testdata/fx_sample") {
+Component("Server", "Server", "", "")
+
+}
+
+
+Container_Boundary(module_user, "user") {
+Component("user_Repository", "user.Repository", "", "")
+
+}
+Rel("Server", "user_Repository", "Find")
+
+@enduml`, file.String())
+}
+
+func TestGenerateUmlFileFromSchema_optional(t *testing.T) {
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+
+	graph := parse.NewGraph()
+	server := &parse.Node{
+		Name:        "testdata/options.Server",
+		PackageName: "testdata/options",
+		StructName:  "Server",
+	}
+	graph.AddNode(server)
+	metrics := &parse.Node{
+		Name:        "testdata/options.Metrics",
+		PackageName: "testdata/options",
+		StructName:  "Metrics",
+	}
+	graph.AddNode(metrics)
+	logger := &parse.Node{
+		Name:        "testdata/options.StdLogger",
+		PackageName: "testdata/options",
+		StructName:  "StdLogger",
+	}
+	graph.AddNode(logger)
+	graph.AddEdge(server, &parse.Adj{Node: metrics, Func: []string{"Inc"}, Optional: true})
+	graph.AddEdge(server, &parse.Adj{Node: logger, Func: []string{"Log"}, Via: "testdata/options.Logger", Optional: true})
+
+	err := NewGenerator().GenerateFromSchema(context.Background(), buff, parse.AstSchema{
+		ModulePath: "testdata/options",
+		Graph:      graph,
+	})
+	buff.Flush()
+	assert.NoError(t, err)
+
+	assert.Equal(t, `@startuml
+!include https://raw.githubusercontent.com/plantuml-stdlib/C4-PlantUML/master/C4_Component.puml
+
+title testdata/options
+
+Container_Boundary(testdata/options, "testdata/options") {
+Component("Metrics", "Metrics", "", "")
+Component("Server", "Server", "", "")
+Component("StdLogger", "StdLogger", "", "")
+
+}
+Rel("Server", "Metrics", "Inc", "optional")
+Rel("Server", "StdLogger", "Log", "via interface Logger, optional")
+
+@enduml`, file.String())
+}
+
+func TestGenerateUmlFileFromSchema_instances(t *testing.T) {
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+
+	graph := parse.NewGraph()
+	handler := &parse.Node{
+		Name:        "testdata/composition/service.Handler",
+		PackageName: "testdata/composition/service",
+		StructName:  "Handler",
+		Instances:   1,
+	}
+	graph.AddNode(handler)
+	service := &parse.Node{
+		Name:        "testdata/composition/service.Service",
+		PackageName: "testdata/composition/service",
+		StructName:  "Service",
+		Instances:   2,
+	}
+	graph.AddNode(service)
+	graph.AddEdge(handler, &parse.Adj{Node: service, Func: []string{"Run"}})
+
+	err := NewGenerator().GenerateFromSchema(context.Background(), buff, parse.AstSchema{
+		ModulePath: "testdata/composition",
+		Graph:      graph,
+	})
+	buff.Flush()
+	assert.NoError(t, err)
+
+	assert.Equal(t, `@startuml
+!include https://raw.githubusercontent.com/plantuml-stdlib/C4-PlantUML/master/C4_Component.puml
+
+title testdata/composition
+
+Container_Boundary(service, "service") {
+Component("service_Handler", "Handler", "", "")
+Component("service_Service", "Service", "2 instances", "")
+
+}
+Rel("service_Handler", "service_Service", "Run")
+
+@enduml`, file.String())
+}
+
+func TestGenerateUmlFileFromSchema_cycle(t *testing.T) {
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+
+	graph := parse.NewGraph()
+	a := &parse.Node{Name: "testdata/cycle.A", PackageName: "testdata/cycle", StructName: "A"}
+	graph.AddNode(a)
+	b := &parse.Node{Name: "testdata/cycle.B", PackageName: "testdata/cycle", StructName: "B"}
+	graph.AddNode(b)
+	c := &parse.Node{Name: "testdata/cycle.C", PackageName: "testdata/cycle", StructName: "C"}
+	graph.AddNode(c)
+	graph.AddEdge(a, &parse.Adj{Node: b, Func: []string{"FuncB"}})
+	graph.AddEdge(b, &parse.Adj{Node: a, Func: []string{"FuncA"}, Via: "testdata/cycle.I"})
+	graph.AddEdge(b, &parse.Adj{Node: c})
+
+	err := NewGenerator().GenerateFromSchema(context.Background(), buff, parse.AstSchema{
+		ModulePath: "testdata/cycle",
+		Graph:      graph,
+	})
+	buff.Flush()
+	assert.NoError(t, err)
+
+	assert.Equal(t, `@startuml
+!include https://raw.githubusercontent.com/plantuml-stdlib/C4-PlantUML/master/C4_Component.puml
+
+title testdata/cycle
+AddRelTag("cycle", $textColor="red", $lineColor="red", $legendText="dependency cycle")
+
+Container_Boundary(testdata/cycle, "testdata/cycle") {
+Component("A", "A", "", "")
+Component("B", "B", "", "")
+Component("C", "C", "", "")
+
+}
+Rel("A", "B", "FuncB", $tags="cycle")
+Rel("B", "A", "FuncA", "via interface I", $tags="cycle")
+Rel("B", "C", "C")
+
+@enduml`, file.String())
+}
@@ -0,0 +1,267 @@
+package c4
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	mymap "github.com/emilien-puget/go-dependency-graph/pkg/map"
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+)
+
+const defaultTechnology = "Go"
+
+var errUnknownContainer = errors.New("unknown container, not a main package of the module")
+
+// ContainerGenerator generates a C4 plantuml container diagram, a container per main package of the module grouping
+// the components of the packages it is built from, and the external systems the components use.
+// A module without main package is drawn as a single container.
+type ContainerGenerator struct {
+	metadata Metadata
+	replacer *strings.Replacer
+}
+
+// NewContainerGenerator returns a generator of a container diagram, the names, technologies and descriptions of the
+// containers are read from the metadata.
+func NewContainerGenerator(metadata Metadata) *ContainerGenerator {
+	return &ContainerGenerator{
+		metadata: metadata,
+		replacer: newIDReplacer(),
+	}
+}
+
+func (g ContainerGenerator) GetDefaultResultFileName() string {
+	return "container.puml"
+}
+
+func (g ContainerGenerator) GenerateFromSchema(ctx context.Context, writer *bufio.Writer, s parse.AstSchema) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	sys, err := buildSystem(s, g.metadata, g.replacer)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString("@startuml\n!include https://raw.githubusercontent.com/plantuml-stdlib/C4-PlantUML/master/C4_Container.puml\n")
+	b.WriteString("\ntitle " + sys.name + "\n\n")
+	writePersons(&b, sys)
+	fmt.Fprintf(&b, "System_Boundary(%s, %q) {\n", systemID, sys.name)
+	for _, c := range sys.containers {
+		fmt.Fprintf(&b, "Container(%s, %q, %q, %q)\n", c.id, c.name, c.technology, c.description)
+	}
+	b.WriteString("}\n\n")
+	writeExternalSystems(&b, sys)
+	for _, p := range sys.persons {
+		for _, c := range p.uses {
+			fmt.Fprintf(&b, "Rel(%s, %s, \"Uses\")\n", p.id, c.id)
+		}
+	}
+	for _, c := range sys.containers {
+		for _, e := range sys.externalSystems {
+			if types := e.uses[c]; len(types) > 0 {
+				fmt.Fprintf(&b, "Rel(%s, %s, \"Uses\", %q)\n", c.id, e.id, strings.Join(types, ", "))
+			}
+		}
+	}
+	b.WriteString("\n@enduml")
+
+	_, err = writer.WriteString(b.String())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+const systemID = "system"
+
+// system is the model shared by the container and context diagrams.
+type system struct {
+	name            string
+	description     string
+	containers      []*container
+	externalSystems []*externalSystem
+	persons         []person
+}
+
+type container struct {
+	id          string
+	name        string
+	technology  string
+	description string
+}
+
+type externalSystem struct {
+	ExternalSystem
+	id   string
+	uses map[*container][]string // The external dependencies used by the components of each container, sorted
+}
+
+type person struct {
+	Person
+	id   string
+	uses []*container
+}
+
+// buildSystem groups the components of the schema in a container per main package, and attributes their external
+// dependencies to the external systems of the metadata, then to the well known ones, the others are ignored.
+func buildSystem(s parse.AstSchema, metadata Metadata, replacer *strings.Replacer) (system, error) {
+	sys := system{name: metadata.System.Name, description: metadata.System.Description}
+	if sys.name == "" {
+		sys.name = s.ModulePath
+	}
+
+	binaries := s.Binaries
+	if len(binaries) == 0 {
+		var packages []string
+		for packageName := range s.Graph.NodesByPackage {
+			packages = append(packages, packageName)
+		}
+		binaries = []parse.Binary{{Package: s.ModulePath, Packages: packages}}
+	}
+
+	byKey := make(map[string]*container)
+	for _, binary := range binaries {
+		relative := strings.TrimPrefix(strings.TrimPrefix(binary.Package, s.ModulePath), "/")
+		key := binary.Package
+		if relative != "" {
+			key = relative
+		}
+		c := &container{id: replacer.Replace(key), name: path.Base(binary.Package), technology: defaultTechnology}
+		byKey[binary.Package] = c
+		if relative != "" {
+			byKey[relative] = c
+		}
+		sys.containers = append(sys.containers, c)
+
+		components := 0
+		for _, packageName := range binary.Packages {
+			for _, node := range s.Graph.NodesByPackage[packageName] {
+				if !node.External {
+					components++
+				}
+			}
+		}
+		c.description = fmt.Sprintf("%d components", components)
+		if components == 1 {
+			c.description = "1 component"
+		}
+	}
+
+	for _, key := range mymap.OrderedKeys(metadata.Containers) {
+		c, ok := byKey[key]
+		if !ok {
+			return system{}, fmt.Errorf("%s: %w", key, errUnknownContainer)
+		}
+		meta := metadata.Containers[key]
+		if meta.Name != "" {
+			c.name = meta.Name
+		}
+		if meta.Technology != "" {
+			c.technology = meta.Technology
+		}
+		if meta.Description != "" {
+			c.description = meta.Description
+		}
+	}
+
+	for _, p := range metadata.Persons {
+		sp := person{Person: p, id: "person_" + replacer.Replace(p.Name)}
+		for _, key := range p.Uses {
+			c, ok := byKey[key]
+			if !ok {
+				return system{}, fmt.Errorf("%s: %s: %w", p.Name, key, errUnknownContainer)
+			}
+			sp.uses = append(sp.uses, c)
+		}
+		sys.persons = append(sys.persons, sp)
+	}
+
+	sys.externalSystems = searchExternalSystems(s, binaries, byKey, append(compileExternalSystems(metadata.ExternalSystems), compileExternalSystems(defaultExternalSystems)...), replacer)
+	return sys, nil
+}
+
+// searchExternalSystems returns the external systems used by the containers, sorted by name.
+func searchExternalSystems(s parse.AstSchema, binaries []parse.Binary, byKey map[string]*container, matchers []externalSystemMatcher, replacer *strings.Replacer) []*externalSystem {
+	byName := make(map[string]*externalSystem)
+	for _, binary := range binaries {
+		c := byKey[binary.Package]
+		used := make(map[*externalSystem]map[string]bool)
+		for _, packageName := range binary.Packages {
+			for _, node := range s.Graph.NodesByPackage[packageName] {
+				if node.External {
+					continue
+				}
+				for _, adj := range s.Graph.GetAdjacenciesSortedByName(node) {
+					if !adj.Node.External {
+						continue
+					}
+					e := getExternalSystem(adj.Node.PackageName, matchers, byName, replacer)
+					if e == nil {
+						continue
+					}
+					if used[e] == nil {
+						used[e] = make(map[string]bool)
+					}
+					used[e][path.Base(adj.Node.PackageName)+"."+adj.Node.StructName] = true
+				}
+			}
+		}
+		for e, types := range used {
+			e.uses[c] = mymap.OrderedKeys(types)
+		}
+	}
+
+	systems := make([]*externalSystem, 0, len(byName))
+	for _, name := range mymap.OrderedKeys(byName) {
+		systems = append(systems, byName[name])
+	}
+	return systems
+}
+
+func getExternalSystem(packageName string, matchers []externalSystemMatcher, byName map[string]*externalSystem, replacer *strings.Replacer) *externalSystem {
+	for _, m := range matchers {
+		if !m.match(packageName) {
+			continue
+		}
+		e, ok := byName[m.Name]
+		if !ok {
+			e = &externalSystem{ExternalSystem: m.ExternalSystem, id: "ext_" + replacer.Replace(m.Name), uses: make(map[*container][]string)}
+			byName[m.Name] = e
+		}
+		return e
+	}
+	return nil
+}
+
+func writePersons(b *strings.Builder, sys system) {
+	for _, p := range sys.persons {
+		fmt.Fprintf(b, "Person(%s, %q, %q)\n", p.id, p.Name, p.Description)
+	}
+	if len(sys.persons) > 0 {
+		b.WriteString("\n")
+	}
+}
+
+func writeExternalSystems(b *strings.Builder, sys system) {
+	for _, e := range sys.externalSystems {
+		macro := "System_Ext"
+		if e.Database {
+			macro = "SystemDb_Ext"
+		}
+		fmt.Fprintf(b, "%s(%s, %q, %q)\n", macro, e.id, e.Name, e.Description)
+	}
+	if len(sys.externalSystems) > 0 {
+		b.WriteString("\n")
+	}
+}
+
+func newIDReplacer() *strings.Replacer {
+	return strings.NewReplacer(".", umlSeparator, "-", umlSeparator, "/", umlSeparator, " ", umlSeparator)
+}
@@ -0,0 +1,132 @@
+package c4
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// getSystemSchema returns a module built into an api and a worker, both saving through a store using a SQL database.
+func getSystemSchema() parse.AstSchema {
+	graph := parse.NewGraph()
+	handler := &parse.Node{Name: "example.com/orders/api.Handler", PackageName: "example.com/orders/api", StructName: "Handler"}
+	store := &parse.Node{Name: "example.com/orders/store.Store", PackageName: "example.com/orders/store", StructName: "Store"}
+	worker := &parse.Node{Name: "example.com/orders/worker.Worker", PackageName: "example.com/orders/worker", StructName: "Worker"}
+	db := &parse.Node{Name: "database/sql.DB", PackageName: "database/sql", StructName: "DB", External: true}
+	client := &parse.Node{Name: "net/http.Client", PackageName: "net/http", StructName: "Client", External: true}
+	logger := &parse.Node{Name: "go.uber.org/zap.Logger", PackageName: "go.uber.org/zap", StructName: "Logger", External: true}
+	payments := &parse.Node{Name: "github.com/acme/payments.Client", PackageName: "github.com/acme/payments", StructName: "Client", External: true}
+	for _, node := range []*parse.Node{handler, store, worker, db, client, logger, payments} {
+		graph.AddNode(node)
+	}
+	graph.AddEdge(handler, &parse.Adj{Node: store, Func: []string{"Save"}})
+	graph.AddEdge(handler, &parse.Adj{Node: client, Func: []string{"Do"}})
+	graph.AddEdge(handler, &parse.Adj{Node: payments, Func: []string{"Charge"}})
+	graph.AddEdge(handler, &parse.Adj{Node: logger})
+	graph.AddEdge(store, &parse.Adj{Node: db, Func: []string{"Exec"}})
+	graph.AddEdge(worker, &parse.Adj{Node: store, Func: []string{"Save"}})
+
+	return parse.AstSchema{
+		ModulePath: "example.com/orders",
+		Graph:      graph,
+		Binaries: []parse.Binary{
+			{Package: "example.com/orders/cmd/api", Packages: []string{"example.com/orders/api", "example.com/orders/cmd/api", "example.com/orders/store"}},
+			{Package: "example.com/orders/cmd/worker", Packages: []string{"example.com/orders/cmd/worker", "example.com/orders/store", "example.com/orders/worker"}},
+		},
+	}
+}
+
+func getSystemMetadata() Metadata {
+	return Metadata{
+		System: System{Name: "Orders", Description: "Takes the orders"},
+		Containers: map[string]Container{
+			"cmd/api":                       {Name: "Orders API", Technology: "Go, net/http", Description: "Serves the orders"},
+			"example.com/orders/cmd/worker": {Description: "Retries the orders"},
+		},
+		ExternalSystems: []ExternalSystem{
+			{Name: "Payments", Description: "Charges the customers", Packages: []string{"github.com/acme/**"}},
+		},
+		Persons: []Person{
+			{Name: "Customer", Description: "Orders things", Uses: []string{"cmd/api"}},
+		},
+	}
+}
+
+func TestContainerGenerator_GenerateFromSchema(t *testing.T) {
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+	err := NewContainerGenerator(getSystemMetadata()).GenerateFromSchema(context.Background(), buff, getSystemSchema())
+	require.NoError(t, err)
+	buff.Flush()
+
+	assert.Equal(t, `@startuml
+!include https://raw.githubusercontent.com/plantuml-stdlib/C4-PlantUML/master/C4_Container.puml
+
+title Orders
+
+Person(person_Customer, "Customer", "Orders things")
+
+System_Boundary(system, "Orders") {
+Container(cmd_api, "Orders API", "Go, net/http", "Serves the orders")
+Container(cmd_worker, "worker", "Go", "Retries the orders")
+}
+
+System_Ext(ext_HTTP_APIs, "HTTP APIs", "")
+System_Ext(ext_Payments, "Payments", "Charges the customers")
+SystemDb_Ext(ext_SQL_database, "SQL database", "")
+
+Rel(person_Customer, cmd_api, "Uses")
+Rel(cmd_api, ext_HTTP_APIs, "Uses", "http.Client")
+Rel(cmd_api, ext_Payments, "Uses", "payments.Client")
+Rel(cmd_api, ext_SQL_database, "Uses", "sql.DB")
+Rel(cmd_worker, ext_SQL_database, "Uses", "sql.DB")
+
+@enduml`, file.String())
+}
+
+func TestContainerGenerator_GenerateFromSchema_withoutBinaries(t *testing.T) {
+	s := getSystemSchema()
+	s.Binaries = nil
+
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+	err := NewContainerGenerator(Metadata{}).GenerateFromSchema(context.Background(), buff, s)
+	require.NoError(t, err)
+	buff.Flush()
+
+	assert.Equal(t, `@startuml
+!include https://raw.githubusercontent.com/plantuml-stdlib/C4-PlantUML/master/C4_Container.puml
+
+title example.com/orders
+
+System_Boundary(system, "example.com/orders") {
+Container(example_com_orders, "orders", "Go", "3 components")
+}
+
+System_Ext(ext_HTTP_APIs, "HTTP APIs", "")
+SystemDb_Ext(ext_SQL_database, "SQL database", "")
+
+Rel(example_com_orders, ext_HTTP_APIs, "Uses", "http.Client")
+Rel(example_com_orders, ext_SQL_database, "Uses", "sql.DB")
+
+@enduml`, file.String())
+}
+
+func TestContainerGenerator_GenerateFromSchema_unknownContainer(t *testing.T) {
+	metadata := getSystemMetadata()
+	metadata.Persons[0].Uses = []string{"cmd/cli"}
+
+	err := NewContainerGenerator(metadata).GenerateFromSchema(context.Background(), bufio.NewWriter(&bytes.Buffer{}), getSystemSchema())
+	require.ErrorIs(t, err, errUnknownContainer)
+	assert.EqualError(t, err, "Customer: cmd/cli: unknown container, not a main package of the module")
+
+	metadata = getSystemMetadata()
+	metadata.Containers["store"] = Container{Name: "Store"}
+	err = NewContainerGenerator(metadata).GenerateFromSchema(context.Background(), bufio.NewWriter(&bytes.Buffer{}), getSystemSchema())
+	require.ErrorIs(t, err, errUnknownContainer)
+}
@@ -0,0 +1,74 @@
+package c4
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+
+	mymap "github.com/emilien-puget/go-dependency-graph/pkg/map"
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+)
+
+// ContextGenerator generates a C4 plantuml context diagram, the system built by the module, its users and the
+// external systems it uses, as inferred for the container diagram.
+type ContextGenerator struct {
+	metadata Metadata
+	replacer *strings.Replacer
+}
+
+// NewContextGenerator returns a generator of a context diagram, the names of the system, of its users and of the
+// external systems are read from the metadata.
+func NewContextGenerator(metadata Metadata) *ContextGenerator {
+	return &ContextGenerator{
+		metadata: metadata,
+		replacer: newIDReplacer(),
+	}
+}
+
+func (g ContextGenerator) GetDefaultResultFileName() string {
+	return "context.puml"
+}
+
+func (g ContextGenerator) GenerateFromSchema(ctx context.Context, writer *bufio.Writer, s parse.AstSchema) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	sys, err := buildSystem(s, g.metadata, g.replacer)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString("@startuml\n!include https://raw.githubusercontent.com/plantuml-stdlib/C4-PlantUML/master/C4_Context.puml\n")
+	b.WriteString("\ntitle " + sys.name + "\n\n")
+	writePersons(&b, sys)
+	fmt.Fprintf(&b, "System(%s, %q, %q)\n\n", systemID, sys.name, sys.description)
+	writeExternalSystems(&b, sys)
+	for _, p := range sys.persons {
+		fmt.Fprintf(&b, "Rel(%s, %s, \"Uses\")\n", p.id, systemID)
+	}
+	for _, e := range sys.externalSystems {
+		fmt.Fprintf(&b, "Rel(%s, %s, \"Uses\", %q)\n", systemID, e.id, strings.Join(e.getTypes(), ", "))
+	}
+	b.WriteString("\n@enduml")
+
+	_, err = writer.WriteString(b.String())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// getTypes returns the external dependencies of the system used by any container, sorted.
+func (e externalSystem) getTypes() []string {
+	types := make(map[string]bool)
+	for _, used := range e.uses {
+		for _, t := range used {
+			types[t] = true
+		}
+	}
+	return mymap.OrderedKeys(types)
+}
@@ -0,0 +1,39 @@
+package c4
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextGenerator_GenerateFromSchema(t *testing.T) {
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+	err := NewContextGenerator(getSystemMetadata()).GenerateFromSchema(context.Background(), buff, getSystemSchema())
+	require.NoError(t, err)
+	buff.Flush()
+
+	assert.Equal(t, `@startuml
+!include https://raw.githubusercontent.com/plantuml-stdlib/C4-PlantUML/master/C4_Context.puml
+
+title Orders
+
+Person(person_Customer, "Customer", "Orders things")
+
+System(system, "Orders", "Takes the orders")
+
+System_Ext(ext_HTTP_APIs, "HTTP APIs", "")
+System_Ext(ext_Payments, "Payments", "Charges the customers")
+SystemDb_Ext(ext_SQL_database, "SQL database", "")
+
+Rel(person_Customer, system, "Uses")
+Rel(system, ext_HTTP_APIs, "Uses", "http.Client")
+Rel(system, ext_Payments, "Uses", "payments.Client")
+Rel(system, ext_SQL_database, "Uses", "sql.DB")
+
+@enduml`, file.String())
+}
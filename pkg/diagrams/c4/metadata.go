@@ -0,0 +1,106 @@
+package c4
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/glob"
+	"gopkg.in/yaml.v3"
+)
+
+// Metadata describes the system drawn by the container and context diagrams, what the sources can not tell.
+type Metadata struct {
+	System          System               `json:"system" yaml:"system"`
+	Containers      map[string]Container `json:"containers,omitempty" yaml:"containers,omitempty"` // By the path of the main package, or the path relative to the module, such as cmd/api
+	ExternalSystems []ExternalSystem     `json:"external_systems,omitempty" yaml:"external_systems,omitempty"`
+	Persons         []Person             `json:"persons,omitempty" yaml:"persons,omitempty"`
+}
+
+// System is the software system built by the module.
+type System struct {
+	Name        string `json:"name" yaml:"name"` // The module path when empty
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// Container is a deployable unit, a main package of the module.
+type Container struct {
+	Name        string `json:"name,omitempty" yaml:"name,omitempty"`             // The last element of the package path when empty
+	Technology  string `json:"technology,omitempty" yaml:"technology,omitempty"` // Go when empty
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// ExternalSystem is a system the containers use through the external dependencies of their components.
+type ExternalSystem struct {
+	Name        string   `json:"name" yaml:"name"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Packages    []string `json:"packages" yaml:"packages"`                     // Globs matched against the package of the external dependencies, ** matches several path elements
+	Database    bool     `json:"database,omitempty" yaml:"database,omitempty"` // The system is drawn as a database
+}
+
+// Person is a user of the system.
+type Person struct {
+	Name        string   `json:"name" yaml:"name"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Uses        []string `json:"uses,omitempty" yaml:"uses,omitempty"` // The containers used, as the keys of Containers, the context diagram links the person to the system
+}
+
+// defaultExternalSystems are the systems inferred from well known external dependencies, after the ones of the metadata.
+var defaultExternalSystems = []ExternalSystem{
+	{Name: "SQL database", Packages: []string{"database/sql", "github.com/jmoiron/sqlx", "gorm.io/gorm", "github.com/jackc/pgx/**"}, Database: true},
+	{Name: "MongoDB", Packages: []string{"go.mongodb.org/mongo-driver/**"}, Database: true},
+	{Name: "Redis", Packages: []string{"github.com/redis/go-redis/**", "github.com/go-redis/redis/**"}, Database: true},
+	{Name: "HTTP APIs", Packages: []string{"net/http"}},
+	{Name: "gRPC services", Packages: []string{"google.golang.org/grpc"}},
+}
+
+// LoadMetadata reads a metadata file, a .json file is read as JSON, any other as YAML.
+func LoadMetadata(path string) (Metadata, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("os.ReadFile:%w", err)
+	}
+	m := Metadata{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(content, &m)
+		if err != nil {
+			return Metadata{}, fmt.Errorf("json.Unmarshal:%w", err)
+		}
+		return m, nil
+	}
+	err = yaml.Unmarshal(content, &m)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("yaml.Unmarshal:%w", err)
+	}
+	return m, nil
+}
+
+// externalSystemMatcher is a compiled ExternalSystem.
+type externalSystemMatcher struct {
+	ExternalSystem
+	packages []*regexp.Regexp
+}
+
+func compileExternalSystems(systems []ExternalSystem) []externalSystemMatcher {
+	matchers := make([]externalSystemMatcher, 0, len(systems))
+	for _, system := range systems {
+		m := externalSystemMatcher{ExternalSystem: system}
+		for _, pattern := range system.Packages {
+			m.packages = append(m.packages, glob.Compile(pattern))
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers
+}
+
+func (m externalSystemMatcher) match(packageName string) bool {
+	for _, re := range m.packages {
+		if re.MatchString(packageName) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package c4
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMetadata(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "c4.yaml")
+	require.NoError(t, os.WriteFile(yamlPath, []byte(`system:
+  name: Orders
+containers:
+  cmd/api:
+    technology: Go, net/http
+external_systems:
+  - name: Orders database
+    packages: [ database/sql ]
+    database: true
+persons:
+  - name: Customer
+    uses: [ cmd/api ]
+`), 0o600))
+	jsonPath := filepath.Join(dir, "c4.json")
+	require.NoError(t, os.WriteFile(jsonPath, []byte(`{"system": {"name": "Orders"}, "containers": {"cmd/api": {"technology": "Go, net/http"}}, "external_systems": [{"name": "Orders database", "packages": ["database/sql"], "database": true}], "persons": [{"name": "Customer", "uses": ["cmd/api"]}]}`), 0o600))
+
+	expected := Metadata{
+		System:          System{Name: "Orders"},
+		Containers:      map[string]Container{"cmd/api": {Technology: "Go, net/http"}},
+		ExternalSystems: []ExternalSystem{{Name: "Orders database", Packages: []string{"database/sql"}, Database: true}},
+		Persons:         []Person{{Name: "Customer", Uses: []string{"cmd/api"}}},
+	}
+	for _, path := range []string{yamlPath, jsonPath} {
+		m, err := LoadMetadata(path)
+		require.NoError(t, err)
+		assert.Equal(t, expected, m)
+	}
+
+	_, err := LoadMetadata(filepath.Join(dir, "missing.yaml"))
+	require.Error(t, err)
+}
+
+func TestExternalSystemMatcher_match(t *testing.T) {
+	matchers := compileExternalSystems([]ExternalSystem{{Name: "Redis", Packages: []string{"github.com/redis/go-redis/**", "example.com/*/cache"}}})
+
+	assert.True(t, matchers[0].match("github.com/redis/go-redis"))
+	assert.True(t, matchers[0].match("github.com/redis/go-redis/v9"))
+	assert.True(t, matchers[0].match("example.com/orders/cache"))
+	assert.False(t, matchers[0].match("github.com/redis/go-redis-mock"))
+	assert.False(t, matchers[0].match("example.com/orders/internal/cache"))
+}
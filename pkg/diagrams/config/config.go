@@ -0,0 +1,13 @@
+package config
+
+// Config holds the options of the diagram generators, each generator uses the options it supports.
+type Config struct {
+	RankDir         string // The direction of the layout, TB, LR, BT or RL, the default of the generator when empty
+	DisableClusters bool   // The nodes are not grouped by package
+	LinkBase        string // The URL the source files, relative to the project, are appended to in the links to the sources
+	DSMLevel        string // The rows of the dependency structure matrix, package or component, package when empty
+	DSMValue        string // What the cells of the dependency structure matrix count, edges or funcs, edges when empty
+	SequenceEntry   string // The method the sequence diagram starts from, such as handlers.OrderHandler.Create
+	SequenceDepth   int    // The number of dependencies whose calls the sequence diagram follows, the default when 0
+	C4Metadata      string // The path of the metadata file of the C4 container and context diagrams, optional
+}
@@ -0,0 +1,187 @@
+package d2
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	mymap "github.com/emilien-puget/go-dependency-graph/pkg/map"
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse/struct_decl"
+)
+
+// directions maps the rank directions to the d2 directions.
+var directions = map[string]string{
+	"TB": "down",
+	"LR": "right",
+	"BT": "up",
+	"RL": "left",
+}
+
+type Generator struct {
+	direction string
+	replacer  *strings.Replacer
+}
+
+// NewGenerator returns a generator laying out the graph in the rank direction, TB, LR, BT or RL, default LR.
+func NewGenerator(rankDir string) *Generator {
+	direction, ok := directions[rankDir]
+	if !ok {
+		direction = directions["LR"]
+	}
+	return &Generator{
+		direction: direction,
+		replacer:  strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`),
+	}
+}
+
+func (g Generator) GetDefaultResultFileName() string {
+	return "diag.d2"
+}
+
+// GenerateFromSchema generates a d2 diagram, the packages are containers holding a class shape per component.
+func (g Generator) GenerateFromSchema(ctx context.Context, writer *bufio.Writer, s parse.AstSchema) error {
+	_, err := fmt.Fprintf(writer, "direction: %s\n", g.direction)
+	if err != nil {
+		return err
+	}
+
+	containers := make(map[*parse.Node]string)
+	for _, packageName := range mymap.OrderedKeys(s.Graph.NodesByPackage) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		nodes := s.Graph.GetPackageNodesOutsideModule(packageName)
+		if len(nodes) == 0 {
+			continue
+		}
+		err = g.writeContainer(writer, packageName, parse.GetPackageLabel(packageName, s.ModulePath), nodes, containers)
+		if err != nil {
+			return err
+		}
+	}
+	nodesByModule := s.Graph.GetNodesByModule()
+	for _, module := range mymap.OrderedKeys(nodesByModule) {
+		err = g.writeContainer(writer, "module "+module, module, nodesByModule[module], containers)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = writer.WriteString("\n")
+	if err != nil {
+		return err
+	}
+	for _, node := range s.Graph.GetNodesSortedByName() {
+		for _, adj := range s.Graph.GetAdjacenciesSortedByName(node) {
+			err = g.writeEdge(writer, g.getID(containers, node), g.getID(containers, adj.Node), adj)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeContainer writes a container holding the nodes, and records the container of each node.
+func (g Generator) writeContainer(writer *bufio.Writer, id, label string, nodes []*parse.Node, containers map[*parse.Node]string) error {
+	_, err := fmt.Fprintf(writer, "\n%s: {\n  label: %s\n", g.quote(id), g.quote(label))
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+	for _, node := range nodes {
+		containers[node] = id
+		err = g.writeNode(writer, node)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = writer.WriteString("}\n")
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (g Generator) writeNode(writer *bufio.Writer, node *parse.Node) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n  %s: {\n    shape: class\n", g.quote(node.StructName))
+	if node.Doc != "" {
+		fmt.Fprintf(&b, "    tooltip: %s\n", g.quote(node.Doc))
+	}
+	if node.External {
+		b.WriteString("    style.stroke-dash: 3\n")
+	}
+	for _, method := range node.Methods {
+		b.WriteString("    " + g.getMethod(method) + "\n")
+	}
+	b.WriteString("  }\n")
+	_, err := writer.WriteString(b.String())
+	return err
+}
+
+// getMethod returns the class field of a method, its name and parameters as key and its results as value.
+func (g Generator) getMethod(method struct_decl.Method) string {
+	signature, results := splitSignature(method.String())
+	if results == "" {
+		return g.quote(signature)
+	}
+	return g.quote(signature) + ": " + g.quote(results)
+}
+
+// splitSignature splits a method signature after its parameters, which may hold parenthesis.
+func splitSignature(signature string) (string, string) {
+	depth := 0
+	for i, r := range signature {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return signature[:i+1], strings.TrimSpace(signature[i+1:])
+			}
+		}
+	}
+	return signature, ""
+}
+
+func (g Generator) writeEdge(writer *bufio.Writer, from, to string, adj *parse.Adj) error {
+	edge := from + " -> " + to
+	var attributes []string
+	if adj.Optional {
+		attributes = append(attributes, "style.stroke-dash: 3")
+	}
+	switch {
+	case len(attributes) > 0:
+		_, err := fmt.Fprintf(writer, "%s: %s {\n  %s\n}\n", edge, g.quote(strings.Join(adj.Func, ", ")), strings.Join(attributes, "\n  "))
+		return err
+	case len(adj.Func) > 0:
+		_, err := fmt.Fprintf(writer, "%s: %s\n", edge, g.quote(strings.Join(adj.Func, ", ")))
+		return err
+	default:
+		_, err := fmt.Fprintf(writer, "%s\n", edge)
+		return err
+	}
+}
+
+// getID returns the path of the shape of a node, its container and its name, quoted to escape the dots and slashes.
+func (g Generator) getID(containers map[*parse.Node]string, node *parse.Node) string {
+	container, ok := containers[node]
+	if !ok {
+		container = node.PackageName
+	}
+	return g.quote(container) + "." + g.quote(node.StructName)
+}
+
+// quote returns a d2 double-quoted string, a key between quotes is not split on its dots.
+func (g Generator) quote(s string) string {
+	return `"` + g.replacer.Replace(s) + `"`
+}
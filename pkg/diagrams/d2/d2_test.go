@@ -0,0 +1,127 @@
+package d2
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse/struct_decl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateFromSchema_withParse(t *testing.T) {
+	as, err := parse.Parse("../testdata/named_inter", nil)
+	require.NoError(t, err)
+
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+	err = NewGenerator("").GenerateFromSchema(context.Background(), buff, as)
+	require.NoError(t, err)
+	buff.Flush()
+
+	assert.Equal(t, `direction: right
+
+"testdata/named_inter": {
+  label: "testdata/named_inter"
+
+  "A": {
+    shape: class
+  }
+
+  "B": {
+    shape: class
+    "FuncA()"
+    "FuncB()"
+  }
+
+  "C": {
+    shape: class
+    "FuncA()"
+  }
+
+  "D": {
+    shape: class
+    "FuncA()"
+  }
+}
+
+"testdata/named_inter/pa": {
+  label: "pa"
+
+  "A": {
+    shape: class
+    tooltip: "A pa struct."
+    "FuncFoo(foo string)": "(bar int, err error)"
+  }
+}
+
+"testdata/named_inter"."A" -> "testdata/named_inter"."B": "FuncA, FuncB"
+"testdata/named_inter"."A" -> "testdata/named_inter"."D": "FuncA"
+"testdata/named_inter"."B" -> "testdata/named_inter"."C": "FuncA"
+"testdata/named_inter"."D" -> "testdata/named_inter/pa"."A": "FuncFoo"
+`, file.String())
+}
+
+func TestGenerateFromSchema_escaping(t *testing.T) {
+	graph := parse.NewGraph()
+	service := &parse.Node{
+		Name:        "mod/app.Service",
+		PackageName: "mod/app",
+		StructName:  "Service",
+		Doc:         "The \"main\" service.\nIt handles the requests.",
+		Methods: []struct_decl.Method{
+			{Signature: "Handle(fn func(ctx context.Context) error) (err error)"},
+		},
+	}
+	cache := &parse.Node{Name: "mod/app.Cache", PackageName: "mod/app", StructName: "Cache", Module: "storage.v1"}
+	client := &parse.Node{Name: "gopkg.in/http.v2.Client", PackageName: "gopkg.in/http.v2", StructName: "Client", External: true}
+	graph.AddNode(service)
+	graph.AddNode(cache)
+	graph.AddNode(client)
+	graph.AddEdge(service, &parse.Adj{Node: client, Func: []string{"Do", "Get"}})
+	graph.AddEdge(service, &parse.Adj{Node: cache, Optional: true})
+
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+	err := NewGenerator("TB").GenerateFromSchema(context.Background(), buff, parse.AstSchema{ModulePath: "mod", Graph: graph})
+	require.NoError(t, err)
+	buff.Flush()
+
+	assert.Equal(t, `direction: down
+
+"gopkg.in/http.v2": {
+  label: "gopkg.in/http.v2"
+
+  "Client": {
+    shape: class
+    style.stroke-dash: 3
+  }
+}
+
+"mod/app": {
+  label: "app"
+
+  "Service": {
+    shape: class
+    tooltip: "The \"main\" service.\nIt handles the requests."
+    "Handle(fn func(ctx context.Context) error)": "(err error)"
+  }
+}
+
+"module storage.v1": {
+  label: "storage.v1"
+
+  "Cache": {
+    shape: class
+  }
+}
+
+"mod/app"."Service" -> "gopkg.in/http.v2"."Client": "Do, Get"
+"mod/app"."Service" -> "module storage.v1"."Cache": "" {
+  style.stroke-dash: 3
+}
+`, file.String())
+}
@@ -0,0 +1,70 @@
+package dsm
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+)
+
+// CSVGenerator generates the matrix as CSV, a row per package or component, followed by its partition, whether the
+// partition is a cycle, and its cells, an empty cell has no dependency.
+type CSVGenerator struct {
+	level Level
+	value Value
+}
+
+// NewCSVGenerator returns a generator of the matrix at the level, default package, counting the value, default edges.
+func NewCSVGenerator(level Level, value Value) *CSVGenerator {
+	return &CSVGenerator{level: getLevel(level), value: getValue(value)}
+}
+
+func (g CSVGenerator) GetDefaultResultFileName() string {
+	return "dsm.csv"
+}
+
+func (g CSVGenerator) GenerateFromSchema(ctx context.Context, writer *bufio.Writer, s parse.AstSchema) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	m := Build(s, g.level, g.value)
+
+	records := make([][]string, 0, len(m.Names)+1)
+	records = append(records, append([]string{string(g.level), "partition", "cycle"}, m.Names...))
+	for i, name := range m.Names {
+		record := make([]string, 0, len(m.Names)+3)
+		record = append(record, name, strconv.Itoa(m.Groups[i]+1), strconv.FormatBool(m.Cycles[m.Groups[i]]))
+		for _, cell := range m.Cells[i] {
+			if cell == 0 {
+				record = append(record, "")
+				continue
+			}
+			record = append(record, strconv.Itoa(cell))
+		}
+		records = append(records, record)
+	}
+	err := csv.NewWriter(writer).WriteAll(records)
+	if err != nil {
+		return fmt.Errorf("cw.WriteAll:%w", err)
+	}
+	return nil
+}
+
+func getLevel(level Level) Level {
+	if level == "" {
+		return LevelPackage
+	}
+	return level
+}
+
+func getValue(value Value) Value {
+	if value == "" {
+		return ValueEdges
+	}
+	return value
+}
@@ -0,0 +1,45 @@
+package dsm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVGenerator_withParse(t *testing.T) {
+	as, err := parse.Parse("../testdata/named_inter", nil)
+	require.NoError(t, err)
+
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+	err = NewCSVGenerator(LevelComponent, ValueFuncs).GenerateFromSchema(context.Background(), buff, as)
+	require.NoError(t, err)
+	buff.Flush()
+
+	assert.Equal(t, `component,partition,cycle,testdata/named_inter.C,testdata/named_inter.B,testdata/named_inter/pa.A,testdata/named_inter.D,testdata/named_inter.A
+testdata/named_inter.C,1,false,,,,,
+testdata/named_inter.B,2,false,1,,,,
+testdata/named_inter/pa.A,3,false,,,,,
+testdata/named_inter.D,4,false,,,1,,
+testdata/named_inter.A,5,false,,2,,1,
+`, file.String())
+}
+
+func TestCSVGenerator(t *testing.T) {
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+	err := NewCSVGenerator("", "").GenerateFromSchema(context.Background(), buff, getSchema())
+	require.NoError(t, err)
+	buff.Flush()
+
+	assert.Equal(t, `package,partition,cycle,mod/storage,mod/service,mod/http
+mod/storage,1,false,,,
+mod/service,2,false,2,2,
+mod/http,3,false,,1,
+`, file.String())
+}
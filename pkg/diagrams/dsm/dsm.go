@@ -0,0 +1,158 @@
+package dsm
+
+import (
+	"sort"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+)
+
+// Level is the granularity of the rows and columns of the matrix.
+type Level string
+
+const (
+	LevelPackage   Level = "package"
+	LevelComponent Level = "component"
+)
+
+// Value is what a cell of the matrix counts.
+type Value string
+
+const (
+	ValueEdges Value = "edges" // The number of edges, a package cell counts the edges between their components
+	ValueFuncs Value = "funcs" // The number of methods used through the edges
+)
+
+// Matrix is a dependency structure matrix, Cells[i][j] is the dependency of the row i on the column j.
+// The rows are partitioned, the dependencies come before their dependents and the members of a cycle are grouped, a
+// cell above the diagonal is a dependency on a later row, which only happens inside a cycle.
+type Matrix struct {
+	Names  []string
+	Groups []int  // The partition of each row, the rows of a cycle share theirs
+	Cycles []bool // Whether each partition is a cycle
+	Cells  [][]int
+}
+
+// Max returns the highest value of the cells outside the diagonal.
+func (m Matrix) Max() int {
+	highest := 0
+	for i, row := range m.Cells {
+		for j, cell := range row {
+			if i != j && cell > highest {
+				highest = cell
+			}
+		}
+	}
+	return highest
+}
+
+// Build returns the matrix of the graph at the level, counting the value.
+// At the package level, the diagonal counts the dependencies between the components of a package.
+func Build(s parse.AstSchema, level Level, value Value) Matrix {
+	key := func(node *parse.Node) string {
+		return node.Name
+	}
+	if level == LevelPackage {
+		key = func(node *parse.Node) string {
+			return node.PackageName
+		}
+	}
+
+	// the graph of the rows, holding an edge wherever a cell is set outside the diagonal
+	rows := parse.NewGraph()
+	counts := make(map[string]map[string]int)
+	for _, node := range s.Graph.GetNodesSortedByName() {
+		from := getRow(rows, key(node))
+		if counts[from.Name] == nil {
+			counts[from.Name] = make(map[string]int)
+		}
+		for _, adj := range s.Graph.GetAdjacenciesSortedByName(node) {
+			to := getRow(rows, key(adj.Node))
+			if _, ok := counts[from.Name][to.Name]; !ok && (from != to || level == LevelComponent) {
+				rows.AddEdge(from, &parse.Adj{Node: to})
+			}
+			count := 1
+			if value == ValueFuncs {
+				count = len(adj.Func)
+			}
+			counts[from.Name][to.Name] += count
+		}
+	}
+
+	m := Matrix{}
+	for group, component := range partition(rows) {
+		m.Cycles = append(m.Cycles, len(component) > 1 || rows.HasSelfEdge(component[0]))
+		for _, row := range component {
+			m.Names = append(m.Names, row.Name)
+			m.Groups = append(m.Groups, group)
+		}
+	}
+	for _, from := range m.Names {
+		cells := make([]int, len(m.Names))
+		for j, to := range m.Names {
+			cells[j] = counts[from][to]
+		}
+		m.Cells = append(m.Cells, cells)
+	}
+	return m
+}
+
+func getRow(rows *parse.Graph, name string) *parse.Node {
+	if node := rows.GetNodeByName(name); node != nil {
+		return node
+	}
+	node := &parse.Node{Name: name}
+	rows.AddNode(node)
+	return node
+}
+
+// partition returns the strongly connected components of the graph, each one after the components it depends on.
+// Among the components whose dependencies are all placed, the one with the lowest name comes first.
+func partition(g *parse.Graph) [][]*parse.Node {
+	components := g.StronglyConnectedComponents()
+	componentOf := make(map[*parse.Node]int)
+	for i, component := range components {
+		for _, node := range component {
+			componentOf[node] = i
+		}
+	}
+
+	remaining := make([]map[int]bool, len(components)) // the dependencies of each component not placed yet
+	dependents := make([]map[int]bool, len(components))
+	for i := range components {
+		remaining[i] = make(map[int]bool)
+		dependents[i] = make(map[int]bool)
+	}
+	for i, component := range components {
+		for _, node := range component {
+			for _, adj := range g.Adj[node] {
+				j := componentOf[adj.Node]
+				if i != j {
+					remaining[i][j] = true
+					dependents[j][i] = true
+				}
+			}
+		}
+	}
+
+	var ready []int
+	for i := range components {
+		if len(remaining[i]) == 0 {
+			ready = append(ready, i)
+		}
+	}
+	ordered := make([][]*parse.Node, 0, len(components))
+	for len(ready) > 0 {
+		// the components are sorted by name, the lowest index has the lowest name
+		sort.Ints(ready)
+		i := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, components[i])
+		for dependent := range dependents[i] {
+			delete(remaining[dependent], i)
+			if len(remaining[dependent]) == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+	return ordered
+}
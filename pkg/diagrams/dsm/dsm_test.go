@@ -0,0 +1,73 @@
+package dsm
+
+import (
+	"testing"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+// getSchema returns a layered project, the handler uses the services, which use the storage, the two services
+// depending on each other.
+func getSchema() parse.AstSchema {
+	graph := parse.NewGraph()
+	handler := &parse.Node{Name: "mod/http.Handler", PackageName: "mod/http", StructName: "Handler"}
+	users := &parse.Node{Name: "mod/service.Users", PackageName: "mod/service", StructName: "Users"}
+	mailer := &parse.Node{Name: "mod/service.Mailer", PackageName: "mod/service", StructName: "Mailer"}
+	storage := &parse.Node{Name: "mod/storage.Storage", PackageName: "mod/storage", StructName: "Storage"}
+	graph.AddNode(handler)
+	graph.AddNode(users)
+	graph.AddNode(mailer)
+	graph.AddNode(storage)
+	graph.AddEdge(handler, &parse.Adj{Node: users, Func: []string{"Create", "Get"}})
+	graph.AddEdge(users, &parse.Adj{Node: mailer, Func: []string{"Send"}})
+	graph.AddEdge(mailer, &parse.Adj{Node: users, Func: []string{"Get"}})
+	graph.AddEdge(users, &parse.Adj{Node: storage, Func: []string{"Load", "Save", "Delete"}})
+	graph.AddEdge(mailer, &parse.Adj{Node: storage, Func: []string{"Load"}})
+	return parse.AstSchema{ModulePath: "mod", Graph: graph}
+}
+
+func TestBuild_component(t *testing.T) {
+	m := Build(getSchema(), LevelComponent, ValueFuncs)
+
+	assert.Equal(t, Matrix{
+		Names:  []string{"mod/storage.Storage", "mod/service.Mailer", "mod/service.Users", "mod/http.Handler"},
+		Groups: []int{0, 1, 1, 2},
+		Cycles: []bool{false, true, false},
+		Cells: [][]int{
+			{0, 0, 0, 0},
+			{1, 0, 1, 0},
+			{3, 1, 0, 0},
+			{0, 0, 2, 0},
+		},
+	}, m)
+	assert.Equal(t, 3, m.Max())
+}
+
+func TestBuild_package(t *testing.T) {
+	m := Build(getSchema(), LevelPackage, ValueEdges)
+
+	assert.Equal(t, Matrix{
+		Names:  []string{"mod/storage", "mod/service", "mod/http"},
+		Groups: []int{0, 1, 2},
+		Cycles: []bool{false, false, false},
+		Cells: [][]int{
+			{0, 0, 0},
+			{2, 2, 0},
+			{0, 1, 0},
+		},
+	}, m)
+	assert.Equal(t, 2, m.Max())
+}
+
+func TestBuild_selfEdge(t *testing.T) {
+	graph := parse.NewGraph()
+	node := &parse.Node{Name: "mod.Node", PackageName: "mod", StructName: "Node"}
+	graph.AddNode(node)
+	graph.AddEdge(node, &parse.Adj{Node: node, Func: []string{"Next"}})
+
+	m := Build(parse.AstSchema{ModulePath: "mod", Graph: graph}, LevelComponent, ValueEdges)
+
+	assert.Equal(t, []bool{true}, m.Cycles)
+	assert.Equal(t, [][]int{{1}}, m.Cells)
+}
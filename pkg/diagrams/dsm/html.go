@@ -0,0 +1,176 @@
+package dsm
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+)
+
+const shades = 5
+
+var valueDescriptions = map[Value]string{
+	ValueEdges: "edges",
+	ValueFuncs: "methods used",
+}
+
+const page = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; font-size: 12px; color: #24292f; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #d0d7de; padding: 2px 4px; text-align: center; min-width: 18px; }
+th.name { text-align: left; white-space: nowrap; font-weight: normal; }
+tr.partition > * { border-top: 2px solid #57606a; }
+td.partition { border-left: 2px solid #57606a; }
+td.diagonal { background: #8c959f; color: #ffffff; }
+td.cycle { background: #fff1e5; }
+td.below.shade-1 { background: #ddf4ff; }
+td.below.shade-2 { background: #b6e3ff; }
+td.below.shade-3 { background: #80ccff; }
+td.below.shade-4 { background: #54aeff; }
+td.below.shade-5 { background: #218bff; color: #ffffff; }
+td.above { background: #ffcecb; font-weight: bold; }
+td.above.shade-4, td.above.shade-5 { background: #ff8182; }
+.legend span { display: inline-block; padding: 2px 6px; margin-right: 8px; border: 1px solid #d0d7de; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Description}}</p>
+<p class="legend"><span style="background: #80ccff">dependency on an earlier row</span><span style="background: #ffcecb">dependency on a later row, a layering violation</span><span style="background: #fff1e5">cycle</span></p>
+<table>
+<thead>
+<tr><th></th><th></th>{{range .Rows}}<th title="{{.Name}}">{{.Index}}</th>{{end}}</tr>
+</thead>
+<tbody>
+{{range .Rows}}<tr{{if .Partition}} class="partition"{{end}}><th class="name" title="{{.Name}}">{{.Label}}</th><th>{{.Index}}</th>{{range .Cells}}<td class="{{.Class}}"{{if .Title}} title="{{.Title}}"{{end}}>{{if .Value}}{{.Value}}{{end}}</td>{{end}}</tr>
+{{end}}</tbody>
+</table>
+</body>
+</html>
+`
+
+// HTMLGenerator generates the matrix as a colour coded HTML table, the darker a cell the more it counts.
+// The dependencies on an earlier row are blue, the dependencies on a later row, breaking the layering, are red, and the
+// cells between the members of a cycle are orange.
+type HTMLGenerator struct {
+	level    Level
+	value    Value
+	template *template.Template
+}
+
+// NewHTMLGenerator returns a generator of the matrix at the level, default package, counting the value, default edges.
+func NewHTMLGenerator(level Level, value Value) *HTMLGenerator {
+	return &HTMLGenerator{
+		level:    getLevel(level),
+		value:    getValue(value),
+		template: template.Must(template.New("dsm").Parse(page)),
+	}
+}
+
+func (g HTMLGenerator) GetDefaultResultFileName() string {
+	return "dsm.html"
+}
+
+type htmlRow struct {
+	Index     int
+	Name      string
+	Label     string
+	Partition bool // The row starts a partition
+	Cells     []htmlCell
+}
+
+type htmlCell struct {
+	Value int
+	Class string
+	Title string
+}
+
+func (g HTMLGenerator) GenerateFromSchema(ctx context.Context, writer *bufio.Writer, s parse.AstSchema) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	m := Build(s, g.level, g.value)
+
+	highest := m.Max()
+	rows := make([]htmlRow, 0, len(m.Names))
+	for i, name := range m.Names {
+		row := htmlRow{
+			Index:     i + 1,
+			Name:      name,
+			Label:     getLabel(name, s.ModulePath),
+			Partition: i > 0 && m.Groups[i] != m.Groups[i-1],
+			Cells:     make([]htmlCell, 0, len(m.Names)),
+		}
+		for j, value := range m.Cells[i] {
+			row.Cells = append(row.Cells, getCell(m, i, j, value, highest))
+		}
+		rows = append(rows, row)
+	}
+
+	err := g.template.Execute(writer, struct {
+		Title       string
+		Description string
+		Rows        []htmlRow
+	}{
+		Title:       s.ModulePath,
+		Description: fmt.Sprintf("The dependencies between the %ss, a row depends on the columns holding a value, the values count the %s.", g.level, valueDescriptions[g.value]),
+		Rows:        rows,
+	})
+	if err != nil {
+		return fmt.Errorf("template.Execute:%w", err)
+	}
+	return nil
+}
+
+func getCell(m Matrix, i, j, value, highest int) htmlCell {
+	var classes []string
+	if j > 0 && m.Groups[j] != m.Groups[j-1] {
+		classes = append(classes, "partition")
+	}
+	switch {
+	case i == j:
+		classes = append(classes, "diagonal")
+	case value > 0 && j < i:
+		classes = append(classes, "below", fmt.Sprintf("shade-%d", getShade(value, highest)))
+	case value > 0:
+		classes = append(classes, "above", fmt.Sprintf("shade-%d", getShade(value, highest)))
+	case m.Groups[i] == m.Groups[j] && m.Cycles[m.Groups[i]]:
+		classes = append(classes, "cycle")
+	}
+	cell := htmlCell{Value: value, Class: strings.Join(classes, " ")}
+	if value > 0 {
+		cell.Title = fmt.Sprintf("%s -> %s: %d", m.Names[i], m.Names[j], value)
+	}
+	return cell
+}
+
+// getShade returns the shade of the value, from 1 to shades, relative to the highest value.
+func getShade(value, highest int) int {
+	if highest == 0 || value >= highest {
+		return shades
+	}
+	return (value*shades + highest - 1) / highest
+}
+
+// getLabel returns the name without the module path.
+func getLabel(name, modulePath string) string {
+	if modulePath == "" {
+		return name
+	}
+	for _, separator := range []string{"/", "."} {
+		if strings.HasPrefix(name, modulePath+separator) {
+			return strings.TrimPrefix(name, modulePath+separator)
+		}
+	}
+	return name
+}
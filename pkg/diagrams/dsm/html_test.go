@@ -0,0 +1,34 @@
+package dsm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTMLGenerator(t *testing.T) {
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+	err := NewHTMLGenerator(LevelComponent, ValueFuncs).GenerateFromSchema(context.Background(), buff, getSchema())
+	require.NoError(t, err)
+	buff.Flush()
+
+	page := file.String()
+	assert.Contains(t, page, "<title>mod</title>")
+	assert.Contains(t, page, "<p>The dependencies between the components, a row depends on the columns holding a value, the values count the methods used.</p>")
+	assert.Contains(t, page, `<tr><th></th><th></th><th title="mod/storage.Storage">1</th><th title="mod/service.Mailer">2</th><th title="mod/service.Users">3</th><th title="mod/http.Handler">4</th></tr>`)
+	// the mailer and the users are a cycle, the mailer depends on a later row
+	assert.Contains(t, page, `<tr class="partition"><th class="name" title="mod/service.Mailer">service.Mailer</th><th>2</th><td class="below shade-2" title="mod/service.Mailer -&gt; mod/storage.Storage: 1">1</td><td class="partition diagonal"></td><td class="above shade-2" title="mod/service.Mailer -&gt; mod/service.Users: 1">1</td><td class="partition"></td></tr>`)
+	assert.Contains(t, page, `<tr><th class="name" title="mod/service.Users">service.Users</th><th>3</th><td class="below shade-5" title="mod/service.Users -&gt; mod/storage.Storage: 3">3</td><td class="partition below shade-2" title="mod/service.Users -&gt; mod/service.Mailer: 1">1</td><td class="diagonal"></td><td class="partition"></td></tr>`)
+}
+
+func TestGetShade(t *testing.T) {
+	assert.Equal(t, 1, getShade(1, 10))
+	assert.Equal(t, 3, getShade(5, 10))
+	assert.Equal(t, 5, getShade(10, 10))
+	assert.Equal(t, 5, getShade(1, 0))
+}
@@ -4,10 +4,20 @@ import (
 	"bufio"
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/emilien-puget/go-dependency-graph/pkg/diagrams/c4"
+	"github.com/emilien-puget/go-dependency-graph/pkg/diagrams/config"
+	"github.com/emilien-puget/go-dependency-graph/pkg/diagrams/d2"
+	"github.com/emilien-puget/go-dependency-graph/pkg/diagrams/dsm"
+	"github.com/emilien-puget/go-dependency-graph/pkg/diagrams/gexf"
+	"github.com/emilien-puget/go-dependency-graph/pkg/diagrams/graphml"
+	"github.com/emilien-puget/go-dependency-graph/pkg/diagrams/graphviz"
+	"github.com/emilien-puget/go-dependency-graph/pkg/diagrams/html"
 	"github.com/emilien-puget/go-dependency-graph/pkg/diagrams/mermaid"
+	"github.com/emilien-puget/go-dependency-graph/pkg/diagrams/sequence"
 	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/emilien-puget/go-dependency-graph/pkg/snapshot"
 )
 
 type Generator interface {
@@ -18,16 +28,132 @@ type Generator interface {
 const (
 	GeneratorC4PlantumlComponent = "c4_plantuml_component"
 	GeneratorMermaidClass        = "mermaid_class"
+	GeneratorMermaidFlowchart    = "mermaid_flowchart"
+	GeneratorJSON                = "json"
+	GeneratorGraphvizDot         = "graphviz_dot"
+	GeneratorD2                  = "d2"
+	GeneratorHTML                = "html"
+	GeneratorGraphML             = "graphml"
+	GeneratorGEXF                = "gexf"
+	GeneratorDSMCSV              = "dsm_csv"
+	GeneratorDSMHTML             = "dsm_html"
+	GeneratorPlantumlSequence    = "plantuml_sequence"
+	GeneratorMermaidSequence     = "mermaid_sequence"
+	GeneratorC4PlantumlContainer = "c4_plantuml_container"
+	GeneratorC4PlantumlContext   = "c4_plantuml_context"
 )
 
-var errUnknownGenerator = errors.New("unknown generator")
+// generators lists the generators in the order of the help, a generator drawing the nodes of the schema is able to draw
+// any subgraph, such as the paths of the why command.
+var generators = []struct {
+	name       string
+	drawsNodes bool
+}{
+	{name: GeneratorC4PlantumlComponent, drawsNodes: true},
+	{name: GeneratorMermaidClass, drawsNodes: true},
+	{name: GeneratorMermaidFlowchart, drawsNodes: true},
+	{name: GeneratorJSON},
+	{name: GeneratorGraphvizDot, drawsNodes: true},
+	{name: GeneratorD2, drawsNodes: true},
+	{name: GeneratorHTML, drawsNodes: true},
+	{name: GeneratorGraphML, drawsNodes: true},
+	{name: GeneratorGEXF, drawsNodes: true},
+	{name: GeneratorDSMCSV, drawsNodes: true},
+	{name: GeneratorDSMHTML, drawsNodes: true},
+	{name: GeneratorPlantumlSequence},
+	{name: GeneratorMermaidSequence},
+	{name: GeneratorC4PlantumlContainer},
+	{name: GeneratorC4PlantumlContext},
+}
+
+var (
+	errUnknownGenerator = errors.New("unknown generator")
+	errInvalidRankDir   = errors.New("invalid rank direction, [TB, LR, BT, RL]")
+	errInvalidDSMLevel  = errors.New("invalid dsm level, [package, component]")
+	errInvalidDSMValue  = errors.New("invalid dsm value, [edges, funcs]")
+	errMissingEntry     = errors.New("the sequence entry method is required")
+)
+
+// GetGeneratorNames returns the names of the generators.
+func GetGeneratorNames() []string {
+	names := make([]string, 0, len(generators))
+	for _, g := range generators {
+		names = append(names, g.name)
+	}
+	return names
+}
 
-func GetGenerator(generator string) (Generator, error) {
+// GetNodeGeneratorNames returns the names of the generators drawing the nodes of the schema.
+func GetNodeGeneratorNames() []string {
+	names := make([]string, 0, len(generators))
+	for _, g := range generators {
+		if g.drawsNodes {
+			names = append(names, g.name)
+		}
+	}
+	return names
+}
+
+func GetGenerator(generator string, c config.Config) (Generator, error) {
+	switch c.RankDir {
+	case "", "TB", "LR", "BT", "RL":
+	default:
+		return nil, fmt.Errorf("%s: %w", c.RankDir, errInvalidRankDir)
+	}
+	switch dsm.Level(c.DSMLevel) {
+	case "", dsm.LevelPackage, dsm.LevelComponent:
+	default:
+		return nil, fmt.Errorf("%s: %w", c.DSMLevel, errInvalidDSMLevel)
+	}
+	switch dsm.Value(c.DSMValue) {
+	case "", dsm.ValueEdges, dsm.ValueFuncs:
+	default:
+		return nil, fmt.Errorf("%s: %w", c.DSMValue, errInvalidDSMValue)
+	}
 	switch generator {
 	case GeneratorC4PlantumlComponent:
 		return c4.NewGenerator(), nil
 	case GeneratorMermaidClass:
 		return mermaid.NewGenerator(), nil
+	case GeneratorMermaidFlowchart:
+		return mermaid.NewFlowchartGenerator(c.RankDir, c.LinkBase), nil
+	case GeneratorJSON:
+		return snapshot.NewGenerator(), nil
+	case GeneratorGraphvizDot:
+		return graphviz.NewGenerator(c.RankDir, !c.DisableClusters), nil
+	case GeneratorD2:
+		return d2.NewGenerator(c.RankDir), nil
+	case GeneratorHTML:
+		return html.NewGenerator(), nil
+	case GeneratorGraphML:
+		return graphml.NewGenerator(), nil
+	case GeneratorGEXF:
+		return gexf.NewGenerator(), nil
+	case GeneratorDSMCSV:
+		return dsm.NewCSVGenerator(dsm.Level(c.DSMLevel), dsm.Value(c.DSMValue)), nil
+	case GeneratorDSMHTML:
+		return dsm.NewHTMLGenerator(dsm.Level(c.DSMLevel), dsm.Value(c.DSMValue)), nil
+	case GeneratorPlantumlSequence, GeneratorMermaidSequence:
+		if c.SequenceEntry == "" {
+			return nil, errMissingEntry
+		}
+		if generator == GeneratorPlantumlSequence {
+			return sequence.NewPlantUMLGenerator(c.SequenceEntry, c.SequenceDepth), nil
+		}
+		return sequence.NewMermaidGenerator(c.SequenceEntry, c.SequenceDepth), nil
+	case GeneratorC4PlantumlContainer, GeneratorC4PlantumlContext:
+		metadata := c4.Metadata{}
+		if c.C4Metadata != "" {
+			var err error
+			metadata, err = c4.LoadMetadata(c.C4Metadata)
+			if err != nil {
+				return nil, fmt.Errorf("c4.LoadMetadata:%w", err)
+			}
+		}
+		if generator == GeneratorC4PlantumlContainer {
+			return c4.NewContainerGenerator(metadata), nil
+		}
+		return c4.NewContextGenerator(metadata), nil
 	default:
 		return nil, errUnknownGenerator
 	}
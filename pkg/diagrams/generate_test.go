@@ -0,0 +1,19 @@
+package diagrams
+
+import (
+	"testing"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/diagrams/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetGenerator_names(t *testing.T) {
+	for _, name := range GetGeneratorNames() {
+		generator, err := GetGenerator(name, config.Config{SequenceEntry: "a.B.C"})
+		require.NoError(t, err, name)
+		assert.NotNil(t, generator, name)
+	}
+	assert.Subset(t, GetGeneratorNames(), GetNodeGeneratorNames())
+	assert.NotContains(t, GetNodeGeneratorNames(), GeneratorJSON)
+}
@@ -0,0 +1,135 @@
+package gexf
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/diagrams/xmltext"
+	mymap "github.com/emilien-puget/go-dependency-graph/pkg/map"
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+)
+
+const attributes = `    <attributes class="node">
+      <attribute id="package" title="package" type="string"/>
+      <attribute id="struct" title="struct" type="string"/>
+      <attribute id="external" title="external" type="boolean">
+        <default>false</default>
+      </attribute>
+      <attribute id="doc" title="doc" type="string"/>
+      <attribute id="file" title="file" type="string"/>
+      <attribute id="methods" title="methods" type="integer">
+        <default>0</default>
+      </attribute>
+    </attributes>
+    <attributes class="edge">
+      <attribute id="funcs" title="funcs" type="string"/>
+      <attribute id="func_count" title="func_count" type="integer">
+        <default>0</default>
+      </attribute>
+    </attributes>
+`
+
+// Generator generates a GEXF document, to be loaded in graph analysis tools such as Gephi.
+type Generator struct{}
+
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+func (g Generator) GetDefaultResultFileName() string {
+	return "diag.gexf"
+}
+
+// GenerateFromSchema generates a GEXF directed graph, the nodes are ordered by package then by name, the edges by
+// their source then their target.
+func (g Generator) GenerateFromSchema(ctx context.Context, writer *bufio.Writer, s parse.AstSchema) error {
+	_, err := fmt.Fprintf(writer, `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://gexf.net/1.3" version="1.3">
+  <meta>
+    <creator>go-dependency-graph</creator>
+    <description>%s</description>
+  </meta>
+  <graph defaultedgetype="directed" mode="static">
+%s    <nodes>
+`, xmltext.Escape(s.ModulePath), attributes)
+	if err != nil {
+		return err
+	}
+
+	for _, packageName := range mymap.OrderedKeys(s.Graph.NodesByPackage) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		for _, node := range parse.SortNodesByName(s.Graph.NodesByPackage[packageName]) {
+			_, err = writer.WriteString(getNode(node, s.Dir))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = writer.WriteString("    </nodes>\n    <edges>\n")
+	if err != nil {
+		return err
+	}
+	i := 0
+	for _, node := range s.Graph.GetNodesSortedByName() {
+		for _, adj := range s.Graph.GetAdjacenciesSortedByName(node) {
+			_, err = writer.WriteString(getEdge(i, node, adj))
+			if err != nil {
+				return err
+			}
+			i++
+		}
+	}
+
+	_, err = writer.WriteString("    </edges>\n  </graph>\n</gexf>\n")
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func getNode(node *parse.Node, dir string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "      <node id=\"%s\" label=\"%s\">\n        <attvalues>\n", xmltext.Escape(node.Name), xmltext.Escape(node.StructName))
+	b.WriteString(attvalue("package", node.PackageName))
+	b.WriteString(attvalue("struct", node.StructName))
+	if node.External {
+		b.WriteString(attvalue("external", "true"))
+	}
+	if node.Doc != "" {
+		b.WriteString(attvalue("doc", node.Doc))
+	}
+	if file := node.GetRelativeFilePath(dir); file != "" {
+		b.WriteString(attvalue("file", file))
+	}
+	if len(node.Methods) > 0 {
+		b.WriteString(attvalue("methods", strconv.Itoa(len(node.Methods))))
+	}
+	b.WriteString("        </attvalues>\n      </node>\n")
+	return b.String()
+}
+
+// getEdge returns the edge, labelled with the methods of the dependency used.
+func getEdge(i int, node *parse.Node, adj *parse.Adj) string {
+	if len(adj.Func) == 0 {
+		return fmt.Sprintf("      <edge id=\"%d\" source=\"%s\" target=\"%s\"/>\n", i, xmltext.Escape(node.Name), xmltext.Escape(adj.Node.Name))
+	}
+	funcs := strings.Join(adj.Func, ", ")
+	var b strings.Builder
+	fmt.Fprintf(&b, "      <edge id=\"%d\" source=\"%s\" target=\"%s\" label=\"%s\">\n        <attvalues>\n", i, xmltext.Escape(node.Name), xmltext.Escape(adj.Node.Name), xmltext.Escape(funcs))
+	b.WriteString(attvalue("funcs", funcs))
+	b.WriteString(attvalue("func_count", strconv.Itoa(len(adj.Func))))
+	b.WriteString("        </attvalues>\n      </edge>\n")
+	return b.String()
+}
+
+func attvalue(attribute, value string) string {
+	return fmt.Sprintf("          <attvalue for=\"%s\" value=\"%s\"/>\n", attribute, xmltext.Escape(value))
+}
@@ -0,0 +1,162 @@
+package gexf
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerator_withParse(t *testing.T) {
+	as, err := parse.Parse("../testdata/named_inter", nil)
+	require.NoError(t, err)
+
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+	err = NewGenerator().GenerateFromSchema(context.Background(), buff, as)
+	require.NoError(t, err)
+	buff.Flush()
+
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://gexf.net/1.3" version="1.3">
+  <meta>
+    <creator>go-dependency-graph</creator>
+    <description>testdata/named_inter</description>
+  </meta>
+  <graph defaultedgetype="directed" mode="static">
+    <attributes class="node">
+      <attribute id="package" title="package" type="string"/>
+      <attribute id="struct" title="struct" type="string"/>
+      <attribute id="external" title="external" type="boolean">
+        <default>false</default>
+      </attribute>
+      <attribute id="doc" title="doc" type="string"/>
+      <attribute id="file" title="file" type="string"/>
+      <attribute id="methods" title="methods" type="integer">
+        <default>0</default>
+      </attribute>
+    </attributes>
+    <attributes class="edge">
+      <attribute id="funcs" title="funcs" type="string"/>
+      <attribute id="func_count" title="func_count" type="integer">
+        <default>0</default>
+      </attribute>
+    </attributes>
+    <nodes>
+      <node id="testdata/named_inter.A" label="A">
+        <attvalues>
+          <attvalue for="package" value="testdata/named_inter"/>
+          <attvalue for="struct" value="A"/>
+          <attvalue for="file" value="a.go"/>
+        </attvalues>
+      </node>
+      <node id="testdata/named_inter.B" label="B">
+        <attvalues>
+          <attvalue for="package" value="testdata/named_inter"/>
+          <attvalue for="struct" value="B"/>
+          <attvalue for="file" value="b.go"/>
+          <attvalue for="methods" value="2"/>
+        </attvalues>
+      </node>
+      <node id="testdata/named_inter.C" label="C">
+        <attvalues>
+          <attvalue for="package" value="testdata/named_inter"/>
+          <attvalue for="struct" value="C"/>
+          <attvalue for="file" value="c.go"/>
+          <attvalue for="methods" value="1"/>
+        </attvalues>
+      </node>
+      <node id="testdata/named_inter.D" label="D">
+        <attvalues>
+          <attvalue for="package" value="testdata/named_inter"/>
+          <attvalue for="struct" value="D"/>
+          <attvalue for="file" value="d.go"/>
+          <attvalue for="methods" value="1"/>
+        </attvalues>
+      </node>
+      <node id="testdata/named_inter/pa.A" label="A">
+        <attvalues>
+          <attvalue for="package" value="testdata/named_inter/pa"/>
+          <attvalue for="struct" value="A"/>
+          <attvalue for="doc" value="A pa struct."/>
+          <attvalue for="file" value="pa/a.go"/>
+          <attvalue for="methods" value="1"/>
+        </attvalues>
+      </node>
+    </nodes>
+    <edges>
+      <edge id="0" source="testdata/named_inter.A" target="testdata/named_inter.B" label="FuncA, FuncB">
+        <attvalues>
+          <attvalue for="funcs" value="FuncA, FuncB"/>
+          <attvalue for="func_count" value="2"/>
+        </attvalues>
+      </edge>
+      <edge id="1" source="testdata/named_inter.A" target="testdata/named_inter.D" label="FuncA">
+        <attvalues>
+          <attvalue for="funcs" value="FuncA"/>
+          <attvalue for="func_count" value="1"/>
+        </attvalues>
+      </edge>
+      <edge id="2" source="testdata/named_inter.B" target="testdata/named_inter.C" label="FuncA">
+        <attvalues>
+          <attvalue for="funcs" value="FuncA"/>
+          <attvalue for="func_count" value="1"/>
+        </attvalues>
+      </edge>
+      <edge id="3" source="testdata/named_inter.D" target="testdata/named_inter/pa.A" label="FuncFoo">
+        <attvalues>
+          <attvalue for="funcs" value="FuncFoo"/>
+          <attvalue for="func_count" value="1"/>
+        </attvalues>
+      </edge>
+    </edges>
+  </graph>
+</gexf>
+`, file.String())
+}
+
+func TestGenerator(t *testing.T) {
+	graph := parse.NewGraph()
+	service := &parse.Node{Name: "mod/app.Service", PackageName: "mod/app", StructName: "Service", FilePath: "/src/mod/app/service.go", Doc: `The "main" service & <entry>.`}
+	repository := &parse.Node{Name: "mod/app.Repository", PackageName: "mod/app", StructName: "Repository"}
+	client := &parse.Node{Name: "github.com/lib/http.Client", PackageName: "github.com/lib/http", StructName: "Client", External: true}
+	graph.AddNode(service)
+	graph.AddNode(repository)
+	graph.AddNode(client)
+	graph.AddEdge(service, &parse.Adj{Node: repository})
+	graph.AddEdge(service, &parse.Adj{Node: client, Func: []string{"Do"}})
+
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+	err := NewGenerator().GenerateFromSchema(context.Background(), buff, parse.AstSchema{ModulePath: "mod", Dir: "/src/mod", Graph: graph})
+	require.NoError(t, err)
+	buff.Flush()
+
+	out := file.String()
+	assert.Contains(t, out, `<node id="github.com/lib/http.Client" label="Client">
+        <attvalues>
+          <attvalue for="package" value="github.com/lib/http"/>
+          <attvalue for="struct" value="Client"/>
+          <attvalue for="external" value="true"/>
+        </attvalues>
+      </node>`)
+	assert.Contains(t, out, `<attvalue for="doc" value="The &#34;main&#34; service &amp; &lt;entry&gt;."/>`)
+	assert.Contains(t, out, `<edge id="1" source="mod/app.Service" target="mod/app.Repository"/>`)
+
+	decoder := xml.NewDecoder(strings.NewReader(out))
+	for {
+		_, err = decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,117 @@
+package graphml
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/diagrams/xmltext"
+	mymap "github.com/emilien-puget/go-dependency-graph/pkg/map"
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+)
+
+const header = `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://graphml.graphdrawing.org/xmlns http://graphml.graphdrawing.org/xmlns/1.0/graphml.xsd">
+  <key id="package" for="node" attr.name="package" attr.type="string"/>
+  <key id="struct" for="node" attr.name="struct" attr.type="string"/>
+  <key id="external" for="node" attr.name="external" attr.type="boolean">
+    <default>false</default>
+  </key>
+  <key id="doc" for="node" attr.name="doc" attr.type="string"/>
+  <key id="file" for="node" attr.name="file" attr.type="string"/>
+  <key id="methods" for="node" attr.name="methods" attr.type="int">
+    <default>0</default>
+  </key>
+  <key id="funcs" for="edge" attr.name="funcs" attr.type="string"/>
+  <key id="func_count" for="edge" attr.name="func_count" attr.type="int">
+    <default>0</default>
+  </key>
+`
+
+// Generator generates a GraphML document, to be loaded in graph analysis tools such as yEd.
+type Generator struct{}
+
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+func (g Generator) GetDefaultResultFileName() string {
+	return "diag.graphml"
+}
+
+// GenerateFromSchema generates a GraphML directed graph, the nodes are ordered by package then by name, the edges by
+// their source then their target.
+func (g Generator) GenerateFromSchema(ctx context.Context, writer *bufio.Writer, s parse.AstSchema) error {
+	_, err := fmt.Fprintf(writer, "%s  <graph id=\"%s\" edgedefault=\"directed\">\n", header, xmltext.Escape(s.ModulePath))
+	if err != nil {
+		return err
+	}
+
+	for _, packageName := range mymap.OrderedKeys(s.Graph.NodesByPackage) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		for _, node := range parse.SortNodesByName(s.Graph.NodesByPackage[packageName]) {
+			_, err = writer.WriteString(getNode(node, s.Dir))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	i := 0
+	for _, node := range s.Graph.GetNodesSortedByName() {
+		for _, adj := range s.Graph.GetAdjacenciesSortedByName(node) {
+			_, err = fmt.Fprintf(writer, "    <edge id=\"e%d\" source=\"%s\" target=\"%s\">\n", i, xmltext.Escape(node.Name), xmltext.Escape(adj.Node.Name))
+			if err != nil {
+				return err
+			}
+			if len(adj.Func) > 0 {
+				_, err = fmt.Fprintf(writer, "%s%s", data("funcs", strings.Join(adj.Func, ", ")), data("func_count", strconv.Itoa(len(adj.Func))))
+				if err != nil {
+					return err
+				}
+			}
+			_, err = writer.WriteString("    </edge>\n")
+			if err != nil {
+				return err
+			}
+			i++
+		}
+	}
+
+	_, err = writer.WriteString("  </graph>\n</graphml>\n")
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func getNode(node *parse.Node, dir string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "    <node id=\"%s\">\n", xmltext.Escape(node.Name))
+	b.WriteString(data("package", node.PackageName))
+	b.WriteString(data("struct", node.StructName))
+	if node.External {
+		b.WriteString(data("external", "true"))
+	}
+	if node.Doc != "" {
+		b.WriteString(data("doc", node.Doc))
+	}
+	if file := node.GetRelativeFilePath(dir); file != "" {
+		b.WriteString(data("file", file))
+	}
+	if len(node.Methods) > 0 {
+		b.WriteString(data("methods", strconv.Itoa(len(node.Methods))))
+	}
+	b.WriteString("    </node>\n")
+	return b.String()
+}
+
+func data(key, value string) string {
+	return fmt.Sprintf("      <data key=\"%s\">%s</data>\n", key, xmltext.Escape(value))
+}
@@ -0,0 +1,131 @@
+package graphml
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerator_withParse(t *testing.T) {
+	as, err := parse.Parse("../testdata/named_inter", nil)
+	require.NoError(t, err)
+
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+	err = NewGenerator().GenerateFromSchema(context.Background(), buff, as)
+	require.NoError(t, err)
+	buff.Flush()
+
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://graphml.graphdrawing.org/xmlns http://graphml.graphdrawing.org/xmlns/1.0/graphml.xsd">
+  <key id="package" for="node" attr.name="package" attr.type="string"/>
+  <key id="struct" for="node" attr.name="struct" attr.type="string"/>
+  <key id="external" for="node" attr.name="external" attr.type="boolean">
+    <default>false</default>
+  </key>
+  <key id="doc" for="node" attr.name="doc" attr.type="string"/>
+  <key id="file" for="node" attr.name="file" attr.type="string"/>
+  <key id="methods" for="node" attr.name="methods" attr.type="int">
+    <default>0</default>
+  </key>
+  <key id="funcs" for="edge" attr.name="funcs" attr.type="string"/>
+  <key id="func_count" for="edge" attr.name="func_count" attr.type="int">
+    <default>0</default>
+  </key>
+  <graph id="testdata/named_inter" edgedefault="directed">
+    <node id="testdata/named_inter.A">
+      <data key="package">testdata/named_inter</data>
+      <data key="struct">A</data>
+      <data key="file">a.go</data>
+    </node>
+    <node id="testdata/named_inter.B">
+      <data key="package">testdata/named_inter</data>
+      <data key="struct">B</data>
+      <data key="file">b.go</data>
+      <data key="methods">2</data>
+    </node>
+    <node id="testdata/named_inter.C">
+      <data key="package">testdata/named_inter</data>
+      <data key="struct">C</data>
+      <data key="file">c.go</data>
+      <data key="methods">1</data>
+    </node>
+    <node id="testdata/named_inter.D">
+      <data key="package">testdata/named_inter</data>
+      <data key="struct">D</data>
+      <data key="file">d.go</data>
+      <data key="methods">1</data>
+    </node>
+    <node id="testdata/named_inter/pa.A">
+      <data key="package">testdata/named_inter/pa</data>
+      <data key="struct">A</data>
+      <data key="doc">A pa struct.</data>
+      <data key="file">pa/a.go</data>
+      <data key="methods">1</data>
+    </node>
+    <edge id="e0" source="testdata/named_inter.A" target="testdata/named_inter.B">
+      <data key="funcs">FuncA, FuncB</data>
+      <data key="func_count">2</data>
+    </edge>
+    <edge id="e1" source="testdata/named_inter.A" target="testdata/named_inter.D">
+      <data key="funcs">FuncA</data>
+      <data key="func_count">1</data>
+    </edge>
+    <edge id="e2" source="testdata/named_inter.B" target="testdata/named_inter.C">
+      <data key="funcs">FuncA</data>
+      <data key="func_count">1</data>
+    </edge>
+    <edge id="e3" source="testdata/named_inter.D" target="testdata/named_inter/pa.A">
+      <data key="funcs">FuncFoo</data>
+      <data key="func_count">1</data>
+    </edge>
+  </graph>
+</graphml>
+`, file.String())
+}
+
+func TestGenerator(t *testing.T) {
+	graph := parse.NewGraph()
+	service := &parse.Node{Name: "mod/app.Service", PackageName: "mod/app", StructName: "Service", FilePath: "/src/mod/app/service.go", Doc: `The "main" service & <entry>.`}
+	repository := &parse.Node{Name: "mod/app.Repository", PackageName: "mod/app", StructName: "Repository"}
+	client := &parse.Node{Name: "github.com/lib/http.Client", PackageName: "github.com/lib/http", StructName: "Client", External: true}
+	graph.AddNode(service)
+	graph.AddNode(repository)
+	graph.AddNode(client)
+	graph.AddEdge(service, &parse.Adj{Node: repository})
+	graph.AddEdge(service, &parse.Adj{Node: client, Func: []string{"Do"}})
+
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+	err := NewGenerator().GenerateFromSchema(context.Background(), buff, parse.AstSchema{ModulePath: "mod", Dir: "/src/mod", Graph: graph})
+	require.NoError(t, err)
+	buff.Flush()
+
+	out := file.String()
+	assert.Contains(t, out, `<node id="github.com/lib/http.Client">
+      <data key="package">github.com/lib/http</data>
+      <data key="struct">Client</data>
+      <data key="external">true</data>
+    </node>`)
+	assert.Contains(t, out, `<data key="doc">The &#34;main&#34; service &amp; &lt;entry&gt;.</data>`)
+	assert.Contains(t, out, `<edge id="e1" source="mod/app.Service" target="mod/app.Repository">
+    </edge>`)
+
+	decoder := xml.NewDecoder(strings.NewReader(out))
+	for {
+		_, err = decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+	}
+}
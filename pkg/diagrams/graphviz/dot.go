@@ -0,0 +1,166 @@
+package graphviz
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+
+	mymap "github.com/emilien-puget/go-dependency-graph/pkg/map"
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+)
+
+const (
+	DefaultRankDir = "LR"
+
+	clusterPrefix = "cluster_"
+	externalStyle = `style="filled,dashed", fillcolor="#eeeeee", fontcolor="#666666"`
+)
+
+type Generator struct {
+	rankDir  string
+	clusters bool
+	replacer *strings.Replacer
+}
+
+// NewGenerator returns a generator laying out the graph in the rank direction, default left to right, and grouping
+// the nodes of each package into a cluster when clusters is true.
+func NewGenerator(rankDir string, clusters bool) *Generator {
+	if rankDir == "" {
+		rankDir = DefaultRankDir
+	}
+	return &Generator{
+		rankDir:  rankDir,
+		clusters: clusters,
+		replacer: strings.NewReplacer(".", "_", "-", "_", "/", "_"),
+	}
+}
+
+func (g Generator) GetDefaultResultFileName() string {
+	return "diag.dot"
+}
+
+// GenerateFromSchema generates a graphviz dot digraph.
+func (g Generator) GenerateFromSchema(ctx context.Context, writer *bufio.Writer, s parse.AstSchema) error {
+	_, err := fmt.Fprintf(writer, "digraph %s {\n\trankdir=%s;\n\tnode [shape=box, style=filled, fillcolor=\"#ffffff\"];\n", quote(s.ModulePath), g.rankDir)
+	if err != nil {
+		return err
+	}
+
+	for _, packageName := range mymap.OrderedKeys(s.Graph.NodesByPackage) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		nodes := s.Graph.GetPackageNodesOutsideModule(packageName)
+		if len(nodes) == 0 {
+			continue
+		}
+		err = g.writeCluster(writer, packageName, parse.GetPackageLabel(packageName, s.ModulePath), nodes, s.ModulePath)
+		if err != nil {
+			return err
+		}
+	}
+	nodesByModule := s.Graph.GetNodesByModule()
+	for _, module := range mymap.OrderedKeys(nodesByModule) {
+		err = g.writeCluster(writer, "module_"+module, module, nodesByModule[module], s.ModulePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = writer.WriteString("\n")
+	if err != nil {
+		return err
+	}
+	for _, node := range s.Graph.GetNodesSortedByName() {
+		for _, adj := range s.Graph.GetAdjacenciesSortedByName(node) {
+			_, err = fmt.Fprintf(writer, "\t%s -> %s%s;\n", quote(node.Name), quote(adj.Node.Name), getEdgeAttributes(adj))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	_, err = writer.WriteString("}\n")
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// writeCluster writes the nodes, grouped into a cluster when the clusters are enabled.
+func (g Generator) writeCluster(writer *bufio.Writer, id, label string, nodes []*parse.Node, modulePath string) error {
+	indent := "\t"
+	if g.clusters {
+		style := ""
+		if isExternal(nodes) {
+			style = "\t\tstyle=dashed;\n\t\tcolor=\"#999999\";\n"
+		}
+		_, err := fmt.Fprintf(writer, "\n\tsubgraph %s {\n\t\tlabel=%s;\n%s", quote(clusterPrefix+g.replacer.Replace(id)), quote(label), style)
+		if err != nil {
+			return err
+		}
+		indent = "\t\t"
+	}
+	for _, node := range parse.SortNodesByName(nodes) {
+		_, err := fmt.Fprintf(writer, "%s%s [%s];\n", indent, quote(node.Name), g.getNodeAttributes(node, modulePath))
+		if err != nil {
+			return err
+		}
+	}
+	if g.clusters {
+		_, err := writer.WriteString("\t}\n")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g Generator) getNodeAttributes(node *parse.Node, modulePath string) string {
+	label := node.StructName
+	if !g.clusters && node.PackageName != modulePath {
+		label = parse.GetPackageLabel(node.PackageName, modulePath) + "." + node.StructName
+	}
+	attributes := "label=" + quote(label)
+	if node.Doc != "" {
+		attributes += ", tooltip=" + quote(node.Doc)
+	}
+	if node.External {
+		attributes += ", " + externalStyle
+	}
+	return attributes
+}
+
+// getEdgeAttributes returns the attributes of an edge, labelled with the methods of the dependency used.
+func getEdgeAttributes(adj *parse.Adj) string {
+	var attributes []string
+	if len(adj.Func) > 0 {
+		attributes = append(attributes, "label="+quote(strings.Join(adj.Func, "\n")))
+	}
+	if adj.Optional {
+		attributes = append(attributes, "style=dashed")
+	}
+	if adj.Node.External {
+		attributes = append(attributes, `color="#999999"`)
+	}
+	if len(attributes) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(attributes, ", ") + "]"
+}
+
+// quote returns a dot quoted string, the line feeds are kept as \n escapes.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+func isExternal(nodes []*parse.Node) bool {
+	for _, node := range nodes {
+		if !node.External {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package graphviz
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateFromSchema_withParse(t *testing.T) {
+	as, err := parse.Parse("../testdata/named_inter", nil)
+	require.NoError(t, err)
+
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+	err = NewGenerator("", true).GenerateFromSchema(context.Background(), buff, as)
+	require.NoError(t, err)
+	buff.Flush()
+
+	assert.Equal(t, `digraph "testdata/named_inter" {
+	rankdir=LR;
+	node [shape=box, style=filled, fillcolor="#ffffff"];
+
+	subgraph "cluster_testdata_named_inter" {
+		label="testdata/named_inter";
+		"testdata/named_inter.A" [label="A"];
+		"testdata/named_inter.B" [label="B"];
+		"testdata/named_inter.C" [label="C"];
+		"testdata/named_inter.D" [label="D"];
+	}
+
+	subgraph "cluster_testdata_named_inter_pa" {
+		label="pa";
+		"testdata/named_inter/pa.A" [label="A", tooltip="A pa struct."];
+	}
+
+	"testdata/named_inter.A" -> "testdata/named_inter.B" [label="FuncA\nFuncB"];
+	"testdata/named_inter.A" -> "testdata/named_inter.D" [label="FuncA"];
+	"testdata/named_inter.B" -> "testdata/named_inter.C" [label="FuncA"];
+	"testdata/named_inter.D" -> "testdata/named_inter/pa.A" [label="FuncFoo"];
+}
+`, file.String())
+}
+
+func getExternalSchema() parse.AstSchema {
+	graph := parse.NewGraph()
+	service := &parse.Node{Name: "mod/app.Service", PackageName: "mod/app", StructName: "Service", Doc: `The "main" service.`}
+	logger := &parse.Node{Name: "mod/app.Logger", PackageName: "mod/app", StructName: "Logger"}
+	client := &parse.Node{Name: "github.com/lib/http.Client", PackageName: "github.com/lib/http", StructName: "Client", External: true}
+	graph.AddNode(service)
+	graph.AddNode(logger)
+	graph.AddNode(client)
+	graph.AddEdge(service, &parse.Adj{Node: client, Func: []string{"Do"}})
+	graph.AddEdge(service, &parse.Adj{Node: logger, Optional: true})
+	return parse.AstSchema{ModulePath: "mod", Graph: graph}
+}
+
+func TestGenerateFromSchema_external(t *testing.T) {
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+	err := NewGenerator("", true).GenerateFromSchema(context.Background(), buff, getExternalSchema())
+	require.NoError(t, err)
+	buff.Flush()
+
+	assert.Equal(t, `digraph "mod" {
+	rankdir=LR;
+	node [shape=box, style=filled, fillcolor="#ffffff"];
+
+	subgraph "cluster_github_com_lib_http" {
+		label="github.com/lib/http";
+		style=dashed;
+		color="#999999";
+		"github.com/lib/http.Client" [label="Client", style="filled,dashed", fillcolor="#eeeeee", fontcolor="#666666"];
+	}
+
+	subgraph "cluster_mod_app" {
+		label="app";
+		"mod/app.Logger" [label="Logger"];
+		"mod/app.Service" [label="Service", tooltip="The \"main\" service."];
+	}
+
+	"mod/app.Service" -> "github.com/lib/http.Client" [label="Do", color="#999999"];
+	"mod/app.Service" -> "mod/app.Logger" [style=dashed];
+}
+`, file.String())
+}
+
+func TestGenerateFromSchema_withoutClusters(t *testing.T) {
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+	err := NewGenerator("TB", false).GenerateFromSchema(context.Background(), buff, getExternalSchema())
+	require.NoError(t, err)
+	buff.Flush()
+
+	assert.Equal(t, `digraph "mod" {
+	rankdir=TB;
+	node [shape=box, style=filled, fillcolor="#ffffff"];
+	"github.com/lib/http.Client" [label="github.com/lib/http.Client", style="filled,dashed", fillcolor="#eeeeee", fontcolor="#666666"];
+	"mod/app.Logger" [label="app.Logger"];
+	"mod/app.Service" [label="app.Service", tooltip="The \"main\" service."];
+
+	"mod/app.Service" -> "github.com/lib/http.Client" [label="Do", color="#999999"];
+	"mod/app.Service" -> "mod/app.Logger" [style=dashed];
+}
+`, file.String())
+}
@@ -0,0 +1,430 @@
+// Renders the dependency graph embedded in the page, without any network access.
+// The packages are laid out in columns by dependency depth, each package is a box listing its components.
+(function () {
+  'use strict';
+
+  var SVG_NS = 'http://www.w3.org/2000/svg';
+  var NODE_WIDTH = 220;
+  var NODE_HEIGHT = 26;
+  var HEADER_HEIGHT = 30;
+  var PADDING = 8;
+  var COLUMN_GAP = 140;
+  var ROW_GAP = 30;
+  var MAX_RESULTS = 20;
+
+  var data = JSON.parse(document.getElementById('graph-data').textContent);
+  var svg = document.getElementById('graph');
+  var viewport = document.getElementById('viewport');
+  var panel = document.getElementById('panel');
+  var search = document.getElementById('search');
+  var results = document.getElementById('results');
+
+  var nodes = {};
+  var packages = {};
+  var edges = [];
+  var state = {focus: null, matches: {}, x: 20, y: 20, k: 1};
+
+  data.nodes.forEach(function (n) {
+    n.dependencies = [];
+    n.dependents = [];
+    nodes[n.name] = n;
+    if (!packages[n.package]) {
+      packages[n.package] = {path: n.package, label: packageLabel(n.package), nodes: [], collapsed: false, external: true};
+    }
+    packages[n.package].nodes.push(n);
+    if (!n.external) {
+      packages[n.package].external = false;
+    }
+  });
+  data.edges.forEach(function (e) {
+    var from = nodes[e.from];
+    var to = nodes[e.to];
+    if (!from || !to) {
+      return;
+    }
+    from.dependencies.push(e);
+    to.dependents.push(e);
+    edges.push(e);
+  });
+  var sortedPackages = Object.keys(packages).sort().map(function (path) {
+    packages[path].nodes.sort(function (a, b) {
+      return a.name < b.name ? -1 : 1;
+    });
+    return packages[path];
+  });
+  var depths = packageDepths();
+
+  function packageLabel(path) {
+    var label = path;
+    if (path.indexOf(data.module_path) === 0) {
+      label = path.slice(data.module_path.length).replace(/^\//, '');
+    }
+    return label || path;
+  }
+
+  // packageDepths returns the longest distance of each package from the packages no other package depends on,
+  // the dependency cycles are broken arbitrarily.
+  function packageDepths() {
+    var dependencies = {};
+    var inbound = {};
+    sortedPackages.forEach(function (p) {
+      dependencies[p.path] = {};
+      inbound[p.path] = 0;
+    });
+    edges.forEach(function (e) {
+      var from = nodes[e.from].package;
+      var to = nodes[e.to].package;
+      if (from !== to && !dependencies[from][to]) {
+        dependencies[from][to] = true;
+        inbound[to]++;
+      }
+    });
+    var result = {};
+    var queue = [];
+    sortedPackages.forEach(function (p) {
+      if (inbound[p.path] === 0) {
+        result[p.path] = 0;
+        queue.push(p.path);
+      }
+    });
+    while (queue.length > 0) {
+      var path = queue.shift();
+      Object.keys(dependencies[path]).sort().forEach(function (to) {
+        result[to] = Math.max(result[to] || 0, result[path] + 1);
+        inbound[to]--;
+        if (inbound[to] === 0) {
+          queue.push(to);
+        }
+      });
+    }
+    sortedPackages.forEach(function (p) {
+      if (result[p.path] === undefined) {
+        result[p.path] = 0;
+      }
+    });
+    return result;
+  }
+
+  function layout() {
+    var columns = [];
+    sortedPackages.forEach(function (p) {
+      var depth = depths[p.path];
+      columns[depth] = columns[depth] || [];
+      columns[depth].push(p);
+    });
+    var x = 0;
+    columns.forEach(function (column) {
+      var y = 0;
+      (column || []).forEach(function (p) {
+        p.x = x;
+        p.y = y;
+        p.width = NODE_WIDTH + 2 * PADDING;
+        p.height = HEADER_HEIGHT;
+        if (!p.collapsed) {
+          p.height += p.nodes.length * (NODE_HEIGHT + PADDING) + PADDING;
+        }
+        p.nodes.forEach(function (n, i) {
+          n.x = x + PADDING;
+          n.y = y + HEADER_HEIGHT + PADDING + i * (NODE_HEIGHT + PADDING);
+        });
+        y += p.height + ROW_GAP;
+      });
+      x += NODE_WIDTH + 2 * PADDING + COLUMN_GAP;
+    });
+  }
+
+  function element(name, attributes, parent) {
+    var e = document.createElementNS(SVG_NS, name);
+    Object.keys(attributes).forEach(function (key) {
+      e.setAttribute(key, attributes[key]);
+    });
+    if (parent) {
+      parent.appendChild(e);
+    }
+    return e;
+  }
+
+  function text(content, attributes, parent) {
+    var e = element('text', attributes, parent);
+    e.textContent = content;
+    return e;
+  }
+
+  // anchor returns the box an edge of the node is drawn from, its package when collapsed.
+  function anchor(n) {
+    var p = packages[n.package];
+    if (p.collapsed) {
+      return {key: 'package:' + p.path, x: p.x, y: p.y, width: p.width, height: HEADER_HEIGHT};
+    }
+    return {key: 'node:' + n.name, x: n.x, y: n.y, width: NODE_WIDTH, height: NODE_HEIGHT};
+  }
+
+  function related(e) {
+    return state.focus !== null && (e.from === state.focus || e.to === state.focus);
+  }
+
+  function nodeClass(n) {
+    var classes = ['node'];
+    if (n.external) {
+      classes.push('external');
+    }
+    if (state.matches[n.name]) {
+      classes.push('match');
+    }
+    if (state.focus !== null) {
+      var focus = nodes[state.focus];
+      if (n.name === state.focus) {
+        classes.push('focus');
+      } else if (focus.dependencies.some(function (e) { return e.to === n.name; })) {
+        classes.push('dependency');
+      } else if (focus.dependents.some(function (e) { return e.from === n.name; })) {
+        classes.push('dependent');
+      } else {
+        classes.push('dim');
+      }
+    }
+    return classes.join(' ');
+  }
+
+  function render() {
+    layout();
+    while (viewport.firstChild) {
+      viewport.removeChild(viewport.firstChild);
+    }
+    var edgeLayer = element('g', {'class': 'edges'}, viewport);
+    var packageLayer = element('g', {'class': 'packages'}, viewport);
+
+    sortedPackages.forEach(function (p) {
+      var g = element('g', {'class': 'package' + (p.external ? ' external' : '')}, packageLayer);
+      element('rect', {x: p.x, y: p.y, width: p.width, height: p.height, rx: 6}, g);
+      var header = element('g', {'class': 'header'}, g);
+      element('rect', {x: p.x, y: p.y, width: p.width, height: HEADER_HEIGHT, rx: 6}, header);
+      text((p.collapsed ? '▸ ' : '▾ ') + p.label + ' (' + p.nodes.length + ')', {x: p.x + PADDING, y: p.y + 20}, header);
+      header.addEventListener('click', function () {
+        p.collapsed = !p.collapsed;
+        render();
+      });
+      if (p.collapsed) {
+        return;
+      }
+      p.nodes.forEach(function (n) {
+        var ng = element('g', {'class': nodeClass(n)}, g);
+        element('rect', {x: n.x, y: n.y, width: NODE_WIDTH, height: NODE_HEIGHT, rx: 4}, ng);
+        text(n.struct, {x: n.x + PADDING, y: n.y + 17}, ng);
+        element('title', {}, ng).textContent = n.name;
+        ng.addEventListener('click', function (event) {
+          event.stopPropagation();
+          focus(n.name);
+        });
+      });
+    });
+
+    var drawn = {};
+    edges.forEach(function (e) {
+      var from = anchor(nodes[e.from]);
+      var to = anchor(nodes[e.to]);
+      var key = from.key + '>' + to.key;
+      if (from.key === to.key || drawn[key]) {
+        return;
+      }
+      drawn[key] = true;
+      var x1 = from.x + from.width;
+      var y1 = from.y + from.height / 2;
+      var x2 = to.x;
+      var y2 = to.y + to.height / 2;
+      var bend = Math.max(40, Math.abs(x2 - x1) / 2);
+      var classes = ['edge'];
+      if (e.optional) {
+        classes.push('optional');
+      }
+      if (related(e)) {
+        classes.push(e.from === state.focus ? 'dependency' : 'dependent');
+      } else if (state.focus !== null) {
+        classes.push('dim');
+      }
+      var path = element('path', {
+        'class': classes.join(' '),
+        d: 'M' + x1 + ',' + y1 + ' C' + (x1 + bend) + ',' + y1 + ' ' + (x2 - bend) + ',' + y2 + ' ' + x2 + ',' + y2,
+        'marker-end': 'url(#arrow)'
+      }, edgeLayer);
+      element('title', {}, path).textContent = e.from + ' -> ' + e.to + (e.funcs ? ': ' + e.funcs.join(', ') : '');
+    });
+    applyTransform();
+  }
+
+  function applyTransform() {
+    viewport.setAttribute('transform', 'translate(' + state.x + ',' + state.y + ') scale(' + state.k + ')');
+  }
+
+  function focus(name) {
+    state.focus = name;
+    var n = nodes[name];
+    if (n && packages[n.package].collapsed) {
+      packages[n.package].collapsed = false;
+    }
+    render();
+    showPanel(n);
+  }
+
+  function center(name) {
+    var n = nodes[name];
+    var box = svg.getBoundingClientRect();
+    state.x = box.width / 2 - (n.x + NODE_WIDTH / 2) * state.k;
+    state.y = box.height / 2 - (n.y + NODE_HEIGHT / 2) * state.k;
+    applyTransform();
+  }
+
+  function item(parent, content, name) {
+    var li = document.createElement('li');
+    li.textContent = content;
+    if (name) {
+      li.className = 'link';
+      li.addEventListener('click', function () {
+        focus(name);
+        center(name);
+      });
+    }
+    parent.appendChild(li);
+  }
+
+  function section(title, entries) {
+    var h = document.createElement('h3');
+    h.textContent = title + ' (' + entries.length + ')';
+    panel.appendChild(h);
+    var ul = document.createElement('ul');
+    panel.appendChild(ul);
+    return ul;
+  }
+
+  function showPanel(n) {
+    while (panel.firstChild) {
+      panel.removeChild(panel.firstChild);
+    }
+    if (!n) {
+      panel.className = 'hidden';
+      return;
+    }
+    panel.className = '';
+    var h = document.createElement('h2');
+    h.textContent = n.struct;
+    panel.appendChild(h);
+    var p = document.createElement('p');
+    p.className = 'package-name';
+    p.textContent = n.package + (n.external ? ' (external)' : '');
+    panel.appendChild(p);
+    if (n.position) {
+      var source = document.createElement('p');
+      source.className = 'source';
+      source.textContent = n.position.file + (n.position.line ? ':' + n.position.line : '');
+      panel.appendChild(source);
+    }
+    if (n.doc) {
+      var doc = document.createElement('p');
+      doc.className = 'doc';
+      doc.textContent = n.doc;
+      panel.appendChild(doc);
+    }
+    var methods = n.methods || [];
+    var ul = section('Methods', methods);
+    methods.forEach(function (m) {
+      item(ul, m.signature);
+    });
+    ul = section('Dependencies', n.dependencies);
+    n.dependencies.forEach(function (e) {
+      item(ul, e.to + (e.funcs ? ': ' + e.funcs.join(', ') : ''), e.to);
+    });
+    ul = section('Dependents', n.dependents);
+    n.dependents.forEach(function (e) {
+      item(ul, e.from + (e.funcs ? ': ' + e.funcs.join(', ') : ''), e.from);
+    });
+  }
+
+  function onSearch() {
+    var query = search.value.trim().toLowerCase();
+    state.matches = {};
+    while (results.firstChild) {
+      results.removeChild(results.firstChild);
+    }
+    if (query !== '') {
+      var count = 0;
+      data.nodes.forEach(function (n) {
+        if (n.name.toLowerCase().indexOf(query) === -1) {
+          return;
+        }
+        state.matches[n.name] = true;
+        if (count < MAX_RESULTS) {
+          item(results, n.name, n.name);
+        }
+        count++;
+      });
+    }
+    render();
+  }
+
+  function setCollapsed(collapsed) {
+    sortedPackages.forEach(function (p) {
+      p.collapsed = collapsed;
+    });
+    render();
+  }
+
+  search.addEventListener('input', onSearch);
+  search.addEventListener('keydown', function (event) {
+    if (event.key === 'Enter' && results.firstChild) {
+      results.firstChild.click();
+    }
+  });
+  document.getElementById('collapse-all').addEventListener('click', function () {
+    setCollapsed(true);
+  });
+  document.getElementById('expand-all').addEventListener('click', function () {
+    setCollapsed(false);
+  });
+  document.getElementById('reset').addEventListener('click', function () {
+    state.x = 20;
+    state.y = 20;
+    state.k = 1;
+    state.focus = null;
+    showPanel(null);
+    render();
+  });
+
+  svg.addEventListener('wheel', function (event) {
+    event.preventDefault();
+    var box = svg.getBoundingClientRect();
+    var px = event.clientX - box.left;
+    var py = event.clientY - box.top;
+    var k = Math.min(4, Math.max(0.05, state.k * (event.deltaY < 0 ? 1.1 : 1 / 1.1)));
+    state.x = px - (px - state.x) * k / state.k;
+    state.y = py - (py - state.y) * k / state.k;
+    state.k = k;
+    applyTransform();
+  }, {passive: false});
+
+  var drag = null;
+  svg.addEventListener('mousedown', function (event) {
+    drag = {x: event.clientX, y: event.clientY};
+  });
+  window.addEventListener('mousemove', function (event) {
+    if (drag === null) {
+      return;
+    }
+    state.x += event.clientX - drag.x;
+    state.y += event.clientY - drag.y;
+    drag.x = event.clientX;
+    drag.y = event.clientY;
+    applyTransform();
+  });
+  window.addEventListener('mouseup', function () {
+    drag = null;
+  });
+  svg.addEventListener('click', function (event) {
+    if (event.target === svg && state.focus !== null) {
+      state.focus = null;
+      showPanel(null);
+      render();
+    }
+  });
+
+  render();
+})();
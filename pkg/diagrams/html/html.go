@@ -0,0 +1,67 @@
+package html
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/emilien-puget/go-dependency-graph/pkg/snapshot"
+)
+
+var (
+	//go:embed assets/template.html
+	page string
+	//go:embed assets/style.css
+	style string
+	//go:embed assets/renderer.js
+	renderer string
+)
+
+// Generator generates a single HTML page exploring the graph, the renderer and the graph are embedded in the page, it
+// works offline.
+type Generator struct {
+	template *template.Template
+}
+
+func NewGenerator() *Generator {
+	return &Generator{
+		template: template.Must(template.New("page").Parse(page)),
+	}
+}
+
+func (g Generator) GetDefaultResultFileName() string {
+	return "diag.html"
+}
+
+// GenerateFromSchema generates the HTML page, the graph is embedded as a snapshot.
+func (g Generator) GenerateFromSchema(ctx context.Context, writer *bufio.Writer, s parse.AstSchema) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	// json.Marshal escapes <, > and &, the graph cannot close the script element holding it
+	data, err := json.Marshal(snapshot.FromSchema(s))
+	if err != nil {
+		return fmt.Errorf("json.Marshal:%w", err)
+	}
+	err = g.template.Execute(writer, struct {
+		Title  string
+		Style  template.CSS
+		Data   template.JS
+		Script template.JS
+	}{
+		Title:  s.ModulePath,
+		Style:  template.CSS(style),
+		Data:   template.JS(data),
+		Script: template.JS(renderer),
+	})
+	if err != nil {
+		return fmt.Errorf("template.Execute:%w", err)
+	}
+	return nil
+}
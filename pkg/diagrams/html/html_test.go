@@ -0,0 +1,51 @@
+package html
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerator_withParse(t *testing.T) {
+	as, err := parse.Parse("../testdata/named_inter", nil)
+	require.NoError(t, err)
+
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+	err = NewGenerator().GenerateFromSchema(context.Background(), buff, as)
+	require.NoError(t, err)
+	buff.Flush()
+
+	page := file.String()
+	assert.Contains(t, page, "<title>testdata/named_inter</title>")
+	assert.Contains(t, page, `<script id="graph-data" type="application/json">{"version":1,"module_path":"testdata/named_inter"`)
+	assert.Contains(t, page, `{"name":"testdata/named_inter/pa.A","package":"testdata/named_inter/pa","struct":"A","doc":"A pa struct."`)
+	assert.Contains(t, page, `{"from":"testdata/named_inter.A","to":"testdata/named_inter.B","funcs":["FuncA","FuncB"]`)
+	assert.Contains(t, page, renderer)
+	assert.Contains(t, page, style)
+	// the page works offline
+	assert.NotContains(t, page, `src="http`)
+	assert.NotContains(t, page, `href="http`)
+	assert.NotContains(t, page, "<link")
+}
+
+func TestGenerator_escape(t *testing.T) {
+	graph := parse.NewGraph()
+	graph.AddNode(&parse.Node{Name: "mod.Service", PackageName: "mod", StructName: "Service", Doc: "Ends with </script><script>alert(1)</script>."})
+
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+	err := NewGenerator().GenerateFromSchema(context.Background(), buff, parse.AstSchema{ModulePath: "<mod>", Graph: graph})
+	require.NoError(t, err)
+	buff.Flush()
+
+	page := file.String()
+	assert.Contains(t, page, "<title>&lt;mod&gt;</title>")
+	assert.Contains(t, page, `"doc":"Ends with \u003c/script\u003e\u003cscript\u003ealert(1)\u003c/script\u003e."`)
+	assert.NotContains(t, page, "alert(1)</script>")
+}
@@ -15,6 +15,8 @@ import (
 const (
 	packageSeparator = "/"
 	mermaidSeparator = "_"
+	dependencyArrow  = "..>"
+	cycleArrow       = "-->" // a solid arrow, distinguishing the edges of a dependency cycle
 )
 
 type Generator struct {
@@ -40,6 +42,7 @@ func (g Generator) GenerateFromSchema(ctx context.Context, writer *bufio.Writer,
 
 	var classBuf bytes.Buffer
 	var relationBuf bytes.Buffer
+	cycleEdges := s.Graph.GetCycleEdges()
 
 	for _, k := range mymap.OrderedKeys(s.Graph.NodesByPackage) {
 		select {
@@ -47,7 +50,23 @@ func (g Generator) GenerateFromSchema(ctx context.Context, writer *bufio.Writer,
 			return ctx.Err()
 		default:
 		}
-		err := g.handlePackages(&classBuf, &relationBuf, k, s.Graph.NodesByPackage[k], s.Graph)
+		services := s.Graph.GetPackageNodesOutsideModule(k)
+		if len(services) == 0 {
+			continue
+		}
+		err := g.handlePackages(&classBuf, &relationBuf, g.replacer.Replace(k), services, s.Graph, cycleEdges)
+		if err != nil {
+			return err
+		}
+	}
+	nodesByModule := s.Graph.GetNodesByModule()
+	for _, module := range mymap.OrderedKeys(nodesByModule) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		err := g.handlePackages(&classBuf, &relationBuf, "module_"+g.replacer.Replace(module), nodesByModule[module], s.Graph, cycleEdges)
 		if err != nil {
 			return err
 		}
@@ -67,8 +86,8 @@ func (g Generator) GenerateFromSchema(ctx context.Context, writer *bufio.Writer,
 	return nil
 }
 
-func (g Generator) handlePackages(classBuf, relationBuf *bytes.Buffer, packageName string, services []*parse.Node, graph *parse.Graph) error {
-	_, err := fmt.Fprintf(classBuf, "\nnamespace %s {\n", g.replacer.Replace(packageName))
+func (g Generator) handlePackages(classBuf, relationBuf *bytes.Buffer, namespace string, services []*parse.Node, graph *parse.Graph, cycleEdges map[*parse.Node][]*parse.Adj) error {
+	_, err := fmt.Fprintf(classBuf, "\nnamespace %s {\n", namespace)
 	if err != nil {
 		return err
 	}
@@ -77,7 +96,7 @@ func (g Generator) handlePackages(classBuf, relationBuf *bytes.Buffer, packageNa
 		return services[i].Name < services[j].Name
 	})
 	for i := range services {
-		err := g.handleService(classBuf, relationBuf, packageName, services[i].StructName, services[i], graph)
+		err := g.handleService(classBuf, relationBuf, services[i].PackageName, services[i].StructName, services[i], graph, cycleEdges)
 		if err != nil {
 			return err
 		}
@@ -86,7 +105,7 @@ func (g Generator) handlePackages(classBuf, relationBuf *bytes.Buffer, packageNa
 	return nil
 }
 
-func (g Generator) handleService(classBuf, relationBuf *bytes.Buffer, packageName, serviceName string, service *parse.Node, graph *parse.Graph) error {
+func (g Generator) handleService(classBuf, relationBuf *bytes.Buffer, packageName, serviceName string, service *parse.Node, graph *parse.Graph, cycleEdges map[*parse.Node][]*parse.Adj) error {
 	serviceFqdn := packageName + packageSeparator + serviceName
 
 	if len(service.Methods) == 0 {
@@ -107,7 +126,11 @@ func (g Generator) handleService(classBuf, relationBuf *bytes.Buffer, packageNam
 	}
 
 	for _, d := range graph.GetAdjacenciesSortedByName(service) {
-		err := g.handleDeps(d, relationBuf, serviceFqdn)
+		arrow := dependencyArrow
+		if parse.IsCycleEdge(cycleEdges, service, d) {
+			arrow = cycleArrow
+		}
+		err := g.handleDeps(d, relationBuf, serviceFqdn, arrow)
 		if err != nil {
 			return err
 		}
@@ -115,20 +138,32 @@ func (g Generator) handleService(classBuf, relationBuf *bytes.Buffer, packageNam
 	return nil
 }
 
-func (g Generator) handleDeps(deps *parse.Adj, relationBuf *bytes.Buffer, serviceFqdn string) error {
+func (g Generator) handleDeps(deps *parse.Adj, relationBuf *bytes.Buffer, serviceFqdn, arrow string) error {
 	s := deps.Node.PackageName + packageSeparator + deps.Node.StructName
+	via := ""
+	if deps.Via != "" {
+		via = " via " + deps.Via
+	}
+	if deps.Optional {
+		via += " (optional)"
+	}
 	if len(deps.Func) != 0 {
 		sort.SliceStable(deps.Func, func(i, j int) bool {
 			return deps.Func[i] < deps.Func[j]
 		})
 		for _, fn := range deps.Func {
-			_, err := fmt.Fprintf(relationBuf, "`%s` ..> `%s`: %s\n", serviceFqdn, s, fn)
+			_, err := fmt.Fprintf(relationBuf, "`%s` %s `%s`: %s%s\n", serviceFqdn, arrow, s, fn, via)
 			if err != nil {
 				return err
 			}
 		}
+	} else if deps.Optional {
+		_, err := fmt.Fprintf(relationBuf, "`%s` %s `%s`: optional\n", serviceFqdn, arrow, s)
+		if err != nil {
+			return err
+		}
 	} else {
-		_, err := fmt.Fprintf(relationBuf, "`%s` ..> `%s`\n", serviceFqdn, s)
+		_, err := fmt.Fprintf(relationBuf, "`%s` %s `%s`\n", serviceFqdn, arrow, s)
 		if err != nil {
 			return err
 		}
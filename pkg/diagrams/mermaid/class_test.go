@@ -264,3 +264,67 @@ func TestGenerateMermaidClassFromSchema_package_name_mismatch(t *testing.T) {
 
 	assert.Equal(t, "classDiagram\n\nnamespace gopkg_in_yaml_v3 {\nclass `gopkg.in/yaml.v3/Encoder`\n}\nnamespace package_name_mismatch {\nclass `package_name_mismatch/A`\n}\n`package_name_mismatch/A` ..> `gopkg.in/yaml.v3/Encoder`\n", file.String())
 }
+
+func TestGenerateMermaidClassFromSchema_optional(t *testing.T) {
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+
+	graph := parse.NewGraph()
+	server := &parse.Node{
+		Name:        "options.Server",
+		PackageName: "options",
+		StructName:  "Server",
+	}
+	graph.AddNode(server)
+	metrics := &parse.Node{
+		Name:        "options.Metrics",
+		PackageName: "options",
+		StructName:  "Metrics",
+	}
+	graph.AddNode(metrics)
+	logger := &parse.Node{
+		Name:        "options.StdLogger",
+		PackageName: "options",
+		StructName:  "StdLogger",
+	}
+	graph.AddNode(logger)
+	graph.AddEdge(server, &parse.Adj{Node: metrics, Optional: true})
+	graph.AddEdge(server, &parse.Adj{Node: logger, Func: []string{"Log"}, Via: "options.Logger", Optional: true})
+
+	err := NewGenerator().GenerateFromSchema(context.Background(), buff, parse.AstSchema{
+		ModulePath: "testdata/options",
+		Graph:      graph,
+	})
+	buff.Flush()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "classDiagram\n\nnamespace options {\nclass `options/Metrics`\nclass `options/Server`\nclass `options/StdLogger`\n}\n`options/Server` ..> `options/Metrics`: optional\n`options/Server` ..> `options/StdLogger`: Log via options.Logger (optional)\n", file.String())
+}
+
+func TestGenerateMermaidClassFromSchema_cycle(t *testing.T) {
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+
+	graph := parse.NewGraph()
+	a := &parse.Node{Name: "testdata/cycle.A", PackageName: "testdata/cycle", StructName: "A"}
+	graph.AddNode(a)
+	b := &parse.Node{Name: "testdata/cycle.B", PackageName: "testdata/cycle", StructName: "B"}
+	graph.AddNode(b)
+	c := &parse.Node{Name: "testdata/cycle.C", PackageName: "testdata/cycle", StructName: "C"}
+	graph.AddNode(c)
+	graph.AddEdge(a, &parse.Adj{Node: b, Func: []string{"FuncB"}})
+	graph.AddEdge(b, &parse.Adj{Node: a})
+	graph.AddEdge(b, &parse.Adj{Node: c})
+
+	err := NewGenerator().GenerateFromSchema(context.Background(), buff, parse.AstSchema{
+		ModulePath: "testdata/cycle",
+		Graph:      graph,
+	})
+	buff.Flush()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "classDiagram\n\nnamespace testdata_cycle {\nclass `testdata/cycle/A`\nclass `testdata/cycle/B`\nclass `testdata/cycle/C`\n}\n"+
+		"`testdata/cycle/A` --> `testdata/cycle/B`: FuncB\n"+
+		"`testdata/cycle/B` --> `testdata/cycle/A`\n"+
+		"`testdata/cycle/B` ..> `testdata/cycle/C`\n", file.String())
+}
@@ -0,0 +1,166 @@
+package mermaid
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	mymap "github.com/emilien-puget/go-dependency-graph/pkg/map"
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+)
+
+const (
+	DefaultFlowchartDirection = "LR"
+
+	flowchartArrow         = "-->"
+	flowchartOptionalArrow = "-.->"
+	flowchartCycleArrow    = "==>" // a thick arrow, distinguishing the edges of a dependency cycle
+)
+
+// FlowchartGenerator generates a mermaid flowchart, the packages are drawn as subgraphs, which GitHub renders.
+type FlowchartGenerator struct {
+	direction string
+	linkBase  string
+	replacer  *strings.Replacer
+	escaper   *strings.Replacer
+}
+
+// NewFlowchartGenerator returns a generator laying out the flowchart in the direction, TB, LR, BT or RL, default LR.
+// The nodes link to their source file, relative to the project, appended to linkBase when set.
+func NewFlowchartGenerator(direction, linkBase string) *FlowchartGenerator {
+	if direction == "" {
+		direction = DefaultFlowchartDirection
+	}
+	return &FlowchartGenerator{
+		direction: direction,
+		linkBase:  linkBase,
+		replacer:  strings.NewReplacer(".", mermaidSeparator, "-", mermaidSeparator, "/", mermaidSeparator),
+		escaper:   strings.NewReplacer(`"`, "#quot;", "\n", " "),
+	}
+}
+
+func (g FlowchartGenerator) GetDefaultResultFileName() string {
+	return "diag.mermaid"
+}
+
+// GenerateFromSchema generates a flowchart for mermaid.
+func (g FlowchartGenerator) GenerateFromSchema(ctx context.Context, writer *bufio.Writer, s parse.AstSchema) error {
+	_, err := fmt.Fprintf(writer, "flowchart %s\n", g.direction)
+	if err != nil {
+		return err
+	}
+
+	var links []string
+	for _, packageName := range mymap.OrderedKeys(s.Graph.NodesByPackage) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		nodes := s.Graph.GetPackageNodesOutsideModule(packageName)
+		if len(nodes) == 0 {
+			continue
+		}
+		subgraphLinks, err := g.writeSubgraph(writer, g.replacer.Replace(packageName), parse.GetPackageLabel(packageName, s.ModulePath), nodes, s)
+		if err != nil {
+			return err
+		}
+		links = append(links, subgraphLinks...)
+	}
+	nodesByModule := s.Graph.GetNodesByModule()
+	for _, module := range mymap.OrderedKeys(nodesByModule) {
+		subgraphLinks, err := g.writeSubgraph(writer, "module_"+g.replacer.Replace(module), module, nodesByModule[module], s)
+		if err != nil {
+			return err
+		}
+		links = append(links, subgraphLinks...)
+	}
+
+	_, err = writer.WriteString("\n")
+	if err != nil {
+		return err
+	}
+	cycleEdges := s.Graph.GetCycleEdges()
+	for _, node := range s.Graph.GetNodesSortedByName() {
+		for _, adj := range s.Graph.GetAdjacenciesSortedByName(node) {
+			arrow := flowchartArrow
+			switch {
+			case parse.IsCycleEdge(cycleEdges, node, adj):
+				arrow = flowchartCycleArrow
+			case adj.Optional:
+				arrow = flowchartOptionalArrow
+			}
+			if len(adj.Func) > 0 {
+				arrow += fmt.Sprintf("|\"%s\"|", g.escaper.Replace(strings.Join(adj.Func, ", ")))
+			}
+			_, err = fmt.Fprintf(writer, "%s %s %s\n", g.getID(node), arrow, g.getID(adj.Node))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if len(links) > 0 {
+		_, err = writer.WriteString("\n" + strings.Join(links, "\n") + "\n")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeSubgraph writes a subgraph holding the nodes and returns their click links.
+func (g FlowchartGenerator) writeSubgraph(writer *bufio.Writer, id, label string, nodes []*parse.Node, s parse.AstSchema) ([]string, error) {
+	var b strings.Builder
+	var links []string
+	fmt.Fprintf(&b, "\nsubgraph %s[\"%s\"]\n", id, g.escaper.Replace(label))
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+	for _, node := range nodes {
+		label := g.escaper.Replace(node.StructName)
+		if node.External {
+			// a stadium shape distinguishes the nodes outside the project
+			fmt.Fprintf(&b, "%s([\"%s\"])\n", g.getID(node), label)
+		} else {
+			fmt.Fprintf(&b, "%s[\"%s\"]\n", g.getID(node), label)
+		}
+		if link := g.getLink(node, s.Dir); link != "" {
+			tooltip := ""
+			if node.Doc != "" {
+				tooltip = fmt.Sprintf(" \"%s\"", g.escaper.Replace(node.Doc))
+			}
+			links = append(links, fmt.Sprintf("click %s href \"%s\"%s _blank", g.getID(node), link, tooltip))
+		}
+	}
+	b.WriteString("end\n")
+	_, err := writer.WriteString(b.String())
+	if err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// getLink returns the link to the source file of the node, relative to the project dir, empty for the external nodes.
+func (g FlowchartGenerator) getLink(node *parse.Node, dir string) string {
+	if node.External || node.FilePath == "" {
+		return ""
+	}
+	file := node.FilePath
+	if dir != "" {
+		if rel, err := filepath.Rel(dir, file); err == nil {
+			file = rel
+		}
+	}
+	file = filepath.ToSlash(file)
+	if g.linkBase == "" {
+		return file
+	}
+	return strings.TrimSuffix(g.linkBase, "/") + "/" + strings.TrimPrefix(file, "/")
+}
+
+func (g FlowchartGenerator) getID(node *parse.Node) string {
+	return g.replacer.Replace(node.Name)
+}
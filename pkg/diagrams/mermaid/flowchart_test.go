@@ -0,0 +1,95 @@
+package mermaid
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlowchartGenerator_withParse(t *testing.T) {
+	as, err := parse.Parse("../testdata/named_inter", nil)
+	require.NoError(t, err)
+
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+	err = NewFlowchartGenerator("", "https://github.com/org/repo/blob/main/").GenerateFromSchema(context.Background(), buff, as)
+	require.NoError(t, err)
+	buff.Flush()
+
+	assert.Equal(t, `flowchart LR
+
+subgraph testdata_named_inter["testdata/named_inter"]
+testdata_named_inter_A["A"]
+testdata_named_inter_B["B"]
+testdata_named_inter_C["C"]
+testdata_named_inter_D["D"]
+end
+
+subgraph testdata_named_inter_pa["pa"]
+testdata_named_inter_pa_A["A"]
+end
+
+testdata_named_inter_A -->|"FuncA, FuncB"| testdata_named_inter_B
+testdata_named_inter_A -->|"FuncA"| testdata_named_inter_D
+testdata_named_inter_B -->|"FuncA"| testdata_named_inter_C
+testdata_named_inter_D -->|"FuncFoo"| testdata_named_inter_pa_A
+
+click testdata_named_inter_A href "https://github.com/org/repo/blob/main/a.go" _blank
+click testdata_named_inter_B href "https://github.com/org/repo/blob/main/b.go" _blank
+click testdata_named_inter_C href "https://github.com/org/repo/blob/main/c.go" _blank
+click testdata_named_inter_D href "https://github.com/org/repo/blob/main/d.go" _blank
+click testdata_named_inter_pa_A href "https://github.com/org/repo/blob/main/pa/a.go" "A pa struct." _blank
+`, file.String())
+}
+
+func TestFlowchartGenerator(t *testing.T) {
+	graph := parse.NewGraph()
+	service := &parse.Node{Name: "mod/app.Service", PackageName: "mod/app", StructName: "Service", FilePath: "/src/mod/app/service.go", Doc: `The "main" service.`}
+	repository := &parse.Node{Name: "mod/app.Repository", PackageName: "mod/app", StructName: "Repository", FilePath: "/src/mod/app/repository.go"}
+	logger := &parse.Node{Name: "mod/log.Logger", PackageName: "mod/log", StructName: "Logger", FilePath: "/src/mod/log/logger.go"}
+	client := &parse.Node{Name: "github.com/lib/http.Client", PackageName: "github.com/lib/http", StructName: "Client", External: true}
+	graph.AddNode(service)
+	graph.AddNode(repository)
+	graph.AddNode(logger)
+	graph.AddNode(client)
+	graph.AddEdge(service, &parse.Adj{Node: repository, Func: []string{"Get"}})
+	graph.AddEdge(repository, &parse.Adj{Node: service, Func: []string{"Notify"}})
+	graph.AddEdge(service, &parse.Adj{Node: logger, Optional: true})
+	graph.AddEdge(service, &parse.Adj{Node: client, Func: []string{"Do"}})
+
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+	err := NewFlowchartGenerator("TB", "").GenerateFromSchema(context.Background(), buff, parse.AstSchema{ModulePath: "mod", Dir: "/src/mod", Graph: graph})
+	require.NoError(t, err)
+	buff.Flush()
+
+	assert.Equal(t, `flowchart TB
+
+subgraph github_com_lib_http["github.com/lib/http"]
+github_com_lib_http_Client(["Client"])
+end
+
+subgraph mod_app["app"]
+mod_app_Repository["Repository"]
+mod_app_Service["Service"]
+end
+
+subgraph mod_log["log"]
+mod_log_Logger["Logger"]
+end
+
+mod_app_Repository ==>|"Notify"| mod_app_Service
+mod_app_Service -->|"Do"| github_com_lib_http_Client
+mod_app_Service ==>|"Get"| mod_app_Repository
+mod_app_Service -.-> mod_log_Logger
+
+click mod_app_Repository href "app/repository.go" _blank
+click mod_app_Service href "app/service.go" "The #quot;main#quot; service." _blank
+click mod_log_Logger href "log/logger.go" _blank
+`, file.String())
+}
@@ -0,0 +1,241 @@
+package sequence
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+)
+
+const DefaultDepth = 3
+
+var (
+	errUnknownEntry   = errors.New("unknown entry method")
+	errAmbiguousEntry = errors.New("ambiguous entry method")
+)
+
+// syntax writes the statements of a sequence diagram language.
+type syntax interface {
+	header(title string) string
+	participant(id, label string) string
+	message(from, to, method string) string
+	blockStart(kind parse.StepKind, label string) string
+	blockElse(label string) string
+	blockEnd() string
+	footer() string
+}
+
+// Generator generates the sequence diagram of an entry method, the calls it makes on its dependencies are followed
+// through the graph, the loops and conditionals holding them are drawn as loop and alt blocks.
+// The calls of the methods of the receiver are followed, without counting in the depth.
+type Generator struct {
+	syntax   syntax
+	fileName string
+	entry    string
+	depth    int
+	replacer *strings.Replacer
+}
+
+// NewPlantUMLGenerator returns a generator of a PlantUML sequence diagram of the entry method, such as
+// handlers.OrderHandler.Create, following the calls of the dependencies down to depth, DefaultDepth when not positive.
+func NewPlantUMLGenerator(entry string, depth int) *Generator {
+	return newGenerator(plantUML{}, "sequence.puml", entry, depth)
+}
+
+// NewMermaidGenerator returns a generator of a mermaid sequence diagram of the entry method, such as
+// handlers.OrderHandler.Create, following the calls of the dependencies down to depth, DefaultDepth when not positive.
+func NewMermaidGenerator(entry string, depth int) *Generator {
+	return newGenerator(mermaid{escaper: strings.NewReplacer("#", "#35;", ";", "#59;", "\n", " ")}, "sequence.mermaid", entry, depth)
+}
+
+func newGenerator(s syntax, fileName, entry string, depth int) *Generator {
+	if depth <= 0 {
+		depth = DefaultDepth
+	}
+	return &Generator{
+		syntax:   s,
+		fileName: fileName,
+		entry:    entry,
+		depth:    depth,
+		replacer: strings.NewReplacer(".", "_", "-", "_", "/", "_"),
+	}
+}
+
+func (g Generator) GetDefaultResultFileName() string {
+	return g.fileName
+}
+
+func (g Generator) GenerateFromSchema(ctx context.Context, writer *bufio.Writer, s parse.AstSchema) error {
+	node, method, err := findEntry(s.Graph, g.entry)
+	if err != nil {
+		return fmt.Errorf("%s: %w", g.entry, err)
+	}
+
+	d := diagram{generator: g, participants: make(map[*parse.Node]bool)}
+	d.addParticipant(node)
+	err = d.walk(ctx, node, node.Sequences[method], g.depth, []string{node.Name + "." + method})
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.WriteString(g.syntax.header(getLabel(node) + "." + method))
+	if err != nil {
+		return err
+	}
+	for _, participant := range d.order {
+		_, err = writer.WriteString(g.syntax.participant(g.getID(participant), getLabel(participant)))
+		if err != nil {
+			return err
+		}
+	}
+	_, err = writer.WriteString(d.body.String())
+	if err != nil {
+		return err
+	}
+	_, err = writer.WriteString(g.syntax.footer())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (g Generator) getID(node *parse.Node) string {
+	return g.replacer.Replace(node.Name)
+}
+
+// diagram holds the statements of the diagram and its participants, in the order they are called.
+type diagram struct {
+	generator    Generator
+	participants map[*parse.Node]bool
+	order        []*parse.Node
+	body         strings.Builder
+}
+
+func (d *diagram) addParticipant(node *parse.Node) {
+	if d.participants[node] {
+		return
+	}
+	d.participants[node] = true
+	d.order = append(d.order, node)
+}
+
+// walk writes the steps of a method of the node, depth is the number of dependencies whose calls are drawn, the
+// methods on the stack are not followed again.
+func (d *diagram) walk(ctx context.Context, node *parse.Node, steps []parse.Step, depth int, stack []string) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	syntax := d.generator.syntax
+	for _, step := range steps {
+		switch step.Kind {
+		case parse.StepCall:
+			if len(step.Nodes) == 0 {
+				d.body.WriteString(syntax.message(d.generator.getID(node), d.generator.getID(node), step.Callee))
+				err := d.follow(ctx, node, step.Callee, depth, stack)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			if len(step.Nodes) > 1 {
+				// the call is made on an interface implemented by several dependencies
+				d.body.WriteString(syntax.blockStart(parse.StepAlt, getLabel(step.Nodes[0])))
+			}
+			for i, dependency := range step.Nodes {
+				if i > 0 {
+					d.body.WriteString(syntax.blockElse(getLabel(dependency)))
+				}
+				d.addParticipant(dependency)
+				d.body.WriteString(syntax.message(d.generator.getID(node), d.generator.getID(dependency), step.Callee))
+				if depth > 1 {
+					err := d.follow(ctx, dependency, step.Callee, depth-1, stack)
+					if err != nil {
+						return err
+					}
+				}
+			}
+			if len(step.Nodes) > 1 {
+				d.body.WriteString(syntax.blockEnd())
+			}
+		case parse.StepLoop, parse.StepAlt, parse.StepPar:
+			for i, branch := range step.Branches {
+				if i == 0 {
+					d.body.WriteString(syntax.blockStart(step.Kind, branch.Label))
+				} else {
+					d.body.WriteString(syntax.blockElse(branch.Label))
+				}
+				err := d.walk(ctx, node, branch.Steps, depth, stack)
+				if err != nil {
+					return err
+				}
+			}
+			d.body.WriteString(syntax.blockEnd())
+		}
+	}
+	return nil
+}
+
+// follow writes the steps of the method of the node, unless it is on the stack, a recursive call.
+func (d *diagram) follow(ctx context.Context, node *parse.Node, method string, depth int, stack []string) error {
+	name := node.Name + "." + method
+	for _, called := range stack {
+		if called == name {
+			return nil
+		}
+	}
+	return d.walk(ctx, node, node.Sequences[method], depth, append(stack, name))
+}
+
+// findEntry returns the node and the method of the entry, the package of the node may be given by the end of its
+// path, such as handlers.OrderHandler.Create.
+func findEntry(graph *parse.Graph, entry string) (*parse.Node, string, error) {
+	i := strings.LastIndex(entry, ".")
+	if i <= 0 {
+		return nil, "", errUnknownEntry
+	}
+	structName, method := entry[:i], entry[i+1:]
+
+	var found []*parse.Node
+	for _, node := range graph.GetNodesSortedByName() {
+		if node.Name == structName || strings.HasSuffix(node.Name, "/"+structName) {
+			found = append(found, node)
+		}
+	}
+	switch {
+	case len(found) == 0:
+		return nil, "", errUnknownEntry
+	case len(found) > 1:
+		names := make([]string, 0, len(found))
+		for _, node := range found {
+			names = append(names, node.Name)
+		}
+		return nil, "", fmt.Errorf("%s: %w", strings.Join(names, ", "), errAmbiguousEntry)
+	}
+	if !hasMethod(found[0], method) {
+		return nil, "", fmt.Errorf("%s has no method %s: %w", found[0].Name, method, errUnknownEntry)
+	}
+	return found[0], method, nil
+}
+
+func hasMethod(node *parse.Node, method string) bool {
+	if _, ok := node.Sequences[method]; ok {
+		return true
+	}
+	for _, m := range node.Methods {
+		if m.GetName() == method {
+			return true
+		}
+	}
+	return false
+}
+
+// getLabel returns the name of the node qualified by the last element of its package.
+func getLabel(node *parse.Node) string {
+	return path.Base(node.PackageName) + "." + node.StructName
+}
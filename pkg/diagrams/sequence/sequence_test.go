@@ -0,0 +1,172 @@
+package sequence
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generate(t *testing.T, g *Generator, s parse.AstSchema) string {
+	t.Helper()
+	file := &bytes.Buffer{}
+	buff := bufio.NewWriter(file)
+	err := g.GenerateFromSchema(context.Background(), buff, s)
+	require.NoError(t, err)
+	buff.Flush()
+	return file.String()
+}
+
+func TestPlantUMLGenerator_withParse(t *testing.T) {
+	as, err := parse.Parse("../../parse/testdata/sequence", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, `@startuml
+title handlers.OrderHandler.Create
+
+participant "handlers.OrderHandler" as testdata_sequence_handlers_OrderHandler
+participant "orders.Store" as testdata_sequence_orders_Store
+participant "orders.Service" as testdata_sequence_orders_Service
+participant "orders.Payments" as testdata_sequence_orders_Payments
+testdata_sequence_handlers_OrderHandler -> testdata_sequence_orders_Store : Count
+alt kind == "free"
+testdata_sequence_handlers_OrderHandler -> testdata_sequence_handlers_OrderHandler : log
+testdata_sequence_handlers_OrderHandler -> testdata_sequence_orders_Store : Count
+else default
+testdata_sequence_handlers_OrderHandler -> testdata_sequence_orders_Service : Place
+loop for _, item := range items
+testdata_sequence_orders_Service -> testdata_sequence_orders_Store : Insert
+end
+testdata_sequence_orders_Service -> testdata_sequence_orders_Payments : Charge
+alt if err != nil
+testdata_sequence_orders_Service -> testdata_sequence_orders_Payments : Refund
+end
+end
+@enduml
+`, generate(t, NewPlantUMLGenerator("handlers.OrderHandler.Create", 0), as))
+}
+
+// getSchema returns a service notifying through an interface implemented twice, and a repository calling the
+// service back.
+func getSchema() parse.AstSchema {
+	graph := parse.NewGraph()
+	service := &parse.Node{Name: "mod/app.Service", PackageName: "mod/app", StructName: "Service"}
+	repository := &parse.Node{Name: "mod/app.Repository", PackageName: "mod/app", StructName: "Repository"}
+	mailer := &parse.Node{Name: "mod/notify.Mailer", PackageName: "mod/notify", StructName: "Mailer"}
+	sms := &parse.Node{Name: "mod/notify.SMS", PackageName: "mod/notify", StructName: "SMS"}
+	service.Sequences = map[string][]parse.Step{
+		"Save": {
+			{Kind: parse.StepCall, Callee: "Insert", Field: "repo", Nodes: []*parse.Node{repository}},
+			{Kind: parse.StepAlt, Branches: []parse.Branch{
+				{Label: "if n.Kind == \"urgent\"; n.Retry", Steps: []parse.Step{{Kind: parse.StepCall, Callee: "Notify", Field: "notifier", Nodes: []*parse.Node{mailer, sms}}}},
+			}},
+		},
+		"Refresh": {
+			{Kind: parse.StepCall, Callee: "Save"},
+		},
+	}
+	repository.Sequences = map[string][]parse.Step{
+		"Insert": {
+			{Kind: parse.StepCall, Callee: "Refresh", Field: "service", Nodes: []*parse.Node{service}},
+		},
+	}
+	mailer.Sequences = map[string][]parse.Step{
+		"Notify": {
+			{Kind: parse.StepCall, Callee: "Render", Field: "templates", Nodes: []*parse.Node{repository}},
+		},
+	}
+	graph.AddNode(service)
+	graph.AddNode(repository)
+	graph.AddNode(mailer)
+	graph.AddNode(sms)
+	return parse.AstSchema{ModulePath: "mod", Graph: graph}
+}
+
+func TestMermaidGenerator(t *testing.T) {
+	// Save is on the stack when called back, it is not followed again
+	assert.Equal(t, `sequenceDiagram
+title app.Service.Save
+
+participant mod_app_Service as app.Service
+participant mod_app_Repository as app.Repository
+participant mod_notify_Mailer as notify.Mailer
+participant mod_notify_SMS as notify.SMS
+mod_app_Service->>mod_app_Repository: Insert
+mod_app_Repository->>mod_app_Service: Refresh
+mod_app_Service->>mod_app_Service: Save
+alt if n.Kind == "urgent"#59; n.Retry
+alt notify.Mailer
+mod_app_Service->>mod_notify_Mailer: Notify
+mod_notify_Mailer->>mod_app_Repository: Render
+else notify.SMS
+mod_app_Service->>mod_notify_SMS: Notify
+end
+end
+`, generate(t, NewMermaidGenerator("app.Service.Save", 3), getSchema()))
+}
+
+func TestMermaidGenerator_depth(t *testing.T) {
+	assert.Equal(t, `sequenceDiagram
+title app.Service.Save
+
+participant mod_app_Service as app.Service
+participant mod_app_Repository as app.Repository
+participant mod_notify_Mailer as notify.Mailer
+participant mod_notify_SMS as notify.SMS
+mod_app_Service->>mod_app_Repository: Insert
+alt if n.Kind == "urgent"#59; n.Retry
+alt notify.Mailer
+mod_app_Service->>mod_notify_Mailer: Notify
+else notify.SMS
+mod_app_Service->>mod_notify_SMS: Notify
+end
+end
+`, generate(t, NewMermaidGenerator("mod/app.Service.Save", 1), getSchema()))
+}
+
+func TestGenerator_entry(t *testing.T) {
+	graph := getSchema().Graph
+	graph.AddNode(&parse.Node{Name: "mod/other/app.Service", PackageName: "mod/other/app", StructName: "Service"})
+	s := parse.AstSchema{ModulePath: "mod", Graph: graph}
+
+	for entry, expected := range map[string]error{
+		"Save":                  errUnknownEntry,
+		"app.Unknown.Save":      errUnknownEntry,
+		"notify.Mailer.Unknown": errUnknownEntry,
+		"app.Service.Save":      errAmbiguousEntry,
+	} {
+		err := NewMermaidGenerator(entry, 0).GenerateFromSchema(context.Background(), bufio.NewWriter(&bytes.Buffer{}), s)
+		assert.ErrorIs(t, err, expected, entry)
+	}
+	_, _, err := findEntry(graph, "mod/other/app.Service.Save")
+	assert.ErrorIs(t, err, errUnknownEntry)
+	node, method, err := findEntry(graph, "notify.Mailer.Notify")
+	require.NoError(t, err)
+	assert.Equal(t, "mod/notify.Mailer", node.Name)
+	assert.Equal(t, "Notify", method)
+}
+
+func TestPlantUMLGenerator_deferAndGo(t *testing.T) {
+	as, err := parse.Parse("../../parse/testdata/sequence", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, `@startuml
+title orders.Service.Cancel
+
+participant "orders.Service" as testdata_sequence_orders_Service
+participant "orders.Store" as testdata_sequence_orders_Store
+participant "orders.Payments" as testdata_sequence_orders_Payments
+testdata_sequence_orders_Service -> testdata_sequence_orders_Store : Count
+par go s.payments.Charge(s.store.Count())
+testdata_sequence_orders_Service -> testdata_sequence_orders_Payments : Charge
+end
+testdata_sequence_orders_Service -> testdata_sequence_orders_Store : Insert
+testdata_sequence_orders_Service -> testdata_sequence_orders_Payments : Refund
+testdata_sequence_orders_Service -> testdata_sequence_orders_Store : Count
+@enduml
+`, generate(t, NewPlantUMLGenerator("orders.Service.Cancel", DefaultDepth), as))
+}
@@ -0,0 +1,70 @@
+package sequence
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+)
+
+type plantUML struct{}
+
+func (plantUML) header(title string) string {
+	return fmt.Sprintf("@startuml\ntitle %s\n\n", title)
+}
+
+func (plantUML) participant(id, label string) string {
+	return fmt.Sprintf("participant \"%s\" as %s\n", label, id)
+}
+
+func (plantUML) message(from, to, method string) string {
+	return fmt.Sprintf("%s -> %s : %s\n", from, to, method)
+}
+
+func (plantUML) blockStart(kind parse.StepKind, label string) string {
+	return fmt.Sprintf("%s %s\n", kind, strings.ReplaceAll(label, "\n", " "))
+}
+
+func (plantUML) blockElse(label string) string {
+	return fmt.Sprintf("else %s\n", strings.ReplaceAll(label, "\n", " "))
+}
+
+func (plantUML) blockEnd() string {
+	return "end\n"
+}
+
+func (plantUML) footer() string {
+	return "@enduml\n"
+}
+
+type mermaid struct {
+	escaper *strings.Replacer
+}
+
+func (m mermaid) header(title string) string {
+	return fmt.Sprintf("sequenceDiagram\ntitle %s\n\n", m.escaper.Replace(title))
+}
+
+func (m mermaid) participant(id, label string) string {
+	return fmt.Sprintf("participant %s as %s\n", id, m.escaper.Replace(label))
+}
+
+func (m mermaid) message(from, to, method string) string {
+	return fmt.Sprintf("%s->>%s: %s\n", from, to, m.escaper.Replace(method))
+}
+
+func (m mermaid) blockStart(kind parse.StepKind, label string) string {
+	return fmt.Sprintf("%s %s\n", kind, m.escaper.Replace(label))
+}
+
+func (m mermaid) blockElse(label string) string {
+	return fmt.Sprintf("else %s\n", m.escaper.Replace(label))
+}
+
+func (mermaid) blockEnd() string {
+	return "end\n"
+}
+
+func (mermaid) footer() string {
+	return ""
+}
@@ -0,0 +1,14 @@
+// Package xmltext escapes the text written in the XML diagrams.
+package xmltext
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// Escape returns the text escaped to be written as XML character data or attribute value.
+func Escape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
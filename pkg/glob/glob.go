@@ -0,0 +1,40 @@
+// Package glob matches the package paths with globs.
+package glob
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Compile converts a package glob to a regular expression, * matches within a path element, ? matches a character of
+// a path element, and ** matches across path elements, such as **/domain or internal/**, which also match domain and
+// internal.
+func Compile(glob string) *regexp.Regexp {
+	return regexp.MustCompile(toRegexp(glob))
+}
+
+func toRegexp(glob string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch {
+		case glob[i:] == "/**":
+			b.WriteString("(?:/.*)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case glob[i] == '*':
+			b.WriteString("[^/]*")
+		case glob[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
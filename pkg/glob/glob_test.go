@@ -0,0 +1,36 @@
+package glob
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		glob    string
+		path    string
+		matches bool
+	}{
+		{glob: "domain", path: "domain", matches: true},
+		{glob: "domain", path: "a/domain", matches: false},
+		{glob: "*/domain", path: "a/domain", matches: true},
+		{glob: "*/domain", path: "a/b/domain", matches: false},
+		{glob: "**/domain", path: "a/b/domain", matches: true},
+		{glob: "**/domain", path: "domain", matches: true},
+		{glob: "**/domain", path: "subdomain", matches: false},
+		{glob: "internal/**", path: "internal/a/b", matches: true},
+		{glob: "internal/**", path: "internal", matches: true},
+		{glob: "internal/**", path: "internals", matches: false},
+		{glob: "a/**/b", path: "a/b", matches: true},
+		{glob: "a/**/b", path: "a/x/y/b", matches: true},
+		{glob: "**", path: "a/b", matches: true},
+		{glob: "internal/?", path: "internal/a", matches: true},
+		{glob: "a.b", path: "axb", matches: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.glob+" "+tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.matches, Compile(tt.glob).MatchString(tt.path))
+		})
+	}
+}
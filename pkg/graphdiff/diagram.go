@@ -0,0 +1,156 @@
+package graphdiff
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+)
+
+const (
+	addedColor   = "green"
+	removedColor = "red"
+	changedColor = "orange"
+)
+
+var idReplacer = strings.NewReplacer(".", "_", "-", "_", "/", "_")
+
+// WriteC4 writes a C4 plantuml component diagram of both graphs, the added elements are drawn in green, the removed
+// ones in red and the edges using other methods in orange.
+func WriteC4(w io.Writer, d Diff) error {
+	var b strings.Builder
+	b.WriteString("@startuml\n!include https://raw.githubusercontent.com/plantuml-stdlib/C4-PlantUML/master/C4_Component.puml\n")
+	b.WriteString("\ntitle " + d.ModulePath)
+	fmt.Fprintf(&b, "\nAddElementTag(%q, $bgColor=%q, $legendText=\"added\")", StatusAdded, addedColor)
+	fmt.Fprintf(&b, "\nAddElementTag(%q, $bgColor=%q, $legendText=\"removed\")", StatusRemoved, removedColor)
+	fmt.Fprintf(&b, "\nAddRelTag(%q, $textColor=%q, $lineColor=%q, $legendText=\"added\")", StatusAdded, addedColor, addedColor)
+	fmt.Fprintf(&b, "\nAddRelTag(%q, $textColor=%q, $lineColor=%q, $legendText=\"removed\")", StatusRemoved, removedColor, removedColor)
+	fmt.Fprintf(&b, "\nAddRelTag(%q, $textColor=%q, $lineColor=%q, $legendText=\"changed\")", StatusChanged, changedColor, changedColor)
+
+	nodesByPackage := getNodesByPackage(d.Nodes)
+	for _, packageName := range getSortedPackages(nodesByPackage) {
+		nodes := nodesByPackage[packageName]
+		if nodes[0].External {
+			b.WriteString("\n\n")
+			for _, n := range nodes {
+				fmt.Fprintf(&b, "Component_Ext(%q, %q, \"\", \"\"%s)\n", getID(n.Name, d.ModulePath), n.Name, getC4Tags(n.Status))
+			}
+			continue
+		}
+		label := parse.GetPackageLabel(packageName, d.ModulePath)
+		fmt.Fprintf(&b, "\n\nContainer_Boundary(%s, %q) {\n", getID(packageName, d.ModulePath), label)
+		for _, n := range nodes {
+			fmt.Fprintf(&b, "Component(%q, %q, \"\", \"\"%s)\n", getID(n.Name, d.ModulePath), getNodeLabel(n, d.ModulePath), getC4Tags(n.Status))
+		}
+		b.WriteString("\n}\n")
+	}
+	for _, e := range d.Edges {
+		fmt.Fprintf(&b, "Rel(%q, %q, %q%s)\n", getID(e.From, d.ModulePath), getID(e.To, d.ModulePath), getEdgeLabel(e), getC4Tags(e.Status))
+	}
+	b.WriteString("\n@enduml")
+	_, err := io.WriteString(w, b.String())
+	if err != nil {
+		return fmt.Errorf("io.WriteString:%w", err)
+	}
+	return nil
+}
+
+func getC4Tags(s Status) string {
+	if s == StatusUnchanged {
+		return ""
+	}
+	return fmt.Sprintf(", $tags=%q", s)
+}
+
+// WriteMermaid writes a mermaid flowchart of both graphs, the added elements are drawn in green, the removed ones in
+// red and the edges using other methods in orange.
+func WriteMermaid(w io.Writer, d Diff) error {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	fmt.Fprintf(&b, "classDef %s stroke:%s,color:%s\n", StatusAdded, addedColor, addedColor)
+	fmt.Fprintf(&b, "classDef %s stroke:%s,color:%s,stroke-dasharray:5\n", StatusRemoved, removedColor, removedColor)
+
+	nodesByPackage := getNodesByPackage(d.Nodes)
+	for _, packageName := range getSortedPackages(nodesByPackage) {
+		fmt.Fprintf(&b, "\nsubgraph %s[%q]\n", getID(packageName, d.ModulePath), parse.GetPackageLabel(packageName, d.ModulePath))
+		for _, n := range nodesByPackage[packageName] {
+			fmt.Fprintf(&b, "%s[%q]%s\n", getID(n.Name, d.ModulePath), getNodeLabel(n, d.ModulePath), getMermaidClass(n.Status))
+		}
+		b.WriteString("end\n")
+	}
+	b.WriteString("\n")
+	var styles []string
+	for i, e := range d.Edges {
+		arrow := "-->"
+		if label := getEdgeLabel(e); label != "" {
+			arrow += fmt.Sprintf("|%q|", label)
+		}
+		fmt.Fprintf(&b, "%s %s %s\n", getID(e.From, d.ModulePath), arrow, getID(e.To, d.ModulePath))
+		switch e.Status {
+		case StatusAdded:
+			styles = append(styles, fmt.Sprintf("linkStyle %d stroke:%s,color:%s", i, addedColor, addedColor))
+		case StatusRemoved:
+			styles = append(styles, fmt.Sprintf("linkStyle %d stroke:%s,color:%s,stroke-dasharray:5", i, removedColor, removedColor))
+		case StatusChanged:
+			styles = append(styles, fmt.Sprintf("linkStyle %d stroke:%s,color:%s", i, changedColor, changedColor))
+		}
+	}
+	for _, s := range styles {
+		b.WriteString(s + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	if err != nil {
+		return fmt.Errorf("io.WriteString:%w", err)
+	}
+	return nil
+}
+
+func getMermaidClass(s Status) string {
+	if s == StatusAdded || s == StatusRemoved {
+		return ":::" + string(s)
+	}
+	return ""
+}
+
+func getNodesByPackage(nodes []Node) map[string][]Node {
+	nodesByPackage := make(map[string][]Node)
+	for _, n := range nodes {
+		nodesByPackage[n.Package] = append(nodesByPackage[n.Package], n)
+	}
+	return nodesByPackage
+}
+
+func getSortedPackages(nodesByPackage map[string][]Node) []string {
+	packages := make([]string, 0, len(nodesByPackage))
+	for p := range nodesByPackage {
+		packages = append(packages, p)
+	}
+	sort.Strings(packages)
+	return packages
+}
+
+// getID returns the identifier of a node or a package, its name relative to the module.
+func getID(name, modulePath string) string {
+	id := strings.TrimLeft(strings.TrimPrefix(name, modulePath), "/.")
+	if id == "" {
+		id = name
+	}
+	return idReplacer.Replace(id)
+}
+
+func getNodeLabel(n Node, modulePath string) string {
+	if n.External {
+		return n.Name
+	}
+	if n.Package == modulePath {
+		return n.Struct
+	}
+	return parse.GetPackageLabel(n.Package, modulePath) + "." + n.Struct
+}
+
+// getEdgeLabel returns the methods of the edge, the added ones prefixed by + and the removed ones by - when changed.
+func getEdgeLabel(e Edge) string {
+	return strings.TrimPrefix(getFuncsLabel(e), ": ")
+}
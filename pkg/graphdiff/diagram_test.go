@@ -0,0 +1,73 @@
+package graphdiff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteC4(t *testing.T) {
+	before, after := getSchemas()
+
+	buf := &bytes.Buffer{}
+	err := WriteC4(buf, Compare(before, after))
+	require.NoError(t, err)
+	assert.Equal(t, `@startuml
+!include https://raw.githubusercontent.com/plantuml-stdlib/C4-PlantUML/master/C4_Component.puml
+
+title mod
+AddElementTag("added", $bgColor="green", $legendText="added")
+AddElementTag("removed", $bgColor="red", $legendText="removed")
+AddRelTag("added", $textColor="green", $lineColor="green", $legendText="added")
+AddRelTag("removed", $textColor="red", $lineColor="red", $legendText="removed")
+AddRelTag("changed", $textColor="orange", $lineColor="orange", $legendText="changed")
+
+Container_Boundary(api, "api") {
+Component("api_Handler", "api.Handler", "", "")
+
+}
+
+
+Container_Boundary(app, "app") {
+Component("app_Cache", "app.Cache", "", "", $tags="removed")
+Component("app_Service", "app.Service", "", "")
+Component("app_Store", "app.Store", "", "", $tags="added")
+
+}
+Rel("api_Handler", "app_Service", "+Create, -List", $tags="changed")
+Rel("app_Service", "app_Cache", "Load", $tags="removed")
+Rel("app_Service", "app_Store", "", $tags="added")
+
+@enduml`, buf.String())
+}
+
+func TestWriteMermaid(t *testing.T) {
+	before, after := getSchemas()
+
+	buf := &bytes.Buffer{}
+	err := WriteMermaid(buf, Compare(before, after))
+	require.NoError(t, err)
+	assert.Equal(t, `flowchart LR
+classDef added stroke:green,color:green
+classDef removed stroke:red,color:red,stroke-dasharray:5
+
+subgraph api["api"]
+api_Handler["api.Handler"]
+end
+
+subgraph app["app"]
+app_Cache["app.Cache"]:::removed
+app_Service["app.Service"]
+app_Store["app.Store"]:::added
+end
+
+api_Handler -->|"+Create, -List"| app_Service
+app_Service -->|"Load"| app_Cache
+app_Service --> app_Store
+linkStyle 0 stroke:orange,color:orange
+linkStyle 1 stroke:red,color:red,stroke-dasharray:5
+linkStyle 2 stroke:green,color:green
+`, buf.String())
+}
@@ -0,0 +1,241 @@
+package graphdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+)
+
+// Status is the status of a node or an edge between the two graphs.
+type Status string
+
+const (
+	StatusUnchanged Status = "unchanged"
+	StatusAdded     Status = "added"
+	StatusRemoved   Status = "removed"
+	StatusChanged   Status = "changed" // The edge exists in both graphs, with different methods
+)
+
+// Node is a node of either graph.
+type Node struct {
+	Name     string `json:"name"`
+	Package  string `json:"package"`
+	Struct   string `json:"struct"`
+	External bool   `json:"external,omitempty"`
+	Status   Status `json:"status"`
+}
+
+// Edge is an edge of either graph, the edges between the same nodes are merged.
+type Edge struct {
+	From         string   `json:"from"`
+	To           string   `json:"to"`
+	Status       Status   `json:"status"`
+	Funcs        []string `json:"funcs,omitempty"`         // The methods used in the last graph having the edge
+	AddedFuncs   []string `json:"added_funcs,omitempty"`   // The methods used only in the after graph
+	RemovedFuncs []string `json:"removed_funcs,omitempty"` // The methods used only in the before graph
+}
+
+// Diff holds the nodes and the edges of both graphs, sorted by name.
+type Diff struct {
+	ModulePath string `json:"module_path"`
+	Nodes      []Node `json:"nodes"`
+	Edges      []Edge `json:"edges"`
+}
+
+// HasChanges returns whether a node or an edge is not unchanged.
+func (d Diff) HasChanges() bool {
+	changes := d.Changes()
+	return len(changes.Nodes)+len(changes.Edges) > 0
+}
+
+// Changes returns the diff without the unchanged nodes and edges.
+func (d Diff) Changes() Diff {
+	changes := Diff{ModulePath: d.ModulePath, Nodes: []Node{}, Edges: []Edge{}}
+	for _, n := range d.Nodes {
+		if n.Status != StatusUnchanged {
+			changes.Nodes = append(changes.Nodes, n)
+		}
+	}
+	for _, e := range d.Edges {
+		if e.Status != StatusUnchanged {
+			changes.Edges = append(changes.Edges, e)
+		}
+	}
+	return changes
+}
+
+// Compare returns the differences going from the before graph to the after one.
+func Compare(before, after parse.AstSchema) Diff {
+	d := Diff{ModulePath: after.ModulePath}
+	if d.ModulePath == "" {
+		d.ModulePath = before.ModulePath
+	}
+
+	nodes := make(map[string]*Node)
+	for _, n := range before.Graph.Nodes {
+		nodes[n.Name] = &Node{Name: n.Name, Package: n.PackageName, Struct: n.StructName, External: n.External, Status: StatusRemoved}
+	}
+	for _, n := range after.Graph.Nodes {
+		if existing, ok := nodes[n.Name]; ok {
+			existing.Status = StatusUnchanged
+			continue
+		}
+		nodes[n.Name] = &Node{Name: n.Name, Package: n.PackageName, Struct: n.StructName, External: n.External, Status: StatusAdded}
+	}
+	for _, n := range nodes {
+		d.Nodes = append(d.Nodes, *n)
+	}
+	sort.Slice(d.Nodes, func(i, j int) bool {
+		return d.Nodes[i].Name < d.Nodes[j].Name
+	})
+
+	beforeEdges, afterEdges := getEdges(before.Graph), getEdges(after.Graph)
+	for key, beforeFuncs := range beforeEdges {
+		afterFuncs, ok := afterEdges[key]
+		if !ok {
+			d.Edges = append(d.Edges, Edge{From: key.from, To: key.to, Status: StatusRemoved, Funcs: sortedKeys(beforeFuncs)})
+			continue
+		}
+		e := Edge{From: key.from, To: key.to, Status: StatusUnchanged, Funcs: sortedKeys(afterFuncs)}
+		e.AddedFuncs = getMissing(afterFuncs, beforeFuncs)
+		e.RemovedFuncs = getMissing(beforeFuncs, afterFuncs)
+		if len(e.AddedFuncs)+len(e.RemovedFuncs) > 0 {
+			e.Status = StatusChanged
+		}
+		d.Edges = append(d.Edges, e)
+	}
+	for key, afterFuncs := range afterEdges {
+		if _, ok := beforeEdges[key]; !ok {
+			d.Edges = append(d.Edges, Edge{From: key.from, To: key.to, Status: StatusAdded, Funcs: sortedKeys(afterFuncs)})
+		}
+	}
+	sort.Slice(d.Edges, func(i, j int) bool {
+		if d.Edges[i].From != d.Edges[j].From {
+			return d.Edges[i].From < d.Edges[j].From
+		}
+		return d.Edges[i].To < d.Edges[j].To
+	})
+	if d.Nodes == nil {
+		d.Nodes = []Node{}
+	}
+	if d.Edges == nil {
+		d.Edges = []Edge{}
+	}
+	return d
+}
+
+type edgeKey struct {
+	from, to string
+}
+
+// getEdges returns the methods used by each edge of the graph.
+func getEdges(graph *parse.Graph) map[edgeKey]map[string]bool {
+	edges := make(map[edgeKey]map[string]bool)
+	for node, adjs := range graph.Adj {
+		for _, adj := range adjs {
+			key := edgeKey{from: node.Name, to: adj.Node.Name}
+			if edges[key] == nil {
+				edges[key] = make(map[string]bool)
+			}
+			for _, f := range adj.Func {
+				edges[key][f] = true
+			}
+		}
+	}
+	return edges
+}
+
+// getMissing returns the sorted values of a missing from b.
+func getMissing(a, b map[string]bool) []string {
+	var missing []string
+	for _, v := range sortedKeys(a) {
+		if !b[v] {
+			missing = append(missing, v)
+		}
+	}
+	return missing
+}
+
+func sortedKeys(m map[string]bool) []string {
+	if len(m) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// WriteText writes the changes, one per line, prefixed by + when added, - when removed and ~ when changed.
+func WriteText(w io.Writer, d Diff) error {
+	changes := d.Changes()
+	if len(changes.Nodes)+len(changes.Edges) == 0 {
+		_, err := fmt.Fprintln(w, "no change")
+		if err != nil {
+			return fmt.Errorf("fmt.Fprintln:%w", err)
+		}
+		return nil
+	}
+	for _, n := range changes.Nodes {
+		_, err := fmt.Fprintf(w, "%s node %s\n", getPrefix(n.Status), n.Name)
+		if err != nil {
+			return fmt.Errorf("fmt.Fprintf:%w", err)
+		}
+	}
+	for _, e := range changes.Edges {
+		_, err := fmt.Fprintf(w, "%s edge %s -> %s%s\n", getPrefix(e.Status), e.From, e.To, getFuncsLabel(e))
+		if err != nil {
+			return fmt.Errorf("fmt.Fprintf:%w", err)
+		}
+	}
+	return nil
+}
+
+func getPrefix(s Status) string {
+	switch s {
+	case StatusAdded:
+		return "+"
+	case StatusRemoved:
+		return "-"
+	case StatusChanged:
+		return "~"
+	default:
+		return " "
+	}
+}
+
+// getFuncsLabel returns the methods of the edge, the added ones prefixed by + and the removed ones by -.
+func getFuncsLabel(e Edge) string {
+	var funcs []string
+	if e.Status != StatusChanged {
+		funcs = e.Funcs
+	} else {
+		for _, f := range e.AddedFuncs {
+			funcs = append(funcs, "+"+f)
+		}
+		for _, f := range e.RemovedFuncs {
+			funcs = append(funcs, "-"+f)
+		}
+	}
+	if len(funcs) == 0 {
+		return ""
+	}
+	return ": " + strings.Join(funcs, ", ")
+}
+
+// WriteJSON writes the changes as an indented JSON document.
+func WriteJSON(w io.Writer, d Diff) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(d.Changes())
+	if err != nil {
+		return fmt.Errorf("encoder.Encode:%w", err)
+	}
+	return nil
+}
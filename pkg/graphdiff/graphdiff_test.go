@@ -0,0 +1,144 @@
+package graphdiff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// getSchemas returns the graphs of a project before and after a change:
+// the Cache is removed, the Store is added and the Handler uses other methods of the Service.
+func getSchemas() (parse.AstSchema, parse.AstSchema) {
+	before := parse.NewGraph()
+	handler := &parse.Node{Name: "mod/api.Handler", PackageName: "mod/api", StructName: "Handler"}
+	service := &parse.Node{Name: "mod/app.Service", PackageName: "mod/app", StructName: "Service"}
+	cache := &parse.Node{Name: "mod/app.Cache", PackageName: "mod/app", StructName: "Cache"}
+	before.AddNode(handler)
+	before.AddNode(service)
+	before.AddNode(cache)
+	before.AddEdge(handler, &parse.Adj{Node: service, Func: []string{"Get", "List"}})
+	before.AddEdge(service, &parse.Adj{Node: cache, Func: []string{"Load"}})
+
+	after := parse.NewGraph()
+	handler = &parse.Node{Name: "mod/api.Handler", PackageName: "mod/api", StructName: "Handler"}
+	service = &parse.Node{Name: "mod/app.Service", PackageName: "mod/app", StructName: "Service"}
+	store := &parse.Node{Name: "mod/app.Store", PackageName: "mod/app", StructName: "Store"}
+	after.AddNode(handler)
+	after.AddNode(service)
+	after.AddNode(store)
+	after.AddEdge(handler, &parse.Adj{Node: service, Func: []string{"Get", "Create"}})
+	after.AddEdge(service, &parse.Adj{Node: store})
+	return parse.AstSchema{ModulePath: "mod", Graph: before}, parse.AstSchema{ModulePath: "mod", Graph: after}
+}
+
+func TestCompare(t *testing.T) {
+	before, after := getSchemas()
+
+	d := Compare(before, after)
+	assert.Equal(t, Diff{
+		ModulePath: "mod",
+		Nodes: []Node{
+			{Name: "mod/api.Handler", Package: "mod/api", Struct: "Handler", Status: StatusUnchanged},
+			{Name: "mod/app.Cache", Package: "mod/app", Struct: "Cache", Status: StatusRemoved},
+			{Name: "mod/app.Service", Package: "mod/app", Struct: "Service", Status: StatusUnchanged},
+			{Name: "mod/app.Store", Package: "mod/app", Struct: "Store", Status: StatusAdded},
+		},
+		Edges: []Edge{
+			{From: "mod/api.Handler", To: "mod/app.Service", Status: StatusChanged, Funcs: []string{"Create", "Get"}, AddedFuncs: []string{"Create"}, RemovedFuncs: []string{"List"}},
+			{From: "mod/app.Service", To: "mod/app.Cache", Status: StatusRemoved, Funcs: []string{"Load"}},
+			{From: "mod/app.Service", To: "mod/app.Store", Status: StatusAdded},
+		},
+	}, d)
+	assert.True(t, d.HasChanges())
+}
+
+func TestCompare_same(t *testing.T) {
+	before, _ := getSchemas()
+
+	d := Compare(before, before)
+	assert.False(t, d.HasChanges())
+	assert.Len(t, d.Nodes, 3)
+	assert.Len(t, d.Edges, 2)
+}
+
+func TestWriteText(t *testing.T) {
+	before, after := getSchemas()
+
+	buf := &bytes.Buffer{}
+	err := WriteText(buf, Compare(before, after))
+	require.NoError(t, err)
+	assert.Equal(t, `- node mod/app.Cache
++ node mod/app.Store
+~ edge mod/api.Handler -> mod/app.Service: +Create, -List
+- edge mod/app.Service -> mod/app.Cache: Load
++ edge mod/app.Service -> mod/app.Store
+`, buf.String())
+}
+
+func TestWriteText_none(t *testing.T) {
+	before, _ := getSchemas()
+
+	buf := &bytes.Buffer{}
+	err := WriteText(buf, Compare(before, before))
+	require.NoError(t, err)
+	assert.Equal(t, "no change\n", buf.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	before, after := getSchemas()
+
+	buf := &bytes.Buffer{}
+	err := WriteJSON(buf, Compare(before, after))
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "module_path": "mod",
+  "nodes": [
+    {
+      "name": "mod/app.Cache",
+      "package": "mod/app",
+      "struct": "Cache",
+      "status": "removed"
+    },
+    {
+      "name": "mod/app.Store",
+      "package": "mod/app",
+      "struct": "Store",
+      "status": "added"
+    }
+  ],
+  "edges": [
+    {
+      "from": "mod/api.Handler",
+      "to": "mod/app.Service",
+      "status": "changed",
+      "funcs": [
+        "Create",
+        "Get"
+      ],
+      "added_funcs": [
+        "Create"
+      ],
+      "removed_funcs": [
+        "List"
+      ]
+    },
+    {
+      "from": "mod/app.Service",
+      "to": "mod/app.Cache",
+      "status": "removed",
+      "funcs": [
+        "Load"
+      ]
+    },
+    {
+      "from": "mod/app.Service",
+      "to": "mod/app.Store",
+      "status": "added"
+    }
+  ]
+}
+`, buf.String())
+}
@@ -0,0 +1,73 @@
+package graphdiff
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Checkout checks out a revision of the git repository holding the project into a temporary worktree, with the local
+// git binary. It returns the path of the project in the worktree and a function removing the worktree.
+func Checkout(ctx context.Context, project, revision string) (string, func() error, error) {
+	project, err := filepath.Abs(project)
+	if err != nil {
+		return "", nil, fmt.Errorf("filepath.Abs:%w", err)
+	}
+	root, err := git(ctx, project, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", nil, err
+	}
+	root, err = filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", nil, fmt.Errorf("filepath.EvalSymlinks:%w", err)
+	}
+	resolvedProject, err := filepath.EvalSymlinks(project)
+	if err != nil {
+		return "", nil, fmt.Errorf("filepath.EvalSymlinks:%w", err)
+	}
+	rel, err := filepath.Rel(root, resolvedProject)
+	if err != nil {
+		return "", nil, fmt.Errorf("filepath.Rel:%w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "go-dependency-graph-")
+	if err != nil {
+		return "", nil, fmt.Errorf("os.MkdirTemp:%w", err)
+	}
+	worktree := filepath.Join(dir, "worktree")
+	_, err = git(ctx, root, "worktree", "add", "--detach", worktree, revision)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return "", nil, err
+	}
+	remove := func() error {
+		_, err := git(context.Background(), root, "worktree", "remove", "--force", worktree)
+		if err != nil {
+			return err
+		}
+		err = os.RemoveAll(dir)
+		if err != nil {
+			return fmt.Errorf("os.RemoveAll:%w", err)
+		}
+		return nil
+	}
+	return filepath.Join(worktree, rel), remove, nil
+}
+
+// git runs a git command in a dir and returns its trimmed output.
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %s:%w", strings.Join(args, " "), strings.TrimSpace(stderr.String()), err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,123 @@
+package impact
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const devNull = "/dev/null"
+
+var (
+	hunkHeader     = regexp.MustCompile(`^@@ -\d+(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+	errInvalidHunk = errors.New("invalid hunk header")
+)
+
+// Change is a changed file, with the changed lines, the whole file is changed when there is none.
+type Change struct {
+	File  string
+	Lines []int
+}
+
+// hunk tracks the lines left to read in a hunk of a diff.
+type hunk struct {
+	line     int // The current line in the new version of the file
+	oldLines int
+	newLines int
+}
+
+// ParseDiff returns the changes of a unified diff, such as the output of git diff.
+// The lines are those of the new version of the files, a removed line is reported at the line following it.
+func ParseDiff(r io.Reader) ([]Change, error) {
+	var changes []Change
+	current := -1 // The index of the change the hunks are read for, none for a deleted file
+	h := hunk{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+		if h.oldLines > 0 || h.newLines > 0 {
+			h.read(text, changes, current)
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(text, "+++ "):
+			file := strings.TrimSpace(strings.TrimPrefix(text, "+++ "))
+			if i := strings.IndexByte(file, '\t'); i >= 0 {
+				file = file[:i]
+			}
+			current = -1
+			if file == devNull {
+				continue
+			}
+			changes = append(changes, Change{File: strings.TrimPrefix(file, "b/")})
+			current = len(changes) - 1
+		case strings.HasPrefix(text, "@@"):
+			var err error
+			h, err = parseHunkHeader(text)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("scanner.Err:%w", err)
+	}
+	return changes, nil
+}
+
+func parseHunkHeader(text string) (hunk, error) {
+	matches := hunkHeader.FindStringSubmatch(text)
+	if matches == nil {
+		return hunk{}, fmt.Errorf("%q: %w", text, errInvalidHunk)
+	}
+	h := hunk{oldLines: 1, newLines: 1}
+	values := []*int{&h.oldLines, &h.line, &h.newLines}
+	for i, value := range matches[1:] {
+		if value == "" {
+			continue
+		}
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return hunk{}, fmt.Errorf("strconv.Atoi:%w", err)
+		}
+		*values[i] = v
+	}
+	return h, nil
+}
+
+// read reads a line of the hunk, the added and removed lines are recorded on the current change, if any.
+func (h *hunk) read(text string, changes []Change, current int) {
+	record := func() {
+		if current >= 0 {
+			changes[current].Lines = appendLine(changes[current].Lines, h.line)
+		}
+	}
+	switch {
+	case strings.HasPrefix(text, "+"):
+		record()
+		h.line++
+		h.newLines--
+	case strings.HasPrefix(text, "-"):
+		record()
+		h.oldLines--
+	case strings.HasPrefix(text, `\`): // no newline at end of file.
+	default:
+		h.line++
+		h.oldLines--
+		h.newLines--
+	}
+}
+
+func appendLine(lines []int, line int) []int {
+	if len(lines) > 0 && lines[len(lines)-1] == line {
+		return lines
+	}
+	return append(lines, line)
+}
@@ -0,0 +1,51 @@
+package impact
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDiff(t *testing.T) {
+	changes, err := ParseDiff(strings.NewReader(`diff --git a/a.go b/a.go
+index 1111111..2222222 100644
+--- a/a.go
++++ b/a.go
+@@ -1,5 +1,5 @@
+ package a
+ 
+--- removed
++-- added
+ 
+ func A() {}
+@@ -20 +20,2 @@ func B() {
+ 	b()
++	c()
+diff --git a/old.go b/old.go
+deleted file mode 100644
+--- a/old.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package a
+-
+diff --git a/new.go b/new.go
+new file mode 100644
+--- /dev/null
++++ b/new.go
+@@ -0,0 +1 @@
++package a
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []Change{
+		{File: "a.go", Lines: []int{3, 21}},
+		{File: "new.go", Lines: []int{1}},
+	}, changes)
+}
+
+func TestParseDiff_invalid_hunk(t *testing.T) {
+	_, err := ParseDiff(strings.NewReader("+++ b/a.go\n@@ invalid @@\n"))
+	assert.ErrorIs(t, err, errInvalidHunk)
+}
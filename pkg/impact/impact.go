@@ -0,0 +1,314 @@
+package impact
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	mymap "github.com/emilien-puget/go-dependency-graph/pkg/map"
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+)
+
+// Config configures the analysis.
+type Config struct {
+	// BaseDir the relative paths of the changes are relative to, such as the root of the git repository.
+	BaseDir string
+}
+
+// Component is a node of the graph changed, or affected by a change.
+type Component struct {
+	Node    string   `json:"node"`
+	Package string   `json:"package"`
+	Methods []string `json:"methods,omitempty"` // The methods changed, or calling a changed method
+	Depth   int      `json:"depth"`             // The number of edges from the closest changed component
+}
+
+// Report lists the components changed, and those calling them.
+type Report struct {
+	Changed  []Component `json:"changed"`
+	Affected []Component `json:"affected"`
+	Packages []string    `json:"packages"` // The packages of the changed and affected components
+}
+
+// fileChange are the lines changed in a file, the whole file is changed when whole is set.
+type fileChange struct {
+	whole bool
+	lines map[int]bool
+}
+
+func (fc *fileChange) intersects(start, end int) bool {
+	if fc.whole {
+		return true
+	}
+	for line := start; line <= end; line++ {
+		if fc.lines[line] {
+			return true
+		}
+	}
+	return false
+}
+
+// Analyze maps the changes to the methods of the nodes, and follows the inbound edges to the components calling them.
+// A consumer is affected only when one of its methods calls a changed method, when the calls of an edge are unknown the
+// consumer is affected as soon as it uses a changed method. Every consumer of a node whose struct or provider is changed
+// is affected.
+func Analyze(as parse.AstSchema, changes []Change, c Config) Report {
+	files := getFileChanges(changes, c)
+	providers := &providerFiles{fset: token.NewFileSet(), files: make(map[string]*ast.File)}
+
+	r := Report{
+		Changed:  []Component{},
+		Affected: []Component{},
+		Packages: []string{},
+	}
+	packages := make(map[string]bool)
+	methods := make(map[*parse.Node]map[string]bool)
+	declarations := make(map[*parse.Node]bool) // the nodes whose struct or provider is changed
+	depths := make(map[*parse.Node]int)
+	var queue []*parse.Node
+	for _, node := range as.Graph.GetNodesSortedByName() {
+		changed, declaration := getChangedMethods(node, files)
+		if !declaration {
+			declaration = providers.isChanged(node, files)
+		}
+		if len(changed) == 0 && !declaration {
+			continue
+		}
+		methods[node] = changed
+		declarations[node] = declaration
+		depths[node] = 0
+		queue = append(queue, node)
+		packages[node.PackageName] = true
+		r.Changed = append(r.Changed, Component{Node: node.Name, Package: node.PackageName, Methods: mymap.OrderedKeys(changed)})
+	}
+
+	// Breadth first, so a component is reported at its smallest depth, methods reached later are still propagated.
+	affected := make(map[*parse.Node]bool)
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, consumer := range getConsumers(node) {
+			callers := getCallers(as.Graph, consumer, node, methods[node], declarations[node])
+			added := false
+			for caller := range callers {
+				if methods[consumer] == nil {
+					methods[consumer] = make(map[string]bool)
+				}
+				if !methods[consumer][caller] {
+					methods[consumer][caller] = true
+					added = true
+				}
+			}
+			_, reached := depths[consumer]
+			if !added && (!declarations[node] || reached) {
+				continue
+			}
+			if !reached {
+				depths[consumer] = depths[node] + 1
+				affected[consumer] = true
+			}
+			queue = append(queue, consumer)
+		}
+	}
+
+	for _, node := range as.Graph.GetNodesSortedByName() {
+		if !affected[node] {
+			continue
+		}
+		packages[node.PackageName] = true
+		r.Affected = append(r.Affected, Component{Node: node.Name, Package: node.PackageName, Methods: mymap.OrderedKeys(methods[node]), Depth: depths[node]})
+	}
+	sort.SliceStable(r.Affected, func(i, j int) bool {
+		return r.Affected[i].Depth < r.Affected[j].Depth
+	})
+	r.Packages = append(r.Packages, mymap.OrderedKeys(packages)...)
+	return r
+}
+
+func getFileChanges(changes []Change, c Config) map[string]*fileChange {
+	files := make(map[string]*fileChange)
+	for _, change := range changes {
+		file := change.File
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(c.BaseDir, file)
+		}
+		if abs, err := filepath.Abs(file); err == nil {
+			file = abs
+		}
+		fc, ok := files[file]
+		if !ok {
+			fc = &fileChange{lines: make(map[int]bool)}
+			files[file] = fc
+		}
+		if len(change.Lines) == 0 {
+			fc.whole = true
+		}
+		for _, line := range change.Lines {
+			fc.lines[line] = true
+		}
+	}
+	return files
+}
+
+// getChangedMethods returns the changed methods of a node, and whether its struct is changed.
+func getChangedMethods(node *parse.Node, files map[string]*fileChange) (map[string]bool, bool) {
+	if node.External || node.ActualNamedType == nil || node.P == nil {
+		return nil, false
+	}
+	changed := make(map[string]bool)
+	structChanged := false
+	for _, f := range node.P.Syntax {
+		fc, ok := files[node.P.Fset.Position(f.Package).Filename]
+		if !ok {
+			continue
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch decl := n.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv == nil || !isMethodOf(node, decl) {
+					return false
+				}
+				if intersects(node.P.Fset, fc, decl) {
+					changed[decl.Name.Name] = true
+				}
+				return false
+			case *ast.TypeSpec:
+				if decl.Name.Pos() == node.ActualNamedType.Obj().Pos() && intersects(node.P.Fset, fc, decl) {
+					structChanged = true
+				}
+				return false
+			}
+			return true
+		})
+	}
+	return changed, structChanged
+}
+
+// providerFiles are the files of the providers, parsed once.
+type providerFiles struct {
+	fset  *token.FileSet
+	files map[string]*ast.File
+}
+
+// isChanged reports whether the provider of the node is changed, the provider may be declared in another package.
+func (pf *providerFiles) isChanged(node *parse.Node, files map[string]*fileChange) bool {
+	fc, ok := files[node.Provider.Filename]
+	if !ok || node.External || node.Provider.Line == 0 {
+		return false
+	}
+	f, ok := pf.files[node.Provider.Filename]
+	if !ok {
+		f, _ = parser.ParseFile(pf.fset, node.Provider.Filename, nil, 0)
+		pf.files[node.Provider.Filename] = f
+	}
+	if f != nil {
+		for _, decl := range f.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if ok && pf.fset.Position(funcDecl.Pos()).Line == node.Provider.Line {
+				return intersects(pf.fset, fc, funcDecl)
+			}
+		}
+	}
+	// a provider declared as a function literal, only its first line is known
+	return fc.intersects(node.Provider.Line, node.Provider.Line)
+}
+
+func isMethodOf(node *parse.Node, decl *ast.FuncDecl) bool {
+	for _, method := range node.Methods {
+		if method.TypFuc.Pos() == decl.Name.Pos() {
+			return true
+		}
+	}
+	return false
+}
+
+func intersects(fset *token.FileSet, fc *fileChange, n ast.Node) bool {
+	return fc.intersects(fset.Position(n.Pos()).Line, fset.Position(n.End()).Line)
+}
+
+// getConsumers returns the nodes depending on the node, once each.
+func getConsumers(node *parse.Node) []*parse.Node {
+	seen := make(map[*parse.Node]bool)
+	consumers := make([]*parse.Node, 0, len(node.InboundEdges))
+	for _, consumer := range node.InboundEdges {
+		if seen[consumer] {
+			continue
+		}
+		seen[consumer] = true
+		consumers = append(consumers, consumer)
+	}
+	sort.SliceStable(consumers, func(i, j int) bool {
+		return consumers[i].Name < consumers[j].Name
+	})
+	return consumers
+}
+
+// getCallers returns the methods of the consumer calling one of the changed methods of the dependency, or any of its
+// methods when its declaration is changed.
+func getCallers(graph *parse.Graph, consumer, dependency *parse.Node, changed map[string]bool, declaration bool) map[string]bool {
+	callers := make(map[string]bool)
+	for _, adj := range graph.GetAdjacency(consumer) {
+		if adj.Node != dependency {
+			continue
+		}
+		if len(adj.Calls) > 0 {
+			for _, call := range adj.Calls {
+				if declaration || changed[call.Callee] {
+					callers[call.Caller] = true
+				}
+			}
+			continue
+		}
+		for _, fn := range adj.Func {
+			if !declaration && !changed[fn] {
+				continue
+			}
+			for _, method := range consumer.Methods {
+				callers[method.TypFuc.Name()] = true
+			}
+			break
+		}
+	}
+	return callers
+}
+
+// WriteText writes the changed components, then the affected ones.
+func WriteText(w io.Writer, r Report) error {
+	for _, c := range r.Changed {
+		_, err := fmt.Fprintf(w, "changed %s%s\n", c.Node, getMethods(c))
+		if err != nil {
+			return fmt.Errorf("fmt.Fprintf:%w", err)
+		}
+	}
+	for _, c := range r.Affected {
+		_, err := fmt.Fprintf(w, "affected %s%s (depth %d)\n", c.Node, getMethods(c), c.Depth)
+		if err != nil {
+			return fmt.Errorf("fmt.Fprintf:%w", err)
+		}
+	}
+	return nil
+}
+
+func getMethods(c Component) string {
+	if len(c.Methods) == 0 {
+		return ""
+	}
+	return ": " + strings.Join(c.Methods, ", ")
+}
+
+// WriteJSON writes the report as an indented JSON document.
+func WriteJSON(w io.Writer, r Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(r)
+	if err != nil {
+		return fmt.Errorf("encoder.Encode:%w", err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package impact
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyze(t *testing.T) {
+	as, err := parse.Parse("testdata/shop", nil)
+	require.NoError(t, err)
+
+	r := Analyze(as, []Change{{File: "repository.go", Lines: []int{13}}}, Config{BaseDir: "testdata/shop"})
+
+	assert.Equal(t, []Component{
+		{Node: "testdata/shop.Repository", Package: "testdata/shop", Methods: []string{"Save"}},
+	}, r.Changed)
+	assert.Equal(t, []Component{
+		{Node: "testdata/shop.Service", Package: "testdata/shop", Methods: []string{"Create"}, Depth: 1},
+		{Node: "testdata/shop.Admin", Package: "testdata/shop", Methods: []string{"Add"}, Depth: 2},
+	}, r.Affected)
+	assert.Equal(t, []string{"testdata/shop"}, r.Packages)
+}
+
+func TestAnalyze_whole_file(t *testing.T) {
+	as, err := parse.Parse("testdata/shop", nil)
+	require.NoError(t, err)
+
+	r := Analyze(as, []Change{{File: "repository.go"}}, Config{BaseDir: "testdata/shop"})
+
+	assert.Equal(t, []Component{
+		{Node: "testdata/shop.Repository", Package: "testdata/shop", Methods: []string{"Find", "Save"}},
+	}, r.Changed)
+	assert.Equal(t, []Component{
+		{Node: "testdata/shop.Service", Package: "testdata/shop", Methods: []string{"Create", "Get"}, Depth: 1},
+		{Node: "testdata/shop.Admin", Package: "testdata/shop", Methods: []string{"Add"}, Depth: 2},
+		{Node: "testdata/shop.Handler", Package: "testdata/shop", Methods: []string{"Show"}, Depth: 2},
+	}, r.Affected)
+}
+
+func TestAnalyze_struct(t *testing.T) {
+	as, err := parse.Parse("testdata/shop", nil)
+	require.NoError(t, err)
+
+	r := Analyze(as, []Change{{File: "service.go", Lines: []int{5}}}, Config{BaseDir: "testdata/shop"})
+
+	assert.Equal(t, []Component{
+		{Node: "testdata/shop.Service", Package: "testdata/shop", Methods: []string{}},
+	}, r.Changed)
+	assert.Equal(t, []Component{
+		{Node: "testdata/shop.Admin", Package: "testdata/shop", Methods: []string{"Add"}, Depth: 1},
+		{Node: "testdata/shop.Handler", Package: "testdata/shop", Methods: []string{"Show"}, Depth: 1},
+	}, r.Affected)
+}
+
+func TestAnalyze_provider(t *testing.T) {
+	as, err := parse.Parse("testdata/shop", nil)
+	require.NoError(t, err)
+
+	r := Analyze(as, []Change{{File: "service.go", Lines: []int{9}}}, Config{BaseDir: "testdata/shop"})
+
+	assert.Equal(t, []Component{
+		{Node: "testdata/shop.Service", Package: "testdata/shop", Methods: []string{}},
+	}, r.Changed)
+	assert.Equal(t, []Component{
+		{Node: "testdata/shop.Admin", Package: "testdata/shop", Methods: []string{"Add"}, Depth: 1},
+		{Node: "testdata/shop.Handler", Package: "testdata/shop", Methods: []string{"Show"}, Depth: 1},
+	}, r.Affected)
+}
+
+func TestAnalyze_diff(t *testing.T) {
+	as, err := parse.Parse("testdata/shop", nil)
+	require.NoError(t, err)
+
+	changes, err := ParseDiff(strings.NewReader(`diff --git a/service.go b/service.go
+index 1111111..2222222 100644
+--- a/service.go
++++ b/service.go
+@@ -11,3 +11,4 @@ func NewService(repo *Repository) *Service {
+ 
+ func (s *Service) Get() {
++	// Get finds an order.
+ 	s.repo.Find()
+`))
+	require.NoError(t, err)
+
+	r := Analyze(as, changes, Config{BaseDir: "testdata/shop"})
+
+	assert.Equal(t, []Component{
+		{Node: "testdata/shop.Service", Package: "testdata/shop", Methods: []string{"Get"}},
+	}, r.Changed)
+	assert.Equal(t, []Component{
+		{Node: "testdata/shop.Handler", Package: "testdata/shop", Methods: []string{"Show"}, Depth: 1},
+	}, r.Affected)
+}
+
+func TestWriteText(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := WriteText(buf, Report{
+		Changed:  []Component{{Node: "a.A", Package: "a", Methods: []string{"Find"}}},
+		Affected: []Component{{Node: "a.B", Package: "a", Methods: []string{"Get", "List"}, Depth: 1}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `changed a.A: Find
+affected a.B: Get, List (depth 1)
+`, buf.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := WriteJSON(buf, Report{
+		Changed:  []Component{{Node: "a.A", Package: "a", Methods: []string{"Find"}}},
+		Affected: []Component{},
+		Packages: []string{"a"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `{
+  "changed": [
+    {
+      "node": "a.A",
+      "package": "a",
+      "methods": [
+        "Find"
+      ],
+      "depth": 0
+    }
+  ],
+  "affected": [],
+  "packages": [
+    "a"
+  ]
+}
+`, buf.String())
+}
@@ -0,0 +1,3 @@
+module testdata/shop
+
+go 1.19
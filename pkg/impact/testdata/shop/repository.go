@@ -0,0 +1,14 @@
+package shop
+
+// Repository stores the orders.
+type Repository struct{}
+
+func NewRepository() *Repository {
+	return &Repository{}
+}
+
+func (r *Repository) Find() {}
+
+func (r *Repository) Save() {
+	_ = r
+}
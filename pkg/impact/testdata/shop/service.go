@@ -0,0 +1,44 @@
+package shop
+
+// Service manages the orders.
+type Service struct {
+	repo *Repository
+}
+
+func NewService(repo *Repository) *Service {
+	return &Service{repo: repo}
+}
+
+func (s *Service) Get() {
+	s.repo.Find()
+}
+
+func (s *Service) Create() {
+	s.repo.Save()
+}
+
+// Handler shows the orders.
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) Show() {
+	h.service.Get()
+}
+
+// Admin adds orders.
+type Admin struct {
+	service *Service
+}
+
+func NewAdmin(service *Service) *Admin {
+	return &Admin{service: service}
+}
+
+func (a *Admin) Add() {
+	a.service.Create()
+}
@@ -0,0 +1,206 @@
+package metrics
+
+import (
+	"go/types"
+	"math"
+
+	mymap "github.com/emilien-puget/go-dependency-graph/pkg/map"
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+)
+
+// NodeMetrics are the coupling metrics of a node.
+type NodeMetrics struct {
+	Node       string `json:"node"`
+	Package    string `json:"package"`
+	FanIn      int    `json:"fan_in"`     // The number of nodes depending on the node
+	FanOut     int    `json:"fan_out"`    // The number of nodes the node depends on
+	Transitive int    `json:"transitive"` // The number of nodes the node depends on, directly or not
+	Depth      int    `json:"depth"`      // The distance from the closest entry point, -1 when none reaches the node
+}
+
+// PackageMetrics are the coupling metrics of a package, as defined by Robert Martin.
+type PackageMetrics struct {
+	Package      string  `json:"package"`
+	Afferent     int     `json:"afferent"`     // Ca, the number of nodes outside the package depending on its nodes
+	Efferent     int     `json:"efferent"`     // Ce, the number of nodes outside the package its nodes depend on
+	Instability  float64 `json:"instability"`  // I = Ce / (Ca + Ce)
+	Abstractness float64 `json:"abstractness"` // A, the ratio of interfaces among the interfaces and structs of the package
+	Distance     float64 `json:"distance"`     // D = |A + I - 1|, the distance from the main sequence
+}
+
+// Report holds the metrics of the nodes and the packages of the project, and the thresholds exceeded.
+type Report struct {
+	Nodes      []NodeMetrics    `json:"nodes"`
+	Packages   []PackageMetrics `json:"packages"`
+	Violations []string         `json:"violations"`
+}
+
+// Compute returns the metrics of the nodes and the packages of the project, the external ones are left out.
+// The entry points are the nodes no other node depends on.
+func Compute(as parse.AstSchema, thresholds Thresholds) Report {
+	r := Report{
+		Nodes:      []NodeMetrics{},
+		Packages:   []PackageMetrics{},
+		Violations: []string{},
+	}
+	depths := getDepths(as.Graph)
+	for _, node := range as.Graph.GetNodesSortedByName() {
+		if node.External {
+			continue
+		}
+		depth, ok := depths[node]
+		if !ok {
+			depth = -1
+		}
+		r.Nodes = append(r.Nodes, NodeMetrics{
+			Node:       node.Name,
+			Package:    node.PackageName,
+			FanIn:      len(getConsumers(node)),
+			FanOut:     len(getDependencies(as.Graph, node)),
+			Transitive: getTransitiveCount(as.Graph, node),
+			Depth:      depth,
+		})
+	}
+	for _, packageName := range mymap.OrderedKeys(as.Graph.NodesByPackage) {
+		nodes := as.Graph.NodesByPackage[packageName]
+		if isExternalPackage(nodes) {
+			continue
+		}
+		r.Packages = append(r.Packages, computePackage(as.Graph, packageName, nodes))
+	}
+	r.Violations = append(r.Violations, thresholds.check(r)...)
+	return r
+}
+
+func computePackage(graph *parse.Graph, packageName string, nodes []*parse.Node) PackageMetrics {
+	afferent := make(map[*parse.Node]bool)
+	efferent := make(map[*parse.Node]bool)
+	for _, node := range nodes {
+		for consumer := range getConsumers(node) {
+			if consumer.PackageName != packageName {
+				afferent[consumer] = true
+			}
+		}
+		for dependency := range getDependencies(graph, node) {
+			if dependency.PackageName != packageName {
+				efferent[dependency] = true
+			}
+		}
+	}
+
+	m := PackageMetrics{
+		Package:      packageName,
+		Afferent:     len(afferent),
+		Efferent:     len(efferent),
+		Abstractness: getAbstractness(nodes),
+	}
+	if m.Afferent+m.Efferent > 0 {
+		m.Instability = round(float64(m.Efferent) / float64(m.Afferent+m.Efferent))
+	}
+	m.Distance = round(math.Abs(m.Abstractness + m.Instability - 1))
+	return m
+}
+
+// getAbstractness returns the ratio of interfaces among the named interfaces and structs of the package of the nodes.
+func getAbstractness(nodes []*parse.Node) float64 {
+	var pkg *types.Package
+	for _, node := range nodes {
+		if node.P != nil && node.P.Types != nil {
+			pkg = node.P.Types
+			break
+		}
+	}
+	if pkg == nil {
+		return 0
+	}
+	interfaces, structs := 0, 0
+	for _, name := range pkg.Scope().Names() {
+		typeName, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+		if !ok || typeName.IsAlias() {
+			continue
+		}
+		switch typeName.Type().Underlying().(type) {
+		case *types.Interface:
+			interfaces++
+		case *types.Struct:
+			structs++
+		}
+	}
+	if interfaces+structs == 0 {
+		return 0
+	}
+	return round(float64(interfaces) / float64(interfaces+structs))
+}
+
+// getDepths returns the distance of each node from the closest entry point.
+func getDepths(graph *parse.Graph) map[*parse.Node]int {
+	depths := make(map[*parse.Node]int)
+	var queue []*parse.Node
+	for _, node := range graph.GetNodesSortedByName() {
+		if len(node.InboundEdges) == 0 {
+			depths[node] = 0
+			queue = append(queue, node)
+		}
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, adj := range graph.GetAdjacency(node) {
+			if _, ok := depths[adj.Node]; ok {
+				continue
+			}
+			depths[adj.Node] = depths[node] + 1
+			queue = append(queue, adj.Node)
+		}
+	}
+	return depths
+}
+
+func getTransitiveCount(graph *parse.Graph, node *parse.Node) int {
+	visited := map[*parse.Node]bool{node: true}
+	stack := []*parse.Node{node}
+	count := 0
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, adj := range graph.GetAdjacency(n) {
+			if visited[adj.Node] {
+				continue
+			}
+			visited[adj.Node] = true
+			count++
+			stack = append(stack, adj.Node)
+		}
+	}
+	return count
+}
+
+func getConsumers(node *parse.Node) map[*parse.Node]bool {
+	consumers := make(map[*parse.Node]bool)
+	for _, consumer := range node.InboundEdges {
+		consumers[consumer] = true
+	}
+	return consumers
+}
+
+func getDependencies(graph *parse.Graph, node *parse.Node) map[*parse.Node]bool {
+	dependencies := make(map[*parse.Node]bool)
+	for _, adj := range graph.GetAdjacency(node) {
+		dependencies[adj.Node] = true
+	}
+	return dependencies
+}
+
+func isExternalPackage(nodes []*parse.Node) bool {
+	for _, node := range nodes {
+		if !node.External {
+			return false
+		}
+	}
+	return true
+}
+
+// round rounds to two decimals, the reports stay readable and stable.
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
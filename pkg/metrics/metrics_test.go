@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompute(t *testing.T) {
+	as, err := parse.Parse("../parse/testdata/named_inter", nil)
+	require.NoError(t, err)
+
+	r := Compute(as, Thresholds{})
+
+	assert.Equal(t, []NodeMetrics{
+		{Node: "testdata/named_inter.A", Package: "testdata/named_inter", FanIn: 0, FanOut: 2, Transitive: 4, Depth: 0},
+		{Node: "testdata/named_inter.B", Package: "testdata/named_inter", FanIn: 1, FanOut: 1, Transitive: 1, Depth: 1},
+		{Node: "testdata/named_inter.C", Package: "testdata/named_inter", FanIn: 1, FanOut: 0, Transitive: 0, Depth: 2},
+		{Node: "testdata/named_inter.D", Package: "testdata/named_inter", FanIn: 1, FanOut: 1, Transitive: 1, Depth: 1},
+		{Node: "testdata/named_inter/pa.A", Package: "testdata/named_inter/pa", FanIn: 1, FanOut: 0, Transitive: 0, Depth: 2},
+	}, r.Nodes)
+	assert.Equal(t, []PackageMetrics{
+		{Package: "testdata/named_inter", Afferent: 0, Efferent: 1, Instability: 1, Abstractness: 0.5, Distance: 0.5},
+		{Package: "testdata/named_inter/pa", Afferent: 1, Efferent: 0, Instability: 0, Abstractness: 0, Distance: 1},
+	}, r.Packages)
+	assert.Empty(t, r.Violations)
+}
+
+func TestCompute_thresholds(t *testing.T) {
+	as, err := parse.Parse("../parse/testdata/named_inter", nil)
+	require.NoError(t, err)
+
+	r := Compute(as, Thresholds{MaxFanOut: 1, MaxDepth: 1, MaxDistance: 0.5})
+
+	assert.Equal(t, []string{
+		"testdata/named_inter.A: fan-out 2 exceeds 1",
+		"testdata/named_inter.C: depth 2 exceeds 1",
+		"testdata/named_inter/pa.A: depth 2 exceeds 1",
+		"testdata/named_inter/pa: distance 1.00 exceeds 0.50",
+	}, r.Violations)
+}
+
+func TestCompute_cycle(t *testing.T) {
+	graph := parse.NewGraph()
+	a := &parse.Node{Name: "a.A", PackageName: "a"}
+	b := &parse.Node{Name: "a.B", PackageName: "a"}
+	graph.AddNode(a)
+	graph.AddNode(b)
+	graph.AddEdge(a, &parse.Adj{Node: b})
+	graph.AddEdge(b, &parse.Adj{Node: a})
+
+	r := Compute(parse.AstSchema{Graph: graph}, Thresholds{})
+
+	assert.Equal(t, []NodeMetrics{
+		{Node: "a.A", Package: "a", FanIn: 1, FanOut: 1, Transitive: 1, Depth: -1},
+		{Node: "a.B", Package: "a", FanIn: 1, FanOut: 1, Transitive: 1, Depth: -1},
+	}, r.Nodes)
+}
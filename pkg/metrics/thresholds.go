@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"fmt"
+)
+
+// Thresholds are the maximum values allowed, a zero value disables its check.
+type Thresholds struct {
+	MaxFanIn       int
+	MaxFanOut      int
+	MaxTransitive  int
+	MaxDepth       int
+	MaxInstability float64
+	MaxDistance    float64
+}
+
+// check returns a description of every threshold exceeded by the metrics.
+func (t Thresholds) check(r Report) []string {
+	var violations []string
+	for _, n := range r.Nodes {
+		violations = appendIntViolation(violations, n.Node, "fan-in", n.FanIn, t.MaxFanIn)
+		violations = appendIntViolation(violations, n.Node, "fan-out", n.FanOut, t.MaxFanOut)
+		violations = appendIntViolation(violations, n.Node, "transitive dependencies", n.Transitive, t.MaxTransitive)
+		violations = appendIntViolation(violations, n.Node, "depth", n.Depth, t.MaxDepth)
+	}
+	for _, p := range r.Packages {
+		violations = appendFloatViolation(violations, p.Package, "instability", p.Instability, t.MaxInstability)
+		violations = appendFloatViolation(violations, p.Package, "distance", p.Distance, t.MaxDistance)
+	}
+	return violations
+}
+
+func appendIntViolation(violations []string, name, metric string, value, limit int) []string {
+	if limit <= 0 || value <= limit {
+		return violations
+	}
+	return append(violations, fmt.Sprintf("%s: %s %d exceeds %d", name, metric, value, limit))
+}
+
+func appendFloatViolation(violations []string, name, metric string, value, limit float64) []string {
+	if limit <= 0 || value <= limit {
+		return violations
+	}
+	return append(violations, fmt.Sprintf("%s: %s %.2f exceeds %.2f", name, metric, value, limit))
+}
@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+)
+
+// WriteTable writes the metrics of the nodes then of the packages as aligned tables, followed by the violations.
+func WriteTable(w io.Writer, r Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, err := fmt.Fprintln(tw, "NODE\tFAN-IN\tFAN-OUT\tTRANSITIVE\tDEPTH")
+	if err != nil {
+		return fmt.Errorf("fmt.Fprintln:%w", err)
+	}
+	for _, n := range r.Nodes {
+		_, err = fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\n", n.Node, n.FanIn, n.FanOut, n.Transitive, n.Depth)
+		if err != nil {
+			return fmt.Errorf("fmt.Fprintf:%w", err)
+		}
+	}
+	_, err = fmt.Fprintln(tw, "\nPACKAGE\tCA\tCE\tI\tA\tD")
+	if err != nil {
+		return fmt.Errorf("fmt.Fprintln:%w", err)
+	}
+	for _, p := range r.Packages {
+		_, err = fmt.Fprintf(tw, "%s\t%d\t%d\t%.2f\t%.2f\t%.2f\n", p.Package, p.Afferent, p.Efferent, p.Instability, p.Abstractness, p.Distance)
+		if err != nil {
+			return fmt.Errorf("fmt.Fprintf:%w", err)
+		}
+	}
+	err = tw.Flush()
+	if err != nil {
+		return fmt.Errorf("tw.Flush:%w", err)
+	}
+	return writeViolations(w, r.Violations)
+}
+
+// WriteCSV writes the metrics of the nodes and of the packages as CSV records, the first column tells them apart.
+func WriteCSV(w io.Writer, r Report) error {
+	cw := csv.NewWriter(w)
+	records := [][]string{{"kind", "name", "fan_in", "fan_out", "transitive", "depth", "afferent", "efferent", "instability", "abstractness", "distance"}}
+	for _, n := range r.Nodes {
+		records = append(records, []string{"node", n.Node, strconv.Itoa(n.FanIn), strconv.Itoa(n.FanOut), strconv.Itoa(n.Transitive), strconv.Itoa(n.Depth), "", "", "", "", ""})
+	}
+	for _, p := range r.Packages {
+		records = append(records, []string{"package", p.Package, "", "", "", "", strconv.Itoa(p.Afferent), strconv.Itoa(p.Efferent), formatFloat(p.Instability), formatFloat(p.Abstractness), formatFloat(p.Distance)})
+	}
+	err := cw.WriteAll(records)
+	if err != nil {
+		return fmt.Errorf("cw.WriteAll:%w", err)
+	}
+	return nil
+}
+
+// WriteJSON writes the report as an indented JSON document.
+func WriteJSON(w io.Writer, r Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(r)
+	if err != nil {
+		return fmt.Errorf("encoder.Encode:%w", err)
+	}
+	return nil
+}
+
+func writeViolations(w io.Writer, violations []string) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	_, err := fmt.Fprintln(w, "\nVIOLATIONS")
+	if err != nil {
+		return fmt.Errorf("fmt.Fprintln:%w", err)
+	}
+	for _, v := range violations {
+		_, err = fmt.Fprintln(w, v)
+		if err != nil {
+			return fmt.Errorf("fmt.Fprintln:%w", err)
+		}
+	}
+	return nil
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
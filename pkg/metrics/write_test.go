@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var report = Report{
+	Nodes: []NodeMetrics{
+		{Node: "a.A", Package: "a", FanIn: 0, FanOut: 1, Transitive: 1, Depth: 0},
+		{Node: "b.B", Package: "b", FanIn: 1, FanOut: 0, Transitive: 0, Depth: 1},
+	},
+	Packages: []PackageMetrics{
+		{Package: "a", Afferent: 0, Efferent: 1, Instability: 1, Abstractness: 0, Distance: 0},
+		{Package: "b", Afferent: 1, Efferent: 0, Instability: 0, Abstractness: 0.25, Distance: 0.75},
+	},
+	Violations: []string{"b: distance 0.75 exceeds 0.50"},
+}
+
+func TestWriteTable(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := WriteTable(buf, report)
+	require.NoError(t, err)
+
+	assert.Equal(t, `NODE  FAN-IN  FAN-OUT  TRANSITIVE  DEPTH
+a.A   0       1        1           0
+b.B   1       0        0           1
+
+PACKAGE  CA  CE  I     A     D
+a        0   1   1.00  0.00  0.00
+b        1   0   0.00  0.25  0.75
+
+VIOLATIONS
+b: distance 0.75 exceeds 0.50
+`, buf.String())
+}
+
+func TestWriteCSV(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := WriteCSV(buf, report)
+	require.NoError(t, err)
+
+	assert.Equal(t, `kind,name,fan_in,fan_out,transitive,depth,afferent,efferent,instability,abstractness,distance
+node,a.A,0,1,1,0,,,,,
+node,b.B,1,0,0,1,,,,,
+package,a,,,,,0,1,1.00,0.00,0.00
+package,b,,,,,1,0,0.00,0.25,0.75
+`, buf.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := WriteJSON(buf, Report{
+		Nodes:      []NodeMetrics{{Node: "a.A", Package: "a", Transitive: 1}},
+		Packages:   []PackageMetrics{},
+		Violations: []string{},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `{
+  "nodes": [
+    {
+      "node": "a.A",
+      "package": "a",
+      "fan_in": 0,
+      "fan_out": 0,
+      "transitive": 1,
+      "depth": 0
+    }
+  ],
+  "packages": [],
+  "violations": []
+}
+`, buf.String())
+}
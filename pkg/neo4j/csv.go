@@ -0,0 +1,90 @@
+package neo4j
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+)
+
+// The files written by WriteCSV.
+const (
+	FilePackages      = "packages.csv"
+	FileComponents    = "components.csv"
+	FileExternalTypes = "external_types.csv"
+	FileInPackage     = "in_package.csv"
+	FileDependsOn     = "depends_on.csv"
+)
+
+const (
+	idSpacePackage = "Package"
+	idSpaceType    = "Type" // shared by the components and the external types, the ends of a :DEPENDS_ON
+	arrayDelimiter = ";"    // the default array delimiter of neo4j-admin import
+)
+
+// WriteCSV writes into dir the node and relationship files of neo4j-admin import, with their header.
+// The docs may span several lines, the import must be run with --multiline-fields=true.
+func WriteCSV(dir string, s parse.AstSchema) error {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return fmt.Errorf("os.MkdirAll:%w", err)
+	}
+
+	packages := [][]string{{"path:ID(" + idSpacePackage + ")", "external:boolean", ":LABEL"}}
+	for _, p := range getPackages(s) {
+		packages = append(packages, []string{p.path, strconv.FormatBool(p.external), LabelPackage})
+	}
+
+	typeHeader := []string{"name:ID(" + idSpaceType + ")", "package", "struct", "doc", "file", ":LABEL"}
+	components := [][]string{typeHeader}
+	externalTypes := [][]string{typeHeader}
+	inPackage := [][]string{{":START_ID(" + idSpaceType + ")", ":END_ID(" + idSpacePackage + ")", ":TYPE"}}
+	dependsOn := [][]string{{":START_ID(" + idSpaceType + ")", ":END_ID(" + idSpaceType + ")", "funcs:string[]", ":TYPE"}}
+	for _, node := range s.Graph.GetNodesSortedByName() {
+		record := []string{node.Name, node.PackageName, node.StructName, node.Doc, node.GetRelativeFilePath(s.Dir), getLabel(node)}
+		if node.External {
+			externalTypes = append(externalTypes, record)
+		} else {
+			components = append(components, record)
+		}
+		inPackage = append(inPackage, []string{node.Name, node.PackageName, TypeInPackage})
+		for _, adj := range s.Graph.GetAdjacenciesSortedByName(node) {
+			dependsOn = append(dependsOn, []string{node.Name, adj.Node.Name, strings.Join(adj.Func, arrayDelimiter), TypeDependsOn})
+		}
+	}
+
+	for _, file := range []struct {
+		name    string
+		records [][]string
+	}{
+		{name: FilePackages, records: packages},
+		{name: FileComponents, records: components},
+		{name: FileExternalTypes, records: externalTypes},
+		{name: FileInPackage, records: inPackage},
+		{name: FileDependsOn, records: dependsOn},
+	} {
+		err = writeCSVFile(filepath.Join(dir, file.name), file.records)
+		if err != nil {
+			return fmt.Errorf("%s:%w", file.name, err)
+		}
+	}
+	return nil
+}
+
+func writeCSVFile(path string, records [][]string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("os.Create:%w", err)
+	}
+	defer f.Close()
+
+	err = csv.NewWriter(f).WriteAll(records)
+	if err != nil {
+		return fmt.Errorf("cw.WriteAll:%w", err)
+	}
+	return f.Close()
+}
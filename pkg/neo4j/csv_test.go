@@ -0,0 +1,45 @@
+package neo4j
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCSV(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "import")
+	err := WriteCSV(dir, getSchema())
+	require.NoError(t, err)
+
+	for file, expected := range map[string]string{
+		FilePackages: `path:ID(Package),external:boolean,:LABEL
+github.com/lib/http,true,Package
+mod/app,false,Package
+`,
+		FileComponents: `name:ID(Type),package,struct,doc,file,:LABEL
+mod/app.Repository,mod/app,Repository,,app/repository.go,Component
+mod/app.Service,mod/app,Service,"The ""main"" service,
+with a \ backslash.",app/service.go,Component
+`,
+		FileExternalTypes: `name:ID(Type),package,struct,doc,file,:LABEL
+github.com/lib/http.Client,github.com/lib/http,Client,,,ExternalType
+`,
+		FileInPackage: `:START_ID(Type),:END_ID(Package),:TYPE
+github.com/lib/http.Client,github.com/lib/http,IN_PACKAGE
+mod/app.Repository,mod/app,IN_PACKAGE
+mod/app.Service,mod/app,IN_PACKAGE
+`,
+		FileDependsOn: `:START_ID(Type),:END_ID(Type),funcs:string[],:TYPE
+mod/app.Repository,github.com/lib/http.Client,,DEPENDS_ON
+mod/app.Service,github.com/lib/http.Client,Do,DEPENDS_ON
+mod/app.Service,mod/app.Repository,Get;List,DEPENDS_ON
+`,
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(content), file)
+	}
+}
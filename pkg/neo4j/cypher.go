@@ -0,0 +1,71 @@
+package neo4j
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+)
+
+var cypherEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// WriteCypher writes the MERGE statements creating the graph, running them again updates the existing nodes and
+// relationships instead of duplicating them. The properties are always set, a missing doc or file is set to null,
+// removing it.
+func WriteCypher(w io.Writer, s parse.AstSchema) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "// %s\n", strings.ReplaceAll(s.ModulePath, "\n", " "))
+	fmt.Fprintf(bw, "CREATE CONSTRAINT package_path IF NOT EXISTS FOR (p:%s) REQUIRE p.path IS UNIQUE;\n", LabelPackage)
+	fmt.Fprintf(bw, "CREATE CONSTRAINT component_name IF NOT EXISTS FOR (n:%s) REQUIRE n.name IS UNIQUE;\n", LabelComponent)
+	fmt.Fprintf(bw, "CREATE CONSTRAINT external_type_name IF NOT EXISTS FOR (n:%s) REQUIRE n.name IS UNIQUE;\n", LabelExternalType)
+
+	bw.WriteString("\n")
+	for _, p := range getPackages(s) {
+		fmt.Fprintf(bw, "MERGE (p:%s {path: %s}) SET p.external = %t;\n", LabelPackage, quote(p.path), p.external)
+	}
+
+	bw.WriteString("\n")
+	nodes := s.Graph.GetNodesSortedByName()
+	for _, node := range nodes {
+		fmt.Fprintf(bw, "MERGE (n:%s {name: %s}) SET n.package = %s, n.struct = %s, n.doc = %s, n.file = %s;\n",
+			getLabel(node), quote(node.Name), quote(node.PackageName), quote(node.StructName), quoteOrNull(node.Doc), quoteOrNull(node.GetRelativeFilePath(s.Dir)))
+	}
+
+	bw.WriteString("\n")
+	for _, node := range nodes {
+		fmt.Fprintf(bw, "MATCH (n:%s {name: %s}), (p:%s {path: %s}) MERGE (n)-[:%s]->(p);\n",
+			getLabel(node), quote(node.Name), LabelPackage, quote(node.PackageName), TypeInPackage)
+	}
+
+	bw.WriteString("\n")
+	for _, node := range nodes {
+		for _, adj := range s.Graph.GetAdjacenciesSortedByName(node) {
+			funcs := make([]string, 0, len(adj.Func))
+			for _, f := range adj.Func {
+				funcs = append(funcs, quote(f))
+			}
+			fmt.Fprintf(bw, "MATCH (a:%s {name: %s}), (b:%s {name: %s}) MERGE (a)-[r:%s]->(b) SET r.funcs = [%s];\n",
+				getLabel(node), quote(node.Name), getLabel(adj.Node), quote(adj.Node.Name), TypeDependsOn, strings.Join(funcs, ", "))
+		}
+	}
+
+	err := bw.Flush()
+	if err != nil {
+		return fmt.Errorf("bw.Flush:%w", err)
+	}
+	return nil
+}
+
+// quote returns a Cypher string literal.
+func quote(s string) string {
+	return `"` + cypherEscaper.Replace(s) + `"`
+}
+
+func quoteOrNull(s string) string {
+	if s == "" {
+		return "null"
+	}
+	return quote(s)
+}
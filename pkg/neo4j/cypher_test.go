@@ -0,0 +1,65 @@
+package neo4j
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getSchema() parse.AstSchema {
+	graph := parse.NewGraph()
+	service := &parse.Node{Name: "mod/app.Service", PackageName: "mod/app", StructName: "Service", FilePath: "/src/mod/app/service.go", Doc: "The \"main\" service,\nwith a \\ backslash."}
+	repository := &parse.Node{Name: "mod/app.Repository", PackageName: "mod/app", StructName: "Repository", FilePath: "/src/mod/app/repository.go"}
+	client := &parse.Node{Name: "github.com/lib/http.Client", PackageName: "github.com/lib/http", StructName: "Client", External: true}
+	graph.AddNode(service)
+	graph.AddNode(repository)
+	graph.AddNode(client)
+	graph.AddEdge(service, &parse.Adj{Node: repository, Func: []string{"Get", "List"}})
+	graph.AddEdge(service, &parse.Adj{Node: client, Func: []string{"Do"}})
+	graph.AddEdge(repository, &parse.Adj{Node: client})
+	return parse.AstSchema{ModulePath: "mod", Dir: "/src/mod", Graph: graph}
+}
+
+func TestWriteCypher(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := WriteCypher(buf, getSchema())
+	require.NoError(t, err)
+
+	assert.Equal(t, `// mod
+CREATE CONSTRAINT package_path IF NOT EXISTS FOR (p:Package) REQUIRE p.path IS UNIQUE;
+CREATE CONSTRAINT component_name IF NOT EXISTS FOR (n:Component) REQUIRE n.name IS UNIQUE;
+CREATE CONSTRAINT external_type_name IF NOT EXISTS FOR (n:ExternalType) REQUIRE n.name IS UNIQUE;
+
+MERGE (p:Package {path: "github.com/lib/http"}) SET p.external = true;
+MERGE (p:Package {path: "mod/app"}) SET p.external = false;
+
+MERGE (n:ExternalType {name: "github.com/lib/http.Client"}) SET n.package = "github.com/lib/http", n.struct = "Client", n.doc = null, n.file = null;
+MERGE (n:Component {name: "mod/app.Repository"}) SET n.package = "mod/app", n.struct = "Repository", n.doc = null, n.file = "app/repository.go";
+MERGE (n:Component {name: "mod/app.Service"}) SET n.package = "mod/app", n.struct = "Service", n.doc = "The \"main\" service,\nwith a \\ backslash.", n.file = "app/service.go";
+
+MATCH (n:ExternalType {name: "github.com/lib/http.Client"}), (p:Package {path: "github.com/lib/http"}) MERGE (n)-[:IN_PACKAGE]->(p);
+MATCH (n:Component {name: "mod/app.Repository"}), (p:Package {path: "mod/app"}) MERGE (n)-[:IN_PACKAGE]->(p);
+MATCH (n:Component {name: "mod/app.Service"}), (p:Package {path: "mod/app"}) MERGE (n)-[:IN_PACKAGE]->(p);
+
+MATCH (a:Component {name: "mod/app.Repository"}), (b:ExternalType {name: "github.com/lib/http.Client"}) MERGE (a)-[r:DEPENDS_ON]->(b) SET r.funcs = [];
+MATCH (a:Component {name: "mod/app.Service"}), (b:ExternalType {name: "github.com/lib/http.Client"}) MERGE (a)-[r:DEPENDS_ON]->(b) SET r.funcs = ["Do"];
+MATCH (a:Component {name: "mod/app.Service"}), (b:Component {name: "mod/app.Repository"}) MERGE (a)-[r:DEPENDS_ON]->(b) SET r.funcs = ["Get", "List"];
+`, buf.String())
+}
+
+func TestWriteCypher_withParse(t *testing.T) {
+	as, err := parse.Parse("../diagrams/testdata/named_inter", nil)
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	err = WriteCypher(buf, as)
+	require.NoError(t, err)
+
+	assert.Contains(t, buf.String(), `MERGE (n:Component {name: "testdata/named_inter/pa.A"}) SET n.package = "testdata/named_inter/pa", n.struct = "A", n.doc = "A pa struct.", n.file = "pa/a.go";
+`)
+	assert.Contains(t, buf.String(), `MATCH (a:Component {name: "testdata/named_inter.A"}), (b:Component {name: "testdata/named_inter.B"}) MERGE (a)-[r:DEPENDS_ON]->(b) SET r.funcs = ["FuncA", "FuncB"];
+`)
+}
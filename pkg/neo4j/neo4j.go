@@ -0,0 +1,48 @@
+// Package neo4j exports the dependency graph to be imported in a Neo4j database, either as idempotent Cypher statements
+// or as the CSV files of neo4j-admin import. Nothing connects to a database, only files are written.
+//
+// The structs of the project are :Component nodes, the structs outside of it :ExternalType nodes, both are linked to
+// their :Package node by an :IN_PACKAGE relationship, and to their dependencies by a :DEPENDS_ON relationship holding
+// the methods used.
+package neo4j
+
+import (
+	mymap "github.com/emilien-puget/go-dependency-graph/pkg/map"
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+)
+
+const (
+	LabelComponent    = "Component"
+	LabelExternalType = "ExternalType"
+	LabelPackage      = "Package"
+	TypeDependsOn     = "DEPENDS_ON"
+	TypeInPackage     = "IN_PACKAGE"
+)
+
+type pkg struct {
+	path     string
+	external bool
+}
+
+// getPackages returns the packages of the graph sorted by path, a package is external when all its nodes are.
+func getPackages(s parse.AstSchema) []pkg {
+	packages := make([]pkg, 0, len(s.Graph.NodesByPackage))
+	for _, path := range mymap.OrderedKeys(s.Graph.NodesByPackage) {
+		p := pkg{path: path, external: true}
+		for _, node := range s.Graph.NodesByPackage[path] {
+			if !node.External {
+				p.external = false
+				break
+			}
+		}
+		packages = append(packages, p)
+	}
+	return packages
+}
+
+func getLabel(node *parse.Node) string {
+	if node.External {
+		return LabelExternalType
+	}
+	return LabelComponent
+}
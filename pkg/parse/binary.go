@@ -0,0 +1,47 @@
+package parse
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Binary is a main package of the module, a deployable unit.
+type Binary struct {
+	Package  string   // The path of the main package
+	Packages []string // The packages of the module the binary is built from, the main package included, sorted
+}
+
+// searchBinaries returns the main packages of the module, sorted by path, with the packages of the module they
+// import, directly or not.
+func searchBinaries(pkgs []*packages.Package, modulePath string) []Binary {
+	var binaries []Binary
+	for _, p := range pkgs {
+		if p.Name != "main" {
+			continue
+		}
+		seen := make(map[string]bool)
+		collectModuleImports(p, modulePath, seen)
+		binary := Binary{Package: p.ID, Packages: make([]string, 0, len(seen))}
+		for packageName := range seen {
+			binary.Packages = append(binary.Packages, packageName)
+		}
+		sort.Strings(binary.Packages)
+		binaries = append(binaries, binary)
+	}
+	sort.Slice(binaries, func(i, j int) bool {
+		return binaries[i].Package < binaries[j].Package
+	})
+	return binaries
+}
+
+func collectModuleImports(p *packages.Package, modulePath string, seen map[string]bool) {
+	if seen[p.ID] || (p.ID != modulePath && !strings.HasPrefix(p.ID, modulePath+"/")) {
+		return
+	}
+	seen[p.ID] = true
+	for _, imported := range p.Imports {
+		collectModuleImports(imported, modulePath, seen)
+	}
+}
@@ -0,0 +1,218 @@
+package parse
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+)
+
+// fieldRef is a field of the receiver of a method.
+type fieldRef struct {
+	Name string
+	Type types.Type
+}
+
+// searchCalls records on every edge the methods of the dependency called by the methods of the consumer, and on every
+// node the calls made by each of its methods, in order.
+// The method bodies are searched for calls on the receiver fields, such as s.repo.Find(), a local variable holding a
+// field is followed. The calls of the other methods of the receiver, such as s.purge(), are followed transitively,
+// the calls they make are attributed to the calling method too. A call is attributed to the edges whose dependency
+// was assigned to the field, or, when the field is unknown, whose dependency is of the field type or implements it.
+func searchCalls(graph *Graph, index *declIndex) {
+	for _, node := range graph.GetNodesSortedByName() {
+		adjacencies := graph.GetAdjacency(node)
+		if node.ActualNamedType == nil || len(adjacencies) == 0 {
+			continue
+		}
+		for i := 0; i < node.ActualNamedType.NumMethods(); i++ {
+			method := node.ActualNamedType.Method(i)
+			src, ok := index.funcDecls[method]
+			if !ok {
+				continue
+			}
+			searchMethodCalls(src, node.ActualNamedType, adjacencies, index)
+			if steps := searchSequence(src, adjacencies); len(steps) > 0 {
+				if node.Sequences == nil {
+					node.Sequences = make(map[string][]Step)
+				}
+				node.Sequences[method.Name()] = steps
+			}
+		}
+		for _, adj := range adjacencies {
+			sortCalls(adj.Calls)
+		}
+	}
+}
+
+func searchMethodCalls(src funcSource, named *types.Named, adjacencies []*Adj, index *declIndex) {
+	c := callSearch{
+		caller:      src.Decl.Name.Name,
+		named:       named,
+		adjacencies: adjacencies,
+		index:       index,
+		visited:     make(map[*ast.FuncDecl]bool),
+	}
+	c.search(src)
+}
+
+// callSearch attributes to the edges the calls made by a method, and by the methods of the receiver it calls.
+type callSearch struct {
+	caller      string
+	named       *types.Named
+	adjacencies []*Adj
+	index       *declIndex
+	visited     map[*ast.FuncDecl]bool
+}
+
+func (c callSearch) search(src funcSource) {
+	if c.visited[src.Decl] {
+		return
+	}
+	c.visited[src.Decl] = true
+	fields, ok := newReceiverFields(src.Decl, src.P.TypesInfo)
+	if !ok {
+		return
+	}
+
+	ast.Inspect(src.Decl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			fields.track(node)
+		case *ast.CallExpr:
+			sel, ok := node.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if fields.isReceiver(sel.X) {
+				c.follow(src.P.TypesInfo.Uses[sel.Sel])
+				return true
+			}
+			field, ok := fields.get(sel.X)
+			if !ok {
+				return true
+			}
+			for _, adj := range c.adjacencies {
+				if isInjectedIn(adj, field) {
+					adj.Calls = appendCall(adj.Calls, Call{Caller: c.caller, Callee: sel.Sel.Name, Field: field.Name})
+				}
+			}
+		}
+		return true
+	})
+}
+
+// follow searches the calls of a method of the receiver, the methods promoted from an embedded field are not followed.
+func (c callSearch) follow(obj types.Object) {
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil || !types.Identical(derefType(recv.Type()), c.named) {
+		return
+	}
+	if src, ok := c.index.funcDecls[fn]; ok {
+		c.search(src)
+	}
+}
+
+// receiverFields resolves the expressions holding a field of the receiver of a method.
+type receiverFields struct {
+	info    *types.Info
+	recv    types.Object
+	aliases map[types.Object]fieldRef // local variables holding a receiver field.
+}
+
+func newReceiverFields(decl *ast.FuncDecl, info *types.Info) (*receiverFields, bool) {
+	if decl.Body == nil || decl.Recv == nil || len(decl.Recv.List) == 0 || len(decl.Recv.List[0].Names) == 0 {
+		return nil, false
+	}
+	recv := info.Defs[decl.Recv.List[0].Names[0]]
+	if recv == nil {
+		return nil, false
+	}
+	return &receiverFields{info: info, recv: recv, aliases: make(map[types.Object]fieldRef)}, true
+}
+
+// get returns the field held by the expression, a selector on the receiver or a local variable holding a field.
+func (r *receiverFields) get(expr ast.Expr) (fieldRef, bool) {
+	switch e := expr.(type) {
+	case *ast.SelectorExpr:
+		if !r.isReceiver(e.X) {
+			return fieldRef{}, false
+		}
+		if _, ok := r.info.Uses[e.Sel].(*types.Var); !ok {
+			return fieldRef{}, false
+		}
+		return fieldRef{Name: e.Sel.Name, Type: r.info.TypeOf(e)}, true
+	case *ast.Ident:
+		field, ok := r.aliases[r.info.Uses[e]]
+		return field, ok
+	}
+	return fieldRef{}, false
+}
+
+// track records the local variables the assignment sets to a field.
+func (r *receiverFields) track(assign *ast.AssignStmt) {
+	if len(assign.Lhs) != len(assign.Rhs) {
+		return
+	}
+	for i, lhs := range assign.Lhs {
+		ident, ok := lhs.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		if field, ok := r.get(assign.Rhs[i]); ok {
+			if obj := getObject(r.info, ident); obj != nil {
+				r.aliases[obj] = field
+			}
+		}
+	}
+}
+
+func (r *receiverFields) isReceiver(expr ast.Expr) bool {
+	x, ok := expr.(*ast.Ident)
+	return ok && r.info.Uses[x] == r.recv
+}
+
+// isInjectedIn reports whether the dependency of the edge is the one held by the field.
+func isInjectedIn(adj *Adj, field fieldRef) bool {
+	if len(adj.Fields) > 0 {
+		return containsString(adj.Fields, field.Name)
+	}
+	if field.Type == nil {
+		return false
+	}
+	if named := getNamedType(field.Type); named != nil && getTypeName(named) == adj.Node.Name {
+		return true
+	}
+	iface, ok := field.Type.Underlying().(*types.Interface)
+	if !ok || iface.NumMethods() == 0 {
+		return false
+	}
+	if adj.Via != "" && adj.Via == types.TypeString(field.Type, nil) {
+		return true
+	}
+	if adj.Node.ActualNamedType == nil {
+		return false
+	}
+	return types.Implements(adj.Node.ActualNamedType, iface) || types.Implements(types.NewPointer(adj.Node.ActualNamedType), iface)
+}
+
+func appendCall(calls []Call, call Call) []Call {
+	for _, c := range calls {
+		if c == call {
+			return calls
+		}
+	}
+	return append(calls, call)
+}
+
+func sortCalls(calls []Call) {
+	sort.SliceStable(calls, func(i, j int) bool {
+		if calls[i].Caller != calls[j].Caller {
+			return calls[i].Caller < calls[j].Caller
+		}
+		return calls[i].Callee < calls[j].Callee
+	})
+}
@@ -0,0 +1,287 @@
+package parse
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	mymap "github.com/emilien-puget/go-dependency-graph/pkg/map"
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse/struct_decl"
+	"golang.org/x/tools/go/packages"
+)
+
+const compositionRootPackage = "main"
+
+// compositionParser follows the values created in the composition roots, the functions of the main packages.
+type compositionParser struct {
+	*declIndex
+	schema         *AstSchema
+	t              map[string]map[string]*struct_decl.Decl
+	docs           map[string]string
+	packagesByPath map[string]*packages.Package
+	edges          map[*Node]map[string]*Adj
+}
+
+// parseComposition builds the graph of the values instantiated in the main packages.
+// Each call returning a struct of the project creates an instance of its node, which is linked to the instances given as arguments.
+func parseComposition(pkgs []*packages.Package, schema *AstSchema, t map[string]map[string]*struct_decl.Decl, index *declIndex) {
+	cp := &compositionParser{
+		declIndex:      index,
+		schema:         schema,
+		t:              t,
+		docs:           getStructDocs(pkgs),
+		packagesByPath: make(map[string]*packages.Package),
+		edges:          make(map[*Node]map[string]*Adj),
+	}
+	for _, p := range pkgs {
+		cp.packagesByPath[p.PkgPath] = p
+	}
+
+	for _, p := range pkgs {
+		if p.Name != compositionRootPackage {
+			continue
+		}
+		for _, f := range p.Syntax {
+			for _, decl := range f.Decls {
+				d, ok := decl.(*ast.FuncDecl)
+				if !ok || d.Body == nil {
+					continue
+				}
+				cp.walk(d.Body, p, make(map[types.Object][]*Node))
+			}
+		}
+	}
+}
+
+// walk follows the statements of a function body, env holds the nodes of the instances each local variable may hold.
+func (cp *compositionParser) walk(body ast.Node, p *packages.Package, env map[types.Object][]*Node) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			cp.assign(stmt.Lhs, stmt.Rhs, p, env)
+			return false
+		case *ast.ValueSpec:
+			lhs := make([]ast.Expr, 0, len(stmt.Names))
+			for _, name := range stmt.Names {
+				lhs = append(lhs, name)
+			}
+			cp.assign(lhs, stmt.Values, p, env)
+			return false
+		case *ast.CallExpr, *ast.CompositeLit:
+			cp.eval(stmt.(ast.Expr), p, env)
+			return false
+		}
+		return true
+	})
+}
+
+// assign binds the instances of the right hand side to the variables, or links them to the instance whose field is assigned.
+func (cp *compositionParser) assign(lhs, rhs []ast.Expr, p *packages.Package, env map[types.Object][]*Node) {
+	values := make([][]*Node, len(lhs))
+	switch {
+	case len(lhs) == len(rhs):
+		for i := range rhs {
+			values[i] = cp.eval(rhs[i], p, env)
+		}
+	case len(rhs) == 1: // a provider returning several values, such as an error.
+		values[0] = cp.eval(rhs[0], p, env)
+	default:
+		for i := range rhs {
+			cp.eval(rhs[i], p, env)
+		}
+	}
+
+	for i, expr := range lhs {
+		switch e := expr.(type) {
+		case *ast.Ident:
+			if obj := getObject(p.TypesInfo, e); obj != nil {
+				env[obj] = values[i]
+			}
+		case *ast.SelectorExpr: // field setter, such as s.repo = repo.
+			x, ok := e.X.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			for _, consumer := range env[getObject(p.TypesInfo, x)] {
+				for _, node := range values[i] {
+					cp.link(consumer, node, cp.getFieldFuncs(consumer, e.Sel.Name), "")
+				}
+			}
+		}
+	}
+}
+
+// eval returns the nodes of the instances an expression may evaluate to, the instances it creates are counted.
+func (cp *compositionParser) eval(expr ast.Expr, p *packages.Package, env map[types.Object][]*Node) []*Node {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return cp.eval(e.X, p, env)
+	case *ast.Ident:
+		return env[getObject(p.TypesInfo, e)]
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			return cp.eval(e.X, p, env)
+		}
+	case *ast.CompositeLit:
+		return cp.evalCompositeLit(e, p, env)
+	case *ast.CallExpr:
+		return cp.evalCall(e, p, env)
+	case *ast.FuncLit:
+		cp.walk(e.Body, p, env)
+	}
+	return nil
+}
+
+// evalCompositeLit creates an instance of a struct of the project, linked to the instances given to its fields.
+func (cp *compositionParser) evalCompositeLit(lit *ast.CompositeLit, p *packages.Package, env map[types.Object][]*Node) []*Node {
+	fields := make(map[string][]*Node)
+	for _, elt := range lit.Elts {
+		kvExpr, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			cp.eval(elt, p, env)
+			continue
+		}
+		values := cp.eval(kvExpr.Value, p, env)
+		if key, ok := kvExpr.Key.(*ast.Ident); ok {
+			fields[key.Name] = values
+		}
+	}
+
+	node := cp.newInstance(p.TypesInfo.TypeOf(lit))
+	if node == nil {
+		return nil
+	}
+	for _, field := range mymap.OrderedKeys(fields) {
+		for _, dependency := range fields[field] {
+			cp.link(node, dependency, cp.getFieldFuncs(node, field), "")
+		}
+	}
+	return []*Node{node}
+}
+
+// evalCall creates an instance when the function called returns a struct of the project, linked to the instances given as arguments.
+func (cp *compositionParser) evalCall(call *ast.CallExpr, p *packages.Package, env map[types.Object][]*Node) []*Node {
+	args := make([][]*Node, len(call.Args))
+	for i, arg := range call.Args {
+		args[i] = cp.eval(arg, p, env)
+	}
+
+	fn := getCalledFunc(p.TypesInfo, call)
+	if fn == nil {
+		return nil
+	}
+	sig := fn.Type().(*types.Signature)
+	if sig.Results().Len() == 0 {
+		return nil
+	}
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sig.Recv() != nil && types.Identical(derefType(sig.Results().At(0).Type()), derefType(sig.Recv().Type())) {
+		return cp.eval(sel.X, p, env) // chained method returning its receiver, such as a builder.
+	}
+	provider := &diProvider{}
+	if src, ok := cp.funcDecls[fn]; ok {
+		provider.Fn, provider.FnP = src.Decl, src.P
+	}
+	node := cp.newInstance(provider.getConcreteType(diValue{Type: sig.Results().At(0).Type(), Index: 0}))
+	if node == nil {
+		return nil
+	}
+
+	for i := range args {
+		if len(args[i]) == 0 {
+			continue
+		}
+		paramIndex := i
+		if paramIndex >= sig.Params().Len() {
+			paramIndex = sig.Params().Len() - 1 // variadic parameter.
+		}
+		param := sig.Params().At(paramIndex)
+		funcs := cp.getParamFuncs(provider.Fn, provider.FnP, param.Name(), node)
+		via := ""
+		if iface := getNamedInterface(param.Type()); iface != nil {
+			via = getTypeName(iface)
+			if len(funcs) == 0 {
+				funcs = getInterfaceMethods(iface.Underlying().(*types.Interface))
+			}
+		}
+		for _, dependency := range args[i] {
+			cp.link(node, dependency, funcs, via)
+		}
+	}
+	return []*Node{node}
+}
+
+// newInstance counts a new instance of t, it returns nil if t is not a struct of the project.
+func (cp *compositionParser) newInstance(t types.Type) *Node {
+	if t == nil {
+		return nil
+	}
+	named := getNamedType(t)
+	if named == nil {
+		return nil
+	}
+	if _, ok := cp.t[named.Obj().Pkg().Path()][named.Obj().Name()]; !ok {
+		return nil
+	}
+	node := cp.schema.Graph.GetNodeByName(getTypeName(named))
+	if node == nil {
+		node = newDINode(named, cp.schema.ModulePath, &diProvider{}, cp.t, cp.docs, cp.packagesByPath)
+		cp.schema.Graph.AddNode(node)
+	}
+	node.Instances++
+	return node
+}
+
+// link adds an edge from the consumer to the dependency, an edge already added is completed with the new funcs.
+func (cp *compositionParser) link(consumer, dependency *Node, funcs []string, via string) {
+	if consumer == dependency {
+		return
+	}
+	if via == dependency.Name {
+		via = ""
+	}
+	if cp.edges[consumer] == nil {
+		cp.edges[consumer] = make(map[string]*Adj)
+	}
+	key := dependency.Name + "|" + via
+	adj, ok := cp.edges[consumer][key]
+	if !ok {
+		adj = &Adj{Node: dependency, Via: via}
+		cp.edges[consumer][key] = adj
+		cp.schema.Graph.AddEdge(consumer, adj)
+	}
+	adj.Func = appendUnique(adj.Func, funcs...)
+}
+
+// getParamFuncs returns the functions of a parameter injected in the struct returned by the provider.
+func (cp *compositionParser) getParamFuncs(fn *ast.FuncDecl, fnP *packages.Package, paramName string, node *Node) []string {
+	decl, ok := cp.t[node.PackageName][node.StructName]
+	if !ok || paramName == "" {
+		return nil
+	}
+	return searchInputFuncs(fn, fnP, diValue{VarName: paramName}, decl, cp.funcDecls)
+}
+
+func (cp *compositionParser) getFieldFuncs(node *Node, field string) []string {
+	decl, ok := cp.t[node.PackageName][node.StructName]
+	if !ok {
+		return nil
+	}
+	return decl.Fields[field].Methods
+}
+
+// getObject returns the object an identifier defines or uses.
+func getObject(info *types.Info, ident *ast.Ident) types.Object {
+	if obj := info.Defs[ident]; obj != nil {
+		return obj
+	}
+	return info.Uses[ident]
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package parse
+
+import (
+	"sort"
+)
+
+// StronglyConnectedComponents returns the strongly connected components of the graph, using Tarjan's algorithm.
+// The nodes of a component are sorted by name, the components by the name of their first node.
+func (g *Graph) StronglyConnectedComponents() [][]*Node {
+	index := 0
+	indexes := make(map[*Node]int)
+	lowLinks := make(map[*Node]int)
+	onStack := make(map[*Node]bool)
+	stack := make([]*Node, 0)
+	components := make([][]*Node, 0)
+
+	var strongConnect func(node *Node)
+	strongConnect = func(node *Node) {
+		indexes[node] = index
+		lowLinks[node] = index
+		index++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, adj := range g.GetAdjacenciesSortedByName(node) {
+			if _, visited := indexes[adj.Node]; !visited {
+				strongConnect(adj.Node)
+				if lowLinks[adj.Node] < lowLinks[node] {
+					lowLinks[node] = lowLinks[adj.Node]
+				}
+			} else if onStack[adj.Node] && indexes[adj.Node] < lowLinks[node] {
+				lowLinks[node] = indexes[adj.Node]
+			}
+		}
+
+		if lowLinks[node] != indexes[node] {
+			return
+		}
+		var component []*Node
+		for {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[n] = false
+			component = append(component, n)
+			if n == node {
+				break
+			}
+		}
+		sort.SliceStable(component, func(i, j int) bool {
+			return component[i].Name < component[j].Name
+		})
+		components = append(components, component)
+	}
+
+	for _, node := range g.GetNodesSortedByName() {
+		if _, visited := indexes[node]; !visited {
+			strongConnect(node)
+		}
+	}
+
+	sort.SliceStable(components, func(i, j int) bool {
+		return components[i][0].Name < components[j][0].Name
+	})
+	return components
+}
+
+// GetCycles returns the strongly connected components forming a cycle, a single node depending on itself is a cycle.
+func (g *Graph) GetCycles() [][]*Node {
+	cycles := make([][]*Node, 0)
+	for _, component := range g.StronglyConnectedComponents() {
+		if len(component) > 1 || g.HasSelfEdge(component[0]) {
+			cycles = append(cycles, component)
+		}
+	}
+	return cycles
+}
+
+// GetCycleEdges returns the edges between the nodes of a same cycle, indexed by their source node.
+func (g *Graph) GetCycleEdges() map[*Node][]*Adj {
+	edges := make(map[*Node][]*Adj)
+	for _, cycle := range g.GetCycles() {
+		inCycle := make(map[*Node]bool, len(cycle))
+		for _, node := range cycle {
+			inCycle[node] = true
+		}
+		for _, node := range cycle {
+			for _, adj := range g.GetAdjacenciesSortedByName(node) {
+				if inCycle[adj.Node] {
+					edges[node] = append(edges[node], adj)
+				}
+			}
+		}
+	}
+	return edges
+}
+
+// IsCycleEdge reports whether the edge is part of a cycle, cycleEdges being the result of GetCycleEdges.
+func IsCycleEdge(cycleEdges map[*Node][]*Adj, from *Node, adj *Adj) bool {
+	for _, a := range cycleEdges[from] {
+		if a == adj {
+			return true
+		}
+	}
+	return false
+}
+
+// HasSelfEdge reports whether the node depends on itself, a cycle on its own.
+func (g *Graph) HasSelfEdge(node *Node) bool {
+	for _, adj := range g.Adj[node] {
+		if adj.Node == node {
+			return true
+		}
+	}
+	return false
+}
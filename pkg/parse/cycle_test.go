@@ -0,0 +1,62 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraph_cycles(t *testing.T) {
+	graph := NewGraph()
+
+	nodeA := &Node{Name: "A"}
+	nodeB := &Node{Name: "B"}
+	nodeC := &Node{Name: "C"}
+	nodeD := &Node{Name: "D"}
+	nodeE := &Node{Name: "E"}
+
+	graph.AddNode(nodeA)
+	graph.AddNode(nodeB)
+	graph.AddNode(nodeC)
+	graph.AddNode(nodeD)
+	graph.AddNode(nodeE)
+
+	ab := &Adj{Node: nodeB}
+	bc := &Adj{Node: nodeC}
+	ca := &Adj{Node: nodeA}
+	da := &Adj{Node: nodeA}
+	ee := &Adj{Node: nodeE}
+	graph.AddEdge(nodeA, ab)
+	graph.AddEdge(nodeB, bc)
+	graph.AddEdge(nodeC, ca)
+	graph.AddEdge(nodeD, da)
+	graph.AddEdge(nodeE, ee)
+
+	t.Run("TestStronglyConnectedComponents", func(t *testing.T) {
+		assert.Equal(t, [][]*Node{{nodeA, nodeB, nodeC}, {nodeD}, {nodeE}}, graph.StronglyConnectedComponents())
+	})
+
+	t.Run("TestGetCycles", func(t *testing.T) {
+		assert.Equal(t, [][]*Node{{nodeA, nodeB, nodeC}, {nodeE}}, graph.GetCycles())
+	})
+
+	t.Run("TestGetCycleEdges", func(t *testing.T) {
+		cycleEdges := graph.GetCycleEdges()
+		assert.Equal(t, map[*Node][]*Adj{nodeA: {ab}, nodeB: {bc}, nodeC: {ca}, nodeE: {ee}}, cycleEdges)
+		assert.True(t, IsCycleEdge(cycleEdges, nodeC, ca))
+		assert.False(t, IsCycleEdge(cycleEdges, nodeD, da))
+	})
+}
+
+func TestGraph_cycles_none(t *testing.T) {
+	graph := NewGraph()
+
+	nodeA := &Node{Name: "A"}
+	nodeB := &Node{Name: "B"}
+	graph.AddNode(nodeA)
+	graph.AddNode(nodeB)
+	graph.AddEdge(nodeA, &Adj{Node: nodeB})
+
+	assert.Empty(t, graph.GetCycles())
+	assert.Empty(t, graph.GetCycleEdges())
+}
@@ -0,0 +1,399 @@
+package parse
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse/struct_decl"
+	"golang.org/x/tools/go/packages"
+)
+
+// diValue is a value consumed or produced by a constructor registered in a dependency injection container.
+type diValue struct {
+	Type    types.Type
+	Name    string // The name:"..." tag
+	Group   string // The group:"..." tag
+	Index   int    // The position in the constructor signature, -1 for the fields of a parameter object
+	VarName string // The parameter name, or the parameter object name for its fields
+	Field   string // The struct field the value is read from or injected in
+}
+
+// diProvider is a constructor registered in a dependency injection container.
+type diProvider struct {
+	Pos     token.Pos
+	Module  string
+	Fn      *ast.FuncDecl // The constructor declaration, nil when it cannot be found
+	FnP     *packages.Package
+	Inputs  []diValue
+	Outputs []diValue
+	As      []types.Type // The interfaces the outputs are provided as
+	Self    bool         // The outputs are provided as their own type as well as As
+	Cleanup bool         // The constructor returns a cleanup function
+}
+
+// diBinding binds an interface to the type provided for it.
+type diBinding struct {
+	Interface      types.Type
+	Implementation types.Type
+}
+
+type funcSource struct {
+	Decl *ast.FuncDecl
+	P    *packages.Package
+}
+
+type varSource struct {
+	Value ast.Expr
+	P     *packages.Package
+}
+
+// declIndex stores the function declarations and package level variable values so they can be followed.
+type declIndex struct {
+	funcDecls map[*types.Func]funcSource
+	varValues map[*types.Var]varSource
+}
+
+func newDeclIndex(pkgs []*packages.Package) *declIndex {
+	di := &declIndex{
+		funcDecls: make(map[*types.Func]funcSource),
+		varValues: make(map[*types.Var]varSource),
+	}
+	for _, p := range pkgs {
+		for _, f := range p.Syntax {
+			for _, decl := range f.Decls {
+				switch d := decl.(type) {
+				case *ast.FuncDecl:
+					if fn, ok := p.TypesInfo.Defs[d.Name].(*types.Func); ok {
+						di.funcDecls[fn] = funcSource{Decl: d, P: p}
+					}
+				case *ast.GenDecl:
+					di.indexVars(d, p)
+				}
+			}
+		}
+	}
+	return di
+}
+
+func (di *declIndex) indexVars(d *ast.GenDecl, p *packages.Package) {
+	if d.Tok != token.VAR {
+		return
+	}
+	for _, spec := range d.Specs {
+		vs, ok := spec.(*ast.ValueSpec)
+		if !ok || len(vs.Values) != len(vs.Names) {
+			continue
+		}
+		for i, name := range vs.Names {
+			if v, ok := p.TypesInfo.Defs[name].(*types.Var); ok {
+				di.varValues[v] = varSource{Value: vs.Values[i], P: p}
+			}
+		}
+	}
+}
+
+// getFuncDecl returns the declaration of the constructor, a function literal is returned as an anonymous declaration.
+func (di *declIndex) getFuncDecl(expr ast.Expr, p *packages.Package) (*ast.FuncDecl, *packages.Package) {
+	if lit, ok := expr.(*ast.FuncLit); ok {
+		return &ast.FuncDecl{Name: ast.NewIdent("func"), Type: lit.Type, Body: lit.Body}, p
+	}
+	ident := getIdent(expr)
+	if ident == nil {
+		return nil, nil
+	}
+	fn, ok := p.TypesInfo.Uses[ident].(*types.Func)
+	if !ok {
+		return nil, nil
+	}
+	src, ok := di.funcDecls[fn]
+	if !ok {
+		return nil, nil
+	}
+	return src.Decl, src.P
+}
+
+// buildDIGraph adds a node per provided type and links each of them to the nodes providing the constructor inputs.
+func buildDIGraph(pkgs []*packages.Package, schema *AstSchema, t map[string]map[string]*struct_decl.Decl, funcDecls map[*types.Func]funcSource, providers []*diProvider, bindings []diBinding) {
+	docs := getStructDocs(pkgs)
+	packagesByPath := make(map[string]*packages.Package)
+	for _, p := range pkgs {
+		packagesByPath[p.PkgPath] = p
+	}
+
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].Pos < providers[j].Pos
+	})
+
+	registry := make(map[string][]*Node)
+	outputNodes := make(map[*diProvider][]*Node)
+	for _, provider := range providers {
+		for _, out := range provider.Outputs {
+			named := getNamedType(provider.getConcreteType(out))
+			if named == nil {
+				continue
+			}
+			node := newDINode(named, schema.ModulePath, provider, t, docs, packagesByPath)
+			schema.Graph.AddNode(node)
+			node = schema.Graph.GetNodeByName(node.Name)
+			outputNodes[provider] = append(outputNodes[provider], node)
+			for _, key := range provider.getKeys(out) {
+				registry[key] = append(registry[key], node)
+			}
+		}
+	}
+	for _, binding := range bindings {
+		ifaceKey := getValueKey(binding.Interface, "")
+		registry[ifaceKey] = append(registry[ifaceKey], registry[getValueKey(binding.Implementation, "")]...)
+	}
+
+	for _, provider := range providers {
+		for _, consumer := range outputNodes[provider] {
+			for _, in := range provider.Inputs {
+				addDIEdges(schema, consumer, provider, in, registry, t, funcDecls)
+			}
+		}
+	}
+}
+
+// addDIEdges links a consumer to the nodes providing one of its inputs.
+func addDIEdges(schema *AstSchema, consumer *Node, provider *diProvider, in diValue, registry map[string][]*Node, t map[string]map[string]*struct_decl.Decl, funcDecls map[*types.Func]funcSource) {
+	requested := in.Type
+	key := getValueKey(requested, in.Name)
+	if in.Group != "" {
+		slice, ok := requested.(*types.Slice)
+		if !ok {
+			return
+		}
+		requested = slice.Elem()
+		key = getGroupKey(requested, in.Group)
+	}
+	named := getNamedType(requested)
+	if named == nil || isFxInternalType(named) {
+		return
+	}
+
+	via := ""
+	var funcs []string
+	if decl, ok := t[consumer.PackageName][consumer.StructName]; ok {
+		funcs = searchInputFuncs(provider.Fn, provider.FnP, in, decl, funcDecls)
+	}
+	if iface := getNamedInterface(requested); iface != nil {
+		via = getTypeName(iface)
+		if len(funcs) == 0 {
+			funcs = getInterfaceMethods(iface.Underlying().(*types.Interface))
+		}
+	}
+
+	providedNodes := registry[key]
+	if len(providedNodes) == 0 {
+		if via == "" && !checkDepsMethods(requested) { // ignore dependencies without methods
+			return
+		}
+		pkgPath := named.Obj().Pkg().Path()
+		adjNode := &Node{
+			Name:        getTypeName(named),
+			PackageName: pkgPath,
+			StructName:  named.Obj().Name(),
+			External:    isExternal(pkgPath, schema.ModulePath),
+		}
+		schema.Graph.AddNode(adjNode)
+		schema.Graph.AddEdge(consumer, &Adj{Node: adjNode, Func: funcs, Param: in.VarName})
+		return
+	}
+
+	for _, node := range providedNodes {
+		if node.Name == consumer.Name {
+			continue
+		}
+		adj := &Adj{Node: node, Func: append([]string(nil), funcs...), Param: in.VarName}
+		if via != node.Name {
+			adj.Via = via
+		}
+		schema.Graph.AddEdge(consumer, adj)
+	}
+}
+
+// searchInputFuncs returns the functions of an input injected in the struct returned by the constructor.
+func searchInputFuncs(fn *ast.FuncDecl, fnP *packages.Package, in diValue, decl *struct_decl.Decl, funcDecls map[*types.Func]funcSource) []string {
+	if fn == nil {
+		if in.Field != "" { // the input is injected in the field by the container.
+			return decl.Fields[in.Field].Methods
+		}
+		return nil
+	}
+	if in.Field == "" {
+		deps := map[string][]dep{in.VarName: {{VarName: in.VarName}}}
+		searchDependenciesAssignment(fn, deps, decl, fnP.TypesInfo, funcDecls)
+		return deps[in.VarName][0].Funcs
+	}
+
+	// the input is a parameter object field, look for `field: params.Field`.
+	var funcs []string
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		kvExpr, ok := n.(*ast.KeyValueExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := kvExpr.Value.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != in.Field {
+			return true
+		}
+		x, ok := sel.X.(*ast.Ident)
+		key, keyOk := kvExpr.Key.(*ast.Ident)
+		if ok && keyOk && x.Name == in.VarName {
+			funcs = append(funcs, decl.Fields[key.Name].Methods...)
+		}
+		return true
+	})
+	return funcs
+}
+
+// getConcreteType returns the type actually returned for an output declared as an interface, when it can be found.
+func (provider *diProvider) getConcreteType(out diValue) types.Type {
+	if _, ok := out.Type.Underlying().(*types.Interface); !ok || provider.Fn == nil || provider.Fn.Body == nil || out.Index < 0 {
+		return out.Type
+	}
+
+	var concrete types.Type
+	ambiguous := false
+	ast.Inspect(provider.Fn.Body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(stmt.Results) <= out.Index {
+				return true
+			}
+			returned := provider.FnP.TypesInfo.TypeOf(stmt.Results[out.Index])
+			if returned == nil || getNamedType(returned) == nil {
+				return true
+			}
+			if _, ok := returned.Underlying().(*types.Interface); ok {
+				return true
+			}
+			if concrete != nil && !types.Identical(concrete, returned) { // several implementations are returned.
+				ambiguous = true
+			}
+			concrete = returned
+		}
+		return true
+	})
+	if concrete == nil || ambiguous {
+		return out.Type
+	}
+	return concrete
+}
+
+// getKeys returns the keys the output is registered under.
+func (provider *diProvider) getKeys(out diValue) []string {
+	provided := []types.Type{out.Type}
+	if len(provider.As) > 0 {
+		provided = provider.As
+		if provider.Self {
+			provided = append([]types.Type{out.Type}, provided...)
+		}
+	}
+	keys := make([]string, 0, len(provided))
+	for _, t := range provided {
+		if out.Group != "" {
+			keys = append(keys, getGroupKey(t, out.Group))
+			continue
+		}
+		keys = append(keys, getValueKey(t, out.Name))
+	}
+	return keys
+}
+
+func newDINode(named *types.Named, modulePath string, provider *diProvider, t map[string]map[string]*struct_decl.Decl, docs map[string]string, packagesByPath map[string]*packages.Package) *Node {
+	pkgPath := named.Obj().Pkg().Path()
+	name := named.Obj().Name()
+	node := &Node{
+		Name:        getTypeName(named),
+		PackageName: pkgPath,
+		StructName:  name,
+		Doc:         docs[pkgPath+"."+name],
+		External:    isExternal(pkgPath, modulePath),
+		Module:      provider.Module,
+		Cleanup:     provider.Cleanup,
+	}
+	if provider.Fn != nil && provider.FnP != nil {
+		node.Provider = provider.FnP.Fset.Position(provider.Fn.Pos())
+	}
+	if decl, ok := t[pkgPath][name]; ok {
+		node.Methods = decl.Methods
+		node.ActualNamedType = decl.ActualNamedType
+		node.FilePath = decl.FilePath
+		node.P = packagesByPath[pkgPath]
+	}
+	return node
+}
+
+func getValueKey(t types.Type, name string) string {
+	return types.TypeString(t, nil) + "|" + name
+}
+
+func getGroupKey(t types.Type, group string) string {
+	return "group:" + group + "|" + types.TypeString(t, nil)
+}
+
+// getGroupTag returns the group name of a group:"name,flatten" tag.
+func getGroupTag(tag reflect.StructTag) string {
+	group, _, _ := strings.Cut(tag.Get("group"), ",")
+	return group
+}
+
+// getNamedType returns the named type of t or of the type t points to, nil if there is none.
+func getNamedType(t types.Type) *types.Named {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return nil
+	}
+	return named
+}
+
+// getTypeName returns the fully qualified name of a named type, PackageName.TypeName.
+func getTypeName(named *types.Named) string {
+	return named.Obj().Pkg().Path() + "." + named.Obj().Name()
+}
+
+func isErrorType(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// getCalledFunc returns the function or method called, nil if it is not statically known.
+func getCalledFunc(info *types.Info, call *ast.CallExpr) *types.Func {
+	ident := getIdent(call.Fun)
+	if ident == nil {
+		return nil
+	}
+	fn, _ := info.Uses[ident].(*types.Func)
+	return fn
+}
+
+// getIdent returns the identifier of an Ident or the selected identifier of a SelectorExpr.
+func getIdent(expr ast.Expr) *ast.Ident {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e
+	case *ast.SelectorExpr:
+		return e.Sel
+	}
+	return nil
+}
+
+// getStringValue returns the value of a constant string expression.
+func getStringValue(info *types.Info, expr ast.Expr) string {
+	tv, ok := info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return ""
+	}
+	return constant.StringVal(tv.Value)
+}
@@ -0,0 +1,268 @@
+package parse
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse/struct_decl"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	fxPackagePath  = "go.uber.org/fx"
+	digPackagePath = "go.uber.org/dig"
+)
+
+// fxParser collects the constructors registered in a project.
+type fxParser struct {
+	*declIndex
+	visiting  map[*types.Var]bool
+	providers map[token.Pos]*diProvider
+}
+
+// parseFx builds the graph from the constructors registered through fx.Provide, fx.Supply, fx.Module and dig.Container.Provide.
+func parseFx(pkgs []*packages.Package, schema *AstSchema, t map[string]map[string]*struct_decl.Decl, index *declIndex) {
+	fp := &fxParser{
+		declIndex: index,
+		visiting:  make(map[*types.Var]bool),
+		providers: make(map[token.Pos]*diProvider),
+	}
+
+	for _, p := range pkgs {
+		for _, f := range p.Syntax {
+			ast.Inspect(f, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				return !fp.handleCall(call, p, "")
+			})
+		}
+	}
+
+	providers := make([]*diProvider, 0, len(fp.providers))
+	for _, provider := range fp.providers {
+		providers = append(providers, provider)
+	}
+	buildDIGraph(pkgs, schema, t, fp.funcDecls, providers, nil)
+}
+
+// handleCall registers the constructors of a fx or dig call, it returns false if the call is not one of them.
+func (fp *fxParser) handleCall(call *ast.CallExpr, p *packages.Package, module string) bool {
+	fn := getCalledFunc(p.TypesInfo, call)
+	switch {
+	case isFxFunc(fn, "Module"):
+		if len(call.Args) == 0 {
+			return true
+		}
+		name := getStringValue(p.TypesInfo, call.Args[0])
+		for _, arg := range call.Args[1:] {
+			fp.handleOption(arg, p, name)
+		}
+	case isFxFunc(fn, "Options"):
+		for _, arg := range call.Args {
+			fp.handleOption(arg, p, module)
+		}
+	case isFxFunc(fn, "Provide"):
+		for _, arg := range call.Args {
+			fp.addProvider(arg, p, module, nil)
+		}
+	case isFxFunc(fn, "Supply"):
+		for _, arg := range call.Args {
+			fp.addSupply(arg, p, module)
+		}
+	case isDigProvide(fn):
+		if len(call.Args) == 0 {
+			return true
+		}
+		fp.addProvider(call.Args[0], p, module, call.Args[1:])
+	default:
+		return false
+	}
+	return true
+}
+
+// handleOption follows an option given to fx.Module or fx.Options, including package level variables.
+func (fp *fxParser) handleOption(expr ast.Expr, p *packages.Package, module string) {
+	switch e := expr.(type) {
+	case *ast.CallExpr:
+		fp.handleCall(e, p, module)
+	case *ast.Ident, *ast.SelectorExpr:
+		v, ok := p.TypesInfo.Uses[getIdent(e)].(*types.Var)
+		if !ok || fp.visiting[v] {
+			return
+		}
+		src, ok := fp.varValues[v]
+		if !ok {
+			return
+		}
+		fp.visiting[v] = true
+		fp.handleOption(src.Value, src.P, module)
+		delete(fp.visiting, v)
+	}
+}
+
+// addProvider registers a constructor, unwrapping fx.Annotate and applying the dig provide options.
+func (fp *fxParser) addProvider(expr ast.Expr, p *packages.Package, module string, digOpts []ast.Expr) {
+	var annotations []ast.Expr
+	if call, ok := expr.(*ast.CallExpr); ok && isFxFunc(getCalledFunc(p.TypesInfo, call), "Annotate") && len(call.Args) > 0 {
+		expr, annotations = call.Args[0], call.Args[1:]
+	}
+	if existing, ok := fp.providers[expr.Pos()]; ok { // already registered through another path, keep the module if it was missing.
+		if existing.Module == "" {
+			existing.Module = module
+		}
+		return
+	}
+	sig, ok := p.TypesInfo.TypeOf(expr).(*types.Signature)
+	if !ok {
+		return
+	}
+
+	provider := &diProvider{
+		Pos:     expr.Pos(),
+		Module:  module,
+		Inputs:  getFxValues(sig.Params()),
+		Outputs: getFxValues(sig.Results()),
+	}
+	provider.Fn, provider.FnP = fp.getFuncDecl(expr, p)
+	for _, annotation := range annotations {
+		fp.applyAnnotation(provider, annotation, p)
+	}
+	for _, opt := range digOpts {
+		fp.applyAnnotation(provider, opt, p)
+	}
+	fp.providers[provider.Pos] = provider
+}
+
+// addSupply registers a value given to fx.Supply.
+func (fp *fxParser) addSupply(expr ast.Expr, p *packages.Package, module string) {
+	if _, ok := fp.providers[expr.Pos()]; ok {
+		return
+	}
+	fp.providers[expr.Pos()] = &diProvider{
+		Pos:     expr.Pos(),
+		Module:  module,
+		Outputs: []diValue{{Type: p.TypesInfo.TypeOf(expr), Index: 0}},
+	}
+}
+
+// applyAnnotation applies fx.As, fx.ParamTags, fx.ResultTags, dig.As, dig.Name and dig.Group to a constructor.
+func (fp *fxParser) applyAnnotation(provider *diProvider, annotation ast.Expr, p *packages.Package) {
+	call, ok := annotation.(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	fn := getCalledFunc(p.TypesInfo, call)
+	switch {
+	case isFxFunc(fn, "As"), isDigFunc(fn, "As"):
+		for _, arg := range call.Args {
+			if argCall, ok := arg.(*ast.CallExpr); ok && isFxFunc(getCalledFunc(p.TypesInfo, argCall), "Self") {
+				provider.Self = true
+				continue
+			}
+			t := p.TypesInfo.TypeOf(arg)
+			if ptr, ok := t.(*types.Pointer); ok { // interfaces are given as new(Interface).
+				t = ptr.Elem()
+			}
+			provider.As = append(provider.As, t)
+		}
+	case isFxFunc(fn, "ParamTags"):
+		applyTags(provider.Inputs, call.Args, p.TypesInfo)
+	case isFxFunc(fn, "ResultTags"):
+		applyTags(provider.Outputs, call.Args, p.TypesInfo)
+	case isDigFunc(fn, "Name") && len(call.Args) == 1:
+		for i := range provider.Outputs {
+			provider.Outputs[i].Name = getStringValue(p.TypesInfo, call.Args[0])
+		}
+	case isDigFunc(fn, "Group") && len(call.Args) == 1:
+		for i := range provider.Outputs {
+			provider.Outputs[i].Group = getStringValue(p.TypesInfo, call.Args[0])
+		}
+	}
+}
+
+// applyTags sets the name and group of positional values from struct tags.
+func applyTags(values []diValue, tags []ast.Expr, info *types.Info) {
+	for i, tag := range tags {
+		for j := range values {
+			if values[j].Index != i {
+				continue
+			}
+			structTag := reflect.StructTag(getStringValue(info, tag))
+			values[j].Name = structTag.Get("name")
+			values[j].Group = getGroupTag(structTag)
+		}
+	}
+}
+
+// getFxValues returns the values of a constructor signature, expanding the fx.In and fx.Out parameter objects.
+func getFxValues(tuple *types.Tuple) []diValue {
+	var values []diValue
+	for i := 0; i < tuple.Len(); i++ {
+		v := tuple.At(i)
+		if s := getParameterObject(v.Type()); s != nil {
+			for j := 0; j < s.NumFields(); j++ {
+				f := s.Field(j)
+				if f.Embedded() || !f.Exported() {
+					continue
+				}
+				tag := reflect.StructTag(s.Tag(j))
+				values = append(values, diValue{
+					Type:    f.Type(),
+					Name:    tag.Get("name"),
+					Group:   getGroupTag(tag),
+					Index:   -1,
+					VarName: v.Name(),
+					Field:   f.Name(),
+				})
+			}
+			continue
+		}
+		if isErrorType(v.Type()) {
+			continue
+		}
+		values = append(values, diValue{Type: v.Type(), Index: i, VarName: v.Name()})
+	}
+	return values
+}
+
+// getParameterObject returns the struct of a fx.In or fx.Out parameter object, nil if t is not one.
+func getParameterObject(t types.Type) *types.Struct {
+	s, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	for i := 0; i < s.NumFields(); i++ {
+		f := s.Field(i)
+		if !f.Embedded() {
+			continue
+		}
+		switch types.TypeString(f.Type(), nil) { // fx.In is an alias of dig.In, compare the names to support both.
+		case fxPackagePath + ".In", fxPackagePath + ".Out", digPackagePath + ".In", digPackagePath + ".Out":
+			return s
+		}
+	}
+	return nil
+}
+
+// isFxInternalType reports whether the type is provided by fx or dig themselves, such as fx.Lifecycle.
+func isFxInternalType(named *types.Named) bool {
+	path := named.Obj().Pkg().Path()
+	return path == fxPackagePath || path == digPackagePath
+}
+
+func isFxFunc(fn *types.Func, name string) bool {
+	return fn != nil && fn.Pkg() != nil && fn.Pkg().Path() == fxPackagePath && fn.Name() == name
+}
+
+func isDigFunc(fn *types.Func, name string) bool {
+	return fn != nil && fn.Pkg() != nil && fn.Pkg().Path() == digPackagePath && fn.Name() == name && fn.Type().(*types.Signature).Recv() == nil
+}
+
+// isDigProvide reports whether fn is the dig.Container.Provide method.
+func isDigProvide(fn *types.Func) bool {
+	return fn != nil && fn.Pkg() != nil && fn.Pkg().Path() == digPackagePath && fn.Name() == "Provide" && fn.Type().(*types.Signature).Recv() != nil
+}
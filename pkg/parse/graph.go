@@ -1,7 +1,9 @@
 package parse
 
 import (
+	"go/token"
 	"go/types"
+	"path/filepath"
 	"sort"
 
 	"github.com/emilien-puget/go-dependency-graph/pkg/parse/struct_decl"
@@ -20,6 +22,27 @@ type Node struct {
 	ActualNamedType *types.Named
 	P               *packages.Package
 	FilePath        string
+	Module          string            // The dependency injection module the node is provided in, if any
+	Cleanup         bool              // The provider also returns a cleanup function
+	ProviderRule    string            // The rule the provider of the node was matched with, if any
+	Instances       int               // The number of instances created in the composition roots
+	Tags            []string          // The tags of the struct, declared with the //depgraph:tag directive
+	Provider        token.Position    // The position of the provider of the node, if known
+	Sequences       map[string][]Step // The calls made by each method, in order, see searchSequence
+}
+
+// GetRelativeFilePath returns the source file of the node relative to dir, if set, with forward slashes.
+func (n *Node) GetRelativeFilePath(dir string) string {
+	if n.FilePath == "" {
+		return ""
+	}
+	file := n.FilePath
+	if dir != "" {
+		if rel, err := filepath.Rel(dir, file); err == nil {
+			file = rel
+		}
+	}
+	return filepath.ToSlash(file)
 }
 
 func (n *Node) MergeAdditionalFields(other *Node) {
@@ -35,6 +58,21 @@ func (n *Node) MergeAdditionalFields(other *Node) {
 	if n.FilePath == "" && other.FilePath != "" {
 		n.FilePath = other.FilePath
 	}
+	if n.Module == "" && other.Module != "" {
+		n.Module = other.Module
+	}
+	if other.Cleanup {
+		n.Cleanup = true
+	}
+	if n.Doc == "" && other.Doc != "" {
+		n.Doc = other.Doc
+	}
+	if n.ProviderRule == "" && other.ProviderRule != "" {
+		n.ProviderRule = other.ProviderRule
+	}
+	if !n.Provider.IsValid() && other.Provider.IsValid() {
+		n.Provider = other.Provider
+	}
 }
 
 // Graph represents the dependency graph.
@@ -46,8 +84,20 @@ type Graph struct {
 }
 
 type Adj struct {
-	Node *Node
-	Func []string
+	Node     *Node
+	Func     []string
+	Via      string   // The fully qualified name of the interface the dependency is injected through, if any
+	Optional bool     // The dependency is injected through a functional option
+	Fields   []string // The fields of the consumer the dependency is assigned to, when known
+	Param    string   // The provider parameter the dependency is injected through, when known
+	Calls    []Call   // The methods of the dependency called by the methods of the consumer
+}
+
+// Call is a method of a dependency called by a method of the consumer.
+type Call struct {
+	Caller string
+	Callee string
+	Field  string // The field of the consumer the method is called through
 }
 
 func NewGraph() *Graph {
@@ -154,10 +204,39 @@ func (g *Graph) GetLeafNodes() []*Node {
 	return leafNodes
 }
 
+// GetNodesByModule returns the nodes provided in a dependency injection module, indexed by module.
+func (g *Graph) GetNodesByModule() map[string][]*Node {
+	nodesByModule := make(map[string][]*Node)
+	for _, node := range g.Nodes {
+		if node.Module != "" {
+			nodesByModule[node.Module] = append(nodesByModule[node.Module], node)
+		}
+	}
+	return nodesByModule
+}
+
+// GetPackageNodesOutsideModule returns the nodes of the package not provided in a dependency injection module, those
+// are drawn in their module rather than in their package.
+func (g *Graph) GetPackageNodesOutsideModule(packageName string) []*Node {
+	nodes := g.NodesByPackage[packageName]
+	outside := make([]*Node, 0, len(nodes))
+	for _, node := range nodes {
+		if node.Module == "" {
+			outside = append(outside, node)
+		}
+	}
+	return outside
+}
+
 // GetNodesSortedByName returns all nodes sorted by node name.
 func (g *Graph) GetNodesSortedByName() []*Node {
-	sortedNodes := make([]*Node, len(g.Nodes))
-	copy(sortedNodes, g.Nodes)
+	return SortNodesByName(g.Nodes)
+}
+
+// SortNodesByName returns a copy of the nodes sorted by node name.
+func SortNodesByName(nodes []*Node) []*Node {
+	sortedNodes := make([]*Node, len(nodes))
+	copy(sortedNodes, nodes)
 	sort.SliceStable(sortedNodes, func(i, j int) bool {
 		return sortedNodes[i].Name < sortedNodes[j].Name
 	})
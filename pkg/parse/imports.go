@@ -23,8 +23,22 @@ func parseImports(f *ast.File, modulePath string, imp map[string]*packages.Packa
 		}
 		imports[importName] = importDecl{
 			Path:     p,
-			External: !strings.Contains(p, modulePath),
+			External: isExternal(p, modulePath),
 		}
 	}
 	return imports
 }
+
+// isExternal reports whether the package is outside the module, the module path must be a prefix of whole path elements.
+func isExternal(pkgPath, modulePath string) bool {
+	return pkgPath != modulePath && !strings.HasPrefix(pkgPath, modulePath+"/")
+}
+
+// GetPackageLabel returns the path of the package relative to the module, the whole path for the package of the module
+// and for the packages outside of it.
+func GetPackageLabel(packageName, modulePath string) string {
+	if packageName == modulePath || isExternal(packageName, modulePath) {
+		return packageName
+	}
+	return strings.TrimPrefix(packageName, modulePath+"/")
+}
@@ -0,0 +1,22 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsExternal(t *testing.T) {
+	assert.False(t, isExternal("example.com/foo", "example.com/foo"))
+	assert.False(t, isExternal("example.com/foo/bar", "example.com/foo"))
+	assert.True(t, isExternal("example.com/foobar", "example.com/foo"))
+	assert.True(t, isExternal("github.com/org/example.com/foo", "example.com/foo"))
+	assert.True(t, isExternal("net/http", "example.com/foo"))
+}
+
+func TestGetPackageLabel(t *testing.T) {
+	assert.Equal(t, "example.com/mod", GetPackageLabel("example.com/mod", "example.com/mod"))
+	assert.Equal(t, "foo/bar", GetPackageLabel("example.com/mod/foo/bar", "example.com/mod"))
+	assert.Equal(t, "example.com/modfoo", GetPackageLabel("example.com/modfoo", "example.com/mod"))
+	assert.Equal(t, "net/http", GetPackageLabel("net/http", "example.com/mod"))
+}
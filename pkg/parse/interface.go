@@ -0,0 +1,77 @@
+package parse
+
+import (
+	"go/types"
+)
+
+// interfaceDep represents a dependency injected through an interface, resolved once every provider is known.
+type interfaceDep struct {
+	From *Node
+	Dep  dep
+}
+
+// resolveInterfaceDependencies adds an edge from each consumer to every provided struct implementing the injected interface.
+// When no implementation is found, the edge points to the interface itself so the dependency is not lost.
+func resolveInterfaceDependencies(graph *Graph, interfaceDeps []interfaceDep) {
+	for _, iDep := range interfaceDeps {
+		iface := iDep.Dep.Interface.Underlying().(*types.Interface)
+		via := iDep.Dep.PackageName + "." + iDep.Dep.DependencyName
+
+		funcs := iDep.Dep.Funcs
+		if len(funcs) == 0 {
+			funcs = getInterfaceMethods(iface)
+		}
+
+		implementations := findImplementations(graph, iface, iDep.From)
+		if len(implementations) == 0 {
+			adjNode := &Node{
+				Name:        via,
+				PackageName: iDep.Dep.PackageName,
+				StructName:  iDep.Dep.DependencyName,
+				External:    iDep.Dep.External,
+			}
+			graph.AddNode(adjNode)
+			graph.AddEdge(iDep.From, &Adj{
+				Node:     adjNode,
+				Func:     funcs,
+				Optional: iDep.Dep.Optional,
+				Fields:   iDep.Dep.Fields,
+				Param:    iDep.Dep.VarName,
+			})
+			continue
+		}
+
+		for _, implementation := range implementations {
+			graph.AddEdge(iDep.From, &Adj{
+				Node:     implementation,
+				Func:     append([]string(nil), funcs...),
+				Via:      via,
+				Optional: iDep.Dep.Optional,
+				Fields:   append([]string(nil), iDep.Dep.Fields...),
+				Param:    iDep.Dep.VarName,
+			})
+		}
+	}
+}
+
+// findImplementations returns the provided nodes whose struct, or a pointer to it, implements iface.
+func findImplementations(graph *Graph, iface *types.Interface, consumer *Node) []*Node {
+	var implementations []*Node
+	for _, node := range graph.GetNodesSortedByName() {
+		if node.Name == consumer.Name || node.ActualNamedType == nil {
+			continue
+		}
+		if types.Implements(node.ActualNamedType, iface) || types.Implements(types.NewPointer(node.ActualNamedType), iface) {
+			implementations = append(implementations, node)
+		}
+	}
+	return implementations
+}
+
+func getInterfaceMethods(iface *types.Interface) []string {
+	methods := make([]string, 0, iface.NumMethods())
+	for i := 0; i < iface.NumMethods(); i++ {
+		methods = append(methods, iface.Method(i).Name())
+	}
+	return methods
+}
@@ -0,0 +1,91 @@
+package parse
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse/struct_decl"
+	"golang.org/x/tools/go/packages"
+)
+
+const optionPrefix = "With"
+
+// optionFunc represents a functional option, a With* function returning a func configuring a struct.
+type optionFunc struct {
+	Decl    *ast.FuncDecl
+	P       *packages.Package
+	Imports map[string]importDecl
+}
+
+// searchOptionFuncs returns the functional options of the packages, indexed by the type they return.
+func searchOptionFuncs(pkgs []*packages.Package, modulePath string) map[string][]optionFunc {
+	options := make(map[string][]optionFunc)
+	for _, p := range pkgs {
+		for _, f := range p.Syntax {
+			var imports map[string]importDecl
+			for _, decl := range f.Decls {
+				d, ok := decl.(*ast.FuncDecl)
+				if !ok || d.Recv != nil || !strings.HasPrefix(d.Name.Name, optionPrefix) {
+					continue
+				}
+				if d.Type.Results == nil || len(d.Type.Results.List) != 1 {
+					continue
+				}
+				optionType := p.TypesInfo.TypeOf(d.Type.Results.List[0].Type)
+				if _, ok := optionType.Underlying().(*types.Signature); !ok {
+					continue
+				}
+				if imports == nil {
+					imports = parseImports(f, modulePath, p.Imports)
+				}
+				key := types.TypeString(optionType, nil)
+				options[key] = append(options[key], optionFunc{Decl: d, P: p, Imports: imports})
+			}
+		}
+	}
+	return options
+}
+
+// searchOptionDependencies returns the dependencies injected through the functional options the provider accepts.
+// Only the parameters of an option assigned to a field of the provided struct are kept, they are optional dependencies.
+func searchOptionDependencies(funcdecl *ast.FuncDecl, info *types.Info, modulePath string, options map[string][]optionFunc, s *struct_decl.Decl, funcDecls map[*types.Func]funcSource) (deps map[string][]dep) {
+	deps = map[string][]dep{}
+	params := funcdecl.Type.Params.List
+	if len(params) == 0 {
+		return deps
+	}
+	ellipsis, ok := params[len(params)-1].Type.(*ast.Ellipsis)
+	if !ok {
+		return deps
+	}
+	optionType := info.TypeOf(ellipsis.Elt)
+	if optionType == nil || !isOptionOf(optionType, s.ActualNamedType) {
+		return deps
+	}
+
+	for _, option := range options[types.TypeString(optionType, nil)] {
+		optionDeps := searchDependencies(option.Decl, option.P.ID, modulePath, option.Imports, option.P.TypesInfo)
+		assigned := searchDependenciesAssignment(option.Decl, optionDeps, s, option.P.TypesInfo, funcDecls)
+		for varName := range optionDeps {
+			if !assigned[varName] {
+				continue
+			}
+			key := option.Decl.Name.Name + "." + varName
+			for _, d := range optionDeps[varName] {
+				d.Optional = true
+				deps[key] = append(deps[key], d)
+			}
+		}
+	}
+	return deps
+}
+
+// isOptionOf reports whether optionType is a func whose first parameter is the struct, or a pointer to it.
+func isOptionOf(optionType types.Type, named *types.Named) bool {
+	sig, ok := optionType.Underlying().(*types.Signature)
+	if !ok || sig.Params().Len() == 0 || named == nil {
+		return false
+	}
+	return types.Identical(derefType(sig.Params().At(0).Type()), named)
+}
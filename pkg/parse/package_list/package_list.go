@@ -14,12 +14,15 @@ const (
 	goFileExtension = ".go"
 )
 
-func GetPackagesToParse(pathDir string, skipDirs []string) ([]*packages.Package, error) {
+func GetPackagesToParse(pathDir string, skipDirs []string, buildTags ...string) ([]*packages.Package, error) {
 	cfg := &packages.Config{
 		Dir:   pathDir,
 		Mode:  packages.NeedName | packages.NeedImports | packages.NeedDeps | packages.NeedExportFile | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
 		Tests: false,
 	}
+	if len(buildTags) > 0 {
+		cfg.BuildFlags = []string{"-tags=" + strings.Join(buildTags, ",")}
+	}
 	dirs, err := findGoSourceDirectories(pathDir, skipDirs)
 	if err != nil {
 		return nil, fmt.Errorf("findGoSourceDirectories: %w", err)
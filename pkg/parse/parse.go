@@ -1,8 +1,10 @@
 package parse
 
 import (
+	"errors"
 	"fmt"
 	"go/ast"
+	"go/types"
 	"path/filepath"
 
 	"github.com/emilien-puget/go-dependency-graph/pkg/parse/package_list"
@@ -13,11 +15,42 @@ import (
 // AstSchema is a simpler presentation of the ast of a project.
 type AstSchema struct {
 	ModulePath string
+	Dir        string // The absolute path of the parsed project, empty when the graph is not parsed from the sources
 	Graph      *Graph
+	Binaries   []Binary // The main packages of the module, empty when the graph is not parsed from the sources
 }
 
+// Mode selects how the providers of a project are discovered.
+type Mode string
+
+const (
+	// ModeProvider discovers the functions named New* returning a struct declared in the same package.
+	ModeProvider Mode = "provider"
+	// ModeFx discovers the constructors registered through fx.Provide, fx.Module and dig.Container.Provide.
+	ModeFx Mode = "fx"
+	// ModeWire discovers the providers declared in wire.NewSet and wire.Build, the injectors are loaded with the wireinject build tag.
+	ModeWire Mode = "wire"
+	// ModeComposition follows the values created in the functions of the main packages and passed to each other.
+	ModeComposition Mode = "composition"
+)
+
+const wireInjectBuildTag = "wireinject"
+
+// Config holds the parsing options.
+type Config struct {
+	Mode     Mode
+	Provider ProviderConfig // used by ModeProvider
+}
+
+var ErrUnknownMode = errors.New("unknown parse mode")
+
 // Parse parses the project located under pathDir and returns an AstSchema.
 func Parse(pathDir string, skipDirs []string) (AstSchema, error) {
+	return ParseWithConfig(pathDir, skipDirs, Config{})
+}
+
+// ParseWithConfig parses the project located under pathDir using the given configuration and returns an AstSchema.
+func ParseWithConfig(pathDir string, skipDirs []string, c Config) (AstSchema, error) {
 	pathDir, err := filepath.Abs(pathDir)
 	if err != nil {
 		return AstSchema{}, fmt.Errorf("filepath.Abs:%w", err)
@@ -28,10 +61,15 @@ func Parse(pathDir string, skipDirs []string) (AstSchema, error) {
 	}
 	as := AstSchema{
 		ModulePath: modulePath,
+		Dir:        pathDir,
 		Graph:      NewGraph(),
 	}
 
-	pkgs, err := package_list.GetPackagesToParse(pathDir, skipDirs)
+	var buildTags []string
+	if c.Mode == ModeWire {
+		buildTags = append(buildTags, wireInjectBuildTag)
+	}
+	pkgs, err := package_list.GetPackagesToParse(pathDir, skipDirs, buildTags...)
 	if err != nil {
 		return AstSchema{}, fmt.Errorf("package_list.GetPackagesToParse:%w", err)
 	}
@@ -41,54 +79,87 @@ func Parse(pathDir string, skipDirs []string) (AstSchema, error) {
 		return AstSchema{}, fmt.Errorf("struct_decl.Extract:%w", err)
 	}
 
-	parsePackages(pkgs, &as, types)
+	index := newDeclIndex(pkgs)
+	switch c.Mode {
+	case "", ModeProvider:
+		matcher, err := newProviderMatcher(c.Provider)
+		if err != nil {
+			return AstSchema{}, fmt.Errorf("newProviderMatcher:%w", err)
+		}
+		interfaceDeps := parsePackages(pkgs, &as, types, matcher, index)
+		resolveInterfaceDependencies(as.Graph, interfaceDeps)
+	case ModeFx:
+		parseFx(pkgs, &as, types, index)
+	case ModeWire:
+		parseWire(pkgs, &as, types, index)
+	case ModeComposition:
+		parseComposition(pkgs, &as, types, index)
+	default:
+		return AstSchema{}, fmt.Errorf("%w: %s", ErrUnknownMode, c.Mode)
+	}
+	searchCalls(as.Graph, index)
+	searchTags(pkgs, as.Graph)
+	as.Binaries = searchBinaries(pkgs, modulePath)
 
 	return as, nil
 }
 
-func parsePackages(pkgs []*packages.Package, schema *AstSchema, types map[string]map[string]*struct_decl.Decl) {
+func parsePackages(pkgs []*packages.Package, schema *AstSchema, types map[string]map[string]*struct_decl.Decl, matcher providerMatcher, index *declIndex) (interfaceDeps []interfaceDep) {
+	docs := getStructDocs(pkgs)
+	options := searchOptionFuncs(pkgs, schema.ModulePath)
+	packagesByPath := make(map[string]*packages.Package)
+	for _, p := range pkgs {
+		packagesByPath[p.PkgPath] = p
+	}
 	for i := range pkgs {
-		parsePackage(pkgs[i], schema, types)
+		interfaceDeps = append(interfaceDeps, parsePackage(pkgs[i], schema, types, docs, options, index.funcDecls, matcher, packagesByPath)...)
 	}
+	return interfaceDeps
 }
 
-func parsePackage(p *packages.Package, schema *AstSchema, types map[string]map[string]*struct_decl.Decl) {
+func parsePackage(p *packages.Package, schema *AstSchema, types map[string]map[string]*struct_decl.Decl, docs map[string]string, options map[string][]optionFunc, funcDecls map[*types.Func]funcSource, matcher providerMatcher, packagesByPath map[string]*packages.Package) (interfaceDeps []interfaceDep) {
 	for _, f := range p.Syntax {
-		parseFile(f, p, schema.ModulePath, types, schema.Graph)
+		interfaceDeps = append(interfaceDeps, parseFile(f, p, schema.ModulePath, types, docs, options, funcDecls, matcher, packagesByPath, schema.Graph)...)
 	}
+	return interfaceDeps
 }
 
-func parseFile(f *ast.File, p *packages.Package, modulePath string, types map[string]map[string]*struct_decl.Decl, graph *Graph) {
+func parseFile(f *ast.File, p *packages.Package, modulePath string, types map[string]map[string]*struct_decl.Decl, docs map[string]string, options map[string][]optionFunc, funcDecls map[*types.Func]funcSource, matcher providerMatcher, packagesByPath map[string]*packages.Package, graph *Graph) (interfaceDeps []interfaceDep) {
 	packageName := p.ID
 
-	structDoc := struct_decl.GetStructDoc(f, packageName)
-
 	imports := parseImports(f, modulePath, p.Imports)
 	for _, decl := range f.Decls {
 		d, ok := decl.(*ast.FuncDecl)
 		if !ok {
 			continue
 		}
-		name, deps, sDecl := searchProvider(d, packageName, imports, p.TypesInfo, types)
-		if name == "" {
+		provided, deps, sDecl := searchProvider(d, packageName, modulePath, imports, p.TypesInfo, types, funcDecls, matcher)
+		if provided.Name == "" {
 			continue
 		}
+		for varName, optionDeps := range searchOptionDependencies(d, p.TypesInfo, modulePath, options, sDecl, funcDecls) {
+			deps[varName] = append(deps[varName], optionDeps...)
+		}
 		newNode := &Node{
-			Name:            packageName + "." + name,
-			PackageName:     packageName,
-			StructName:      name,
+			Name:            provided.PackageName + "." + provided.Name,
+			PackageName:     provided.PackageName,
+			StructName:      provided.Name,
 			Methods:         sDecl.Methods,
+			Doc:             docs[provided.PackageName+"."+provided.Name],
 			ActualNamedType: sDecl.ActualNamedType,
-			P:               p,
+			P:               packagesByPath[provided.PackageName], // the provider may be declared in another package
 			FilePath:        sDecl.FilePath,
-		}
-		if len(structDoc[packageName+"."+name]) > 3 {
-			newNode.Doc = structDoc[packageName+"."+name][3:]
+			ProviderRule:    provided.Rule,
+			Provider:        p.Fset.Position(d.Pos()),
 		}
 		graph.AddNode(newNode)
 
 		for s := range deps {
 			for i2 := range deps[s] {
+				if deps[s][i2].Interface != nil {
+					interfaceDeps = append(interfaceDeps, interfaceDep{From: newNode, Dep: deps[s][i2]})
+					continue
+				}
 				adjNode := &Node{
 					Name:        deps[s][i2].PackageName + "." + deps[s][i2].DependencyName,
 					PackageName: deps[s][i2].PackageName,
@@ -97,10 +168,29 @@ func parseFile(f *ast.File, p *packages.Package, modulePath string, types map[st
 				}
 				graph.AddNode(adjNode)
 				graph.AddEdge(newNode, &Adj{
-					Node: adjNode,
-					Func: deps[s][i2].Funcs,
+					Node:     adjNode,
+					Func:     deps[s][i2].Funcs,
+					Optional: deps[s][i2].Optional,
+					Fields:   deps[s][i2].Fields,
+					Param:    deps[s][i2].VarName,
 				})
 			}
 		}
 	}
+	return interfaceDeps
+}
+
+// getStructDocs returns the doc of every struct of the packages, indexed by their fully qualified name.
+func getStructDocs(pkgs []*packages.Package) map[string]string {
+	docs := make(map[string]string)
+	for _, p := range pkgs {
+		for _, f := range p.Syntax {
+			for name, doc := range struct_decl.GetStructDoc(f, p.ID) {
+				if len(doc) > 3 {
+					docs[name] = doc[3:]
+				}
+			}
+		}
+	}
+	return docs
 }
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"go/token"
 	"go/types"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/emilien-puget/go-dependency-graph/pkg/parse/struct_decl"
@@ -431,9 +433,629 @@ func TestParse_package_name_mismatch(t *testing.T) {
 	assertAdj(t, graph.GetAdjacenciesSortedByName(graph.GetNodeByName("package_name_mismatch.A")), parse.Graph.GetAdjacenciesSortedByName(parse.Graph.GetNodeByName("package_name_mismatch.A")))
 }
 
+func TestParse_interface_param(t *testing.T) {
+	t.Parallel()
+	parse, err := Parse("testdata/interface_param", nil)
+	assert.NoError(t, err)
+
+	graph := NewGraph()
+	a := &Node{
+		Name:        "testdata/interface_param.A",
+		PackageName: "testdata/interface_param",
+		StructName:  "A",
+	}
+	graph.AddNode(a)
+	b := &Node{
+		Name:        "testdata/interface_param.B",
+		PackageName: "testdata/interface_param",
+		StructName:  "B",
+	}
+	graph.AddNode(b)
+	memoryRepository := &Node{
+		Name:        "testdata/interface_param.MemoryRepository",
+		PackageName: "testdata/interface_param",
+		StructName:  "MemoryRepository",
+		Doc:         "MemoryRepository stores users in memory.",
+	}
+	graph.AddNode(memoryRepository)
+	notifier := &Node{
+		Name:        "testdata/interface_param.Notifier",
+		PackageName: "testdata/interface_param",
+		StructName:  "Notifier",
+	}
+	graph.AddNode(notifier)
+	sqlRepository := &Node{
+		Name:        "testdata/interface_param.SQLRepository",
+		PackageName: "testdata/interface_param",
+		StructName:  "SQLRepository",
+		Doc:         "SQLRepository stores users in a database.",
+	}
+	graph.AddNode(sqlRepository)
+	graph.AddEdge(a, &Adj{Node: memoryRepository, Func: []string{"Find", "Save"}, Via: "testdata/interface_param.UserRepository"})
+	graph.AddEdge(a, &Adj{Node: sqlRepository, Func: []string{"Find", "Save"}, Via: "testdata/interface_param.UserRepository"})
+	graph.AddEdge(b, &Adj{Node: notifier, Func: []string{"Notify"}})
+
+	assertNodes(t, graph.GetNodesSortedByName(), parse.Graph.GetNodesSortedByName())
+	assertAdj(t, graph.GetAdjacenciesSortedByName(graph.GetNodeByName("testdata/interface_param.A")), parse.Graph.GetAdjacenciesSortedByName(parse.Graph.GetNodeByName("testdata/interface_param.A")))
+	assertAdj(t, graph.GetAdjacenciesSortedByName(graph.GetNodeByName("testdata/interface_param.B")), parse.Graph.GetAdjacenciesSortedByName(parse.Graph.GetNodeByName("testdata/interface_param.B")))
+}
+
+func TestParse_fx_sample(t *testing.T) {
+	t.Parallel()
+	parse, err := ParseWithConfig("testdata/fx_sample", nil, Config{Mode: ModeFx})
+	assert.NoError(t, err)
+
+	graph := NewGraph()
+	cache := &Node{
+		Name:        "testdata/fx_sample.Cache",
+		PackageName: "testdata/fx_sample",
+		StructName:  "Cache",
+		Doc:         "Cache keeps recently served responses.",
+	}
+	graph.AddNode(cache)
+	server := &Node{
+		Name:        "testdata/fx_sample.Server",
+		PackageName: "testdata/fx_sample",
+		StructName:  "Server",
+		Doc:         "Server serves every registered handler.",
+	}
+	graph.AddNode(server)
+	logger := &Node{
+		Name:        "testdata/fx_sample.StdLogger",
+		PackageName: "testdata/fx_sample",
+		StructName:  "StdLogger",
+		Doc:         "StdLogger is a Logger writing to stdout.",
+	}
+	graph.AddNode(logger)
+	createHandler := &Node{
+		Name:        "testdata/fx_sample/user.CreateHandler",
+		PackageName: "testdata/fx_sample/user",
+		StructName:  "CreateHandler",
+		Doc:         "CreateHandler creates users.",
+		Module:      "user",
+	}
+	graph.AddNode(createHandler)
+	listHandler := &Node{
+		Name:        "testdata/fx_sample/user.ListHandler",
+		PackageName: "testdata/fx_sample/user",
+		StructName:  "ListHandler",
+		Doc:         "ListHandler lists users.",
+		Module:      "user",
+	}
+	graph.AddNode(listHandler)
+	repository := &Node{
+		Name:        "testdata/fx_sample/user.Repository",
+		PackageName: "testdata/fx_sample/user",
+		StructName:  "Repository",
+		Doc:         "Repository stores users.",
+		Module:      "user",
+	}
+	graph.AddNode(repository)
+	graph.AddEdge(server, &Adj{Node: cache})
+	graph.AddEdge(server, &Adj{Node: logger, Func: []string{"Log"}, Via: "testdata/fx_sample.Logger"})
+	graph.AddEdge(server, &Adj{Node: createHandler, Func: []string{"Pattern"}, Via: "testdata/fx_sample/user.Handler"})
+	graph.AddEdge(server, &Adj{Node: listHandler, Func: []string{"Pattern"}, Via: "testdata/fx_sample/user.Handler"})
+	graph.AddEdge(createHandler, &Adj{Node: repository})
+	graph.AddEdge(listHandler, &Adj{Node: repository, Func: []string{"Find"}})
+
+	assertNodes(t, graph.GetNodesSortedByName(), parse.Graph.GetNodesSortedByName())
+	for _, node := range graph.GetNodesSortedByName() {
+		assertAdj(t, graph.GetAdjacenciesSortedByName(node), parse.Graph.GetAdjacenciesSortedByName(parse.Graph.GetNodeByName(node.Name)))
+	}
+	require.NotNil(t, parse.Graph.GetNodeByName("testdata/fx_sample.Server").ActualNamedType)
+}
+
+func TestParse_wire_sets(t *testing.T) {
+	t.Parallel()
+	parse, err := ParseWithConfig("testdata/wire_sets", nil, Config{Mode: ModeWire})
+	assert.NoError(t, err)
+
+	graph := NewGraph()
+	clock := &Node{
+		Name:        "testdata/wire_sets.Clock",
+		PackageName: "testdata/wire_sets",
+		StructName:  "Clock",
+		Doc:         "Clock tells the time.",
+		Module:      "ServiceSet",
+	}
+	graph.AddNode(clock)
+	config := &Node{
+		Name:        "testdata/wire_sets.Config",
+		PackageName: "testdata/wire_sets",
+		StructName:  "Config",
+	}
+	graph.AddNode(config)
+	handler := &Node{
+		Name:        "testdata/wire_sets.Handler",
+		PackageName: "testdata/wire_sets",
+		StructName:  "Handler",
+		Doc:         "Handler serves the service.",
+		Module:      "InitializeHandler",
+	}
+	graph.AddNode(handler)
+	logger := &Node{
+		Name:        "testdata/wire_sets.Logger",
+		PackageName: "testdata/wire_sets",
+		StructName:  "Logger",
+		Doc:         "Logger writes log lines.",
+		Module:      "ServiceSet",
+	}
+	graph.AddNode(logger)
+	redisStore := &Node{
+		Name:        "testdata/wire_sets.RedisStore",
+		PackageName: "testdata/wire_sets",
+		StructName:  "RedisStore",
+		Doc:         "RedisStore is a Store backed by redis.",
+		Module:      "StoreSet",
+		Cleanup:     true,
+	}
+	graph.AddNode(redisStore)
+	service := &Node{
+		Name:        "testdata/wire_sets.Service",
+		PackageName: "testdata/wire_sets",
+		StructName:  "Service",
+		Doc:         "Service reads from the store.",
+		Module:      "ServiceSet",
+	}
+	graph.AddNode(service)
+	graph.AddEdge(handler, &Adj{Node: service})
+	graph.AddEdge(logger, &Adj{Node: config})
+	graph.AddEdge(redisStore, &Adj{Node: config, Func: []string{"Addr"}})
+	graph.AddEdge(service, &Adj{Node: clock})
+	graph.AddEdge(service, &Adj{Node: logger})
+	graph.AddEdge(service, &Adj{Node: redisStore, Func: []string{"Get"}, Via: "testdata/wire_sets.Store"})
+
+	assertNodes(t, graph.GetNodesSortedByName(), parse.Graph.GetNodesSortedByName())
+	for _, node := range graph.GetNodesSortedByName() {
+		assertAdj(t, graph.GetAdjacenciesSortedByName(node), parse.Graph.GetAdjacenciesSortedByName(parse.Graph.GetNodeByName(node.Name)))
+	}
+}
+
+func TestParse_wire_sample_wire_mode(t *testing.T) {
+	t.Parallel()
+	parse, err := ParseWithConfig("testdata/wire_sample", nil, Config{Mode: ModeWire})
+	assert.NoError(t, err)
+
+	graph := NewGraph()
+	event := &Node{
+		Name:        "testdata/wire_sample.Event",
+		PackageName: "testdata/wire_sample",
+		StructName:  "Event",
+		Doc:         "Event is a gathering with greeters.",
+		Module:      "InitializeEvent",
+	}
+	graph.AddNode(event)
+	greeter := &Node{
+		Name:        "testdata/wire_sample.Greeter",
+		PackageName: "testdata/wire_sample",
+		StructName:  "Greeter",
+		Doc:         "Greeter is the type charged with greeting guests.",
+		Module:      "InitializeEvent",
+	}
+	graph.AddNode(greeter)
+	message := &Node{
+		Name:        "testdata/wire_sample.Message",
+		PackageName: "testdata/wire_sample",
+		StructName:  "Message",
+		Doc:         "Message is what greeters will use to greet guests.",
+		Module:      "InitializeEvent",
+	}
+	graph.AddNode(message)
+	graph.AddEdge(event, &Adj{Node: greeter})
+	graph.AddEdge(greeter, &Adj{Node: message})
+
+	assertNodes(t, graph.GetNodesSortedByName(), parse.Graph.GetNodesSortedByName())
+	for _, node := range graph.GetNodesSortedByName() {
+		assertAdj(t, graph.GetAdjacenciesSortedByName(node), parse.Graph.GetAdjacenciesSortedByName(parse.Graph.GetNodeByName(node.Name)))
+	}
+}
+
+func TestParse_provider_rules(t *testing.T) {
+	t.Parallel()
+	parse, err := ParseWithConfig("testdata/provider_rules", nil, Config{Provider: ProviderConfig{
+		NamePatterns: []string{"^New", "^Make", "^Build"},
+		Methods:      true,
+	}})
+	assert.NoError(t, err)
+
+	graph := NewGraph()
+	service := &Node{
+		Name:         "testdata/provider_rules/domain.Service",
+		PackageName:  "testdata/provider_rules/domain",
+		StructName:   "Service",
+		Doc:          "Service handles the items.",
+		ProviderRule: "name:^New",
+	}
+	graph.AddNode(service)
+	handler := &Node{
+		Name:         "testdata/provider_rules/factory.Handler",
+		PackageName:  "testdata/provider_rules/factory",
+		StructName:   "Handler",
+		Doc:          "Handler serves the items.",
+		ProviderRule: "method:^Build",
+	}
+	graph.AddNode(handler)
+	cache := &Node{
+		Name:         "testdata/provider_rules/storage.Cache",
+		PackageName:  "testdata/provider_rules/storage",
+		StructName:   "Cache",
+		Doc:          "Cache keeps the recently found items.",
+		ProviderRule: ProviderRuleDirective,
+	}
+	graph.AddNode(cache)
+	repository := &Node{
+		Name:         "testdata/provider_rules/storage.Repository",
+		PackageName:  "testdata/provider_rules/storage",
+		StructName:   "Repository",
+		Doc:          "Repository stores the items.",
+		ProviderRule: "name:^Make",
+	}
+	graph.AddNode(repository)
+	worker := &Node{
+		Name:         "testdata/provider_rules/factory.Worker",
+		PackageName:  "testdata/provider_rules/factory",
+		StructName:   "Worker",
+		Doc:          "Worker processes the items in the background.",
+		ProviderRule: "method:^New",
+	}
+	graph.AddNode(worker)
+	graph.AddEdge(service, &Adj{Node: repository, Func: []string{"Find"}})
+	graph.AddEdge(handler, &Adj{Node: service, Func: []string{"Handle"}})
+	graph.AddEdge(worker, &Adj{Node: service, Func: []string{"Handle"}})
+
+	assertNodes(t, graph.GetNodesSortedByName(), parse.Graph.GetNodesSortedByName())
+	for _, node := range graph.GetNodesSortedByName() {
+		require.Equal(t, node.ProviderRule, parse.Graph.GetNodeByName(node.Name).ProviderRule)
+		assertAdj(t, graph.GetAdjacenciesSortedByName(node), parse.Graph.GetAdjacenciesSortedByName(parse.Graph.GetNodeByName(node.Name)))
+	}
+}
+
+func TestParse_provider_rules_default(t *testing.T) {
+	t.Parallel()
+	parse, err := Parse("testdata/provider_rules", nil)
+	assert.NoError(t, err)
+
+	graph := NewGraph()
+	service := &Node{
+		Name:         "testdata/provider_rules/domain.Service",
+		PackageName:  "testdata/provider_rules/domain",
+		StructName:   "Service",
+		Doc:          "Service handles the items.",
+		ProviderRule: "name:^New",
+	}
+	graph.AddNode(service)
+	cache := &Node{
+		Name:         "testdata/provider_rules/storage.Cache",
+		PackageName:  "testdata/provider_rules/storage",
+		StructName:   "Cache",
+		Doc:          "Cache keeps the recently found items.",
+		ProviderRule: ProviderRuleDirective,
+	}
+	graph.AddNode(cache)
+	repository := &Node{
+		Name:        "testdata/provider_rules/storage.Repository",
+		PackageName: "testdata/provider_rules/storage",
+		StructName:  "Repository",
+	}
+	graph.AddNode(repository)
+	worker := &Node{
+		Name:         "testdata/provider_rules/factory.Worker",
+		PackageName:  "testdata/provider_rules/factory",
+		StructName:   "Worker",
+		Doc:          "Worker processes the items in the background.",
+		ProviderRule: "method:^New",
+	}
+	graph.AddNode(worker)
+	graph.AddEdge(service, &Adj{Node: repository, Func: []string{"Find"}})
+	graph.AddEdge(worker, &Adj{Node: service, Func: []string{"Handle"}})
+
+	assertNodes(t, graph.GetNodesSortedByName(), parse.Graph.GetNodesSortedByName())
+	for _, node := range graph.GetNodesSortedByName() {
+		require.Equal(t, node.ProviderRule, parse.Graph.GetNodeByName(node.Name).ProviderRule)
+		assertAdj(t, graph.GetAdjacenciesSortedByName(node), parse.Graph.GetAdjacenciesSortedByName(parse.Graph.GetNodeByName(node.Name)))
+	}
+}
+
+func TestParse_provider_rules_patterns(t *testing.T) {
+	t.Parallel()
+	parse, err := ParseWithConfig("testdata/provider_rules", nil, Config{Provider: ProviderConfig{
+		NamePatterns: []string{"^Make"},
+	}})
+	assert.NoError(t, err)
+
+	graph := NewGraph()
+	service := &Node{
+		Name:        "testdata/provider_rules/domain.Service",
+		PackageName: "testdata/provider_rules/domain",
+		StructName:  "Service",
+	}
+	graph.AddNode(service)
+	worker := &Node{
+		Name:         "testdata/provider_rules/factory.Worker",
+		PackageName:  "testdata/provider_rules/factory",
+		StructName:   "Worker",
+		Doc:          "Worker processes the items in the background.",
+		ProviderRule: "method:^New",
+	}
+	graph.AddNode(worker)
+	cache := &Node{
+		Name:         "testdata/provider_rules/storage.Cache",
+		PackageName:  "testdata/provider_rules/storage",
+		StructName:   "Cache",
+		Doc:          "Cache keeps the recently found items.",
+		ProviderRule: ProviderRuleDirective,
+	}
+	graph.AddNode(cache)
+	repository := &Node{
+		Name:         "testdata/provider_rules/storage.Repository",
+		PackageName:  "testdata/provider_rules/storage",
+		StructName:   "Repository",
+		Doc:          "Repository stores the items.",
+		ProviderRule: "name:^Make",
+	}
+	graph.AddNode(repository)
+	graph.AddEdge(worker, &Adj{Node: service, Func: []string{"Handle"}})
+
+	assertNodes(t, graph.GetNodesSortedByName(), parse.Graph.GetNodesSortedByName())
+	for _, node := range graph.GetNodesSortedByName() {
+		require.Equal(t, node.ProviderRule, parse.Graph.GetNodeByName(node.Name).ProviderRule)
+		assertAdj(t, graph.GetAdjacenciesSortedByName(node), parse.Graph.GetAdjacenciesSortedByName(parse.Graph.GetNodeByName(node.Name)))
+	}
+}
+
+func TestParse_provider_rules_invalid_pattern(t *testing.T) {
+	t.Parallel()
+	_, err := ParseWithConfig("testdata/provider_rules", nil, Config{Provider: ProviderConfig{NamePatterns: []string{"("}}})
+	require.Error(t, err)
+}
+
+func TestParse_options(t *testing.T) {
+	t.Parallel()
+	parse, err := Parse("testdata/options", nil)
+	assert.NoError(t, err)
+
+	graph := NewGraph()
+	handler := &Node{
+		Name:        "testdata/options.Handler",
+		PackageName: "testdata/options",
+		StructName:  "Handler",
+		Doc:         "Handler handles the requests.",
+	}
+	graph.AddNode(handler)
+	metrics := &Node{
+		Name:        "testdata/options.Metrics",
+		PackageName: "testdata/options",
+		StructName:  "Metrics",
+	}
+	graph.AddNode(metrics)
+	repository := &Node{
+		Name:        "testdata/options.Repository",
+		PackageName: "testdata/options",
+		StructName:  "Repository",
+		Doc:         "Repository stores the users.",
+	}
+	graph.AddNode(repository)
+	server := &Node{
+		Name:        "testdata/options.Server",
+		PackageName: "testdata/options",
+		StructName:  "Server",
+		Doc:         "Server serves the handler.",
+	}
+	graph.AddNode(server)
+	logger := &Node{
+		Name:        "testdata/options.StdLogger",
+		PackageName: "testdata/options",
+		StructName:  "StdLogger",
+		Doc:         "StdLogger is a Logger writing to stdout.",
+	}
+	graph.AddNode(logger)
+	graph.AddEdge(handler, &Adj{Node: repository, Func: []string{"Find"}})
+	graph.AddEdge(handler, &Adj{Node: logger, Func: []string{"Log"}, Via: "testdata/options.Logger"})
+	graph.AddEdge(server, &Adj{Node: handler, Func: []string{"Serve"}})
+	graph.AddEdge(server, &Adj{Node: metrics, Func: []string{"Inc"}, Optional: true})
+	graph.AddEdge(server, &Adj{Node: logger, Func: []string{"Log"}, Via: "testdata/options.Logger", Optional: true})
+
+	assertNodes(t, graph.GetNodesSortedByName(), parse.Graph.GetNodesSortedByName())
+	for _, node := range graph.GetNodesSortedByName() {
+		assertAdj(t, graph.GetAdjacenciesSortedByName(node), parse.Graph.GetAdjacenciesSortedByName(parse.Graph.GetNodeByName(node.Name)))
+	}
+}
+
+func TestParse_composition(t *testing.T) {
+	t.Parallel()
+	parse, err := ParseWithConfig("testdata/composition", nil, Config{Mode: ModeComposition})
+	assert.NoError(t, err)
+
+	graph := NewGraph()
+	handler := &Node{
+		Name:        "testdata/composition/service.Handler",
+		PackageName: "testdata/composition/service",
+		StructName:  "Handler",
+		Doc:         "Handler exposes a Service.",
+		Instances:   1,
+	}
+	graph.AddNode(handler)
+	logger := &Node{
+		Name:        "testdata/composition/service.Logger",
+		PackageName: "testdata/composition/service",
+		StructName:  "Logger",
+		Doc:         "Logger writes the logs.",
+		Instances:   1,
+	}
+	graph.AddNode(logger)
+	memoryRepository := &Node{
+		Name:        "testdata/composition/service.MemoryRepository",
+		PackageName: "testdata/composition/service",
+		StructName:  "MemoryRepository",
+		Doc:         "MemoryRepository is a Repository kept in memory.",
+		Instances:   1,
+	}
+	graph.AddNode(memoryRepository)
+	sqlRepository := &Node{
+		Name:        "testdata/composition/service.SQLRepository",
+		PackageName: "testdata/composition/service",
+		StructName:  "SQLRepository",
+		Doc:         "SQLRepository is a Repository backed by a database.",
+		Instances:   1,
+	}
+	graph.AddNode(sqlRepository)
+	service := &Node{
+		Name:        "testdata/composition/service.Service",
+		PackageName: "testdata/composition/service",
+		StructName:  "Service",
+		Doc:         "Service serves the users.",
+		Instances:   2,
+	}
+	graph.AddNode(service)
+	graph.AddEdge(handler, &Adj{Node: service, Func: []string{"Run"}})
+	graph.AddEdge(service, &Adj{Node: logger, Func: []string{"Log"}})
+	graph.AddEdge(service, &Adj{Node: memoryRepository, Func: []string{"Find"}, Via: "testdata/composition/service.Repository"})
+	graph.AddEdge(service, &Adj{Node: sqlRepository, Func: []string{"Find"}, Via: "testdata/composition/service.Repository"})
+
+	assertNodes(t, graph.GetNodesSortedByName(), parse.Graph.GetNodesSortedByName())
+	for _, node := range graph.GetNodesSortedByName() {
+		require.Equal(t, node.Instances, parse.Graph.GetNodeByName(node.Name).Instances)
+		assertAdj(t, graph.GetAdjacenciesSortedByName(node), parse.Graph.GetAdjacenciesSortedByName(parse.Graph.GetNodeByName(node.Name)))
+	}
+}
+
+func TestParse_assignment(t *testing.T) {
+	t.Parallel()
+	parse, err := Parse("testdata/assignment", nil)
+	assert.NoError(t, err)
+
+	graph := NewGraph()
+	a := &Node{
+		Name:        "testdata/assignment.A",
+		PackageName: "testdata/assignment",
+		StructName:  "A",
+		Doc:         "A has its fields assigned after being created.",
+	}
+	graph.AddNode(a)
+	b := &Node{
+		Name:        "testdata/assignment.B",
+		PackageName: "testdata/assignment",
+		StructName:  "B",
+		Doc:         "B is returned as a value.",
+	}
+	graph.AddNode(b)
+	c := &Node{
+		Name:        "testdata/assignment.C",
+		PackageName: "testdata/assignment",
+		StructName:  "C",
+		Doc:         "C is returned from an if block.",
+	}
+	graph.AddNode(c)
+	cache := &Node{
+		Name:        "testdata/assignment.Cache",
+		PackageName: "testdata/assignment",
+		StructName:  "Cache",
+	}
+	graph.AddNode(cache)
+	clock := &Node{
+		Name:        "testdata/assignment.Clock",
+		PackageName: "testdata/assignment",
+		StructName:  "Clock",
+	}
+	graph.AddNode(clock)
+	d := &Node{
+		Name:        "testdata/assignment.D",
+		PackageName: "testdata/assignment",
+		StructName:  "D",
+		Doc:         "D has its fields assigned by a helper method.",
+	}
+	graph.AddNode(d)
+	e := &Node{
+		Name:        "testdata/assignment.E",
+		PackageName: "testdata/assignment",
+		StructName:  "E",
+		Doc:         "E has its fields assigned from aliases of the parameters.",
+	}
+	graph.AddNode(e)
+	logger := &Node{
+		Name:        "testdata/assignment.Logger",
+		PackageName: "testdata/assignment",
+		StructName:  "Logger",
+	}
+	graph.AddNode(logger)
+	repository := &Node{
+		Name:        "testdata/assignment.Repository",
+		PackageName: "testdata/assignment",
+		StructName:  "Repository",
+	}
+	graph.AddNode(repository)
+	graph.AddEdge(a, &Adj{Node: repository, Func: []string{"Find"}})
+	graph.AddEdge(b, &Adj{Node: cache, Func: []string{"Get"}})
+	graph.AddEdge(c, &Adj{Node: clock, Func: []string{"Now"}})
+	graph.AddEdge(c, &Adj{Node: repository, Func: []string{"Save"}})
+	graph.AddEdge(d, &Adj{Node: logger, Func: []string{"Log"}})
+	graph.AddEdge(e, &Adj{Node: cache, Func: []string{"Get"}})
+	graph.AddEdge(e, &Adj{Node: repository, Func: []string{"Find"}})
+
+	assertNodes(t, graph.GetNodesSortedByName(), parse.Graph.GetNodesSortedByName())
+	for _, node := range graph.GetNodesSortedByName() {
+		assertAdj(t, graph.GetAdjacenciesSortedByName(node), parse.Graph.GetAdjacenciesSortedByName(parse.Graph.GetNodeByName(node.Name)))
+	}
+}
+
+func TestParse_calls(t *testing.T) {
+	t.Parallel()
+	parse, err := Parse("testdata/calls", nil)
+	assert.NoError(t, err)
+
+	service := parse.Graph.GetNodeByName("testdata/calls.Service")
+	require.NotNil(t, service)
+	adjacencies := parse.Graph.GetAdjacenciesSortedByName(service)
+	require.Len(t, adjacencies, 2)
+
+	require.Equal(t, "testdata/calls.Mailer", adjacencies[0].Node.Name)
+	require.Equal(t, []Call{
+		{Caller: "Create", Callee: "Notify", Field: "notifier"},
+		{Caller: "Remove", Callee: "Notify", Field: "notifier"}, // through purge and notify
+		{Caller: "notify", Callee: "Notify", Field: "notifier"},
+		{Caller: "purge", Callee: "Notify", Field: "notifier"},
+	}, adjacencies[0].Calls)
+
+	require.Equal(t, "testdata/calls.Repository", adjacencies[1].Node.Name)
+	require.Equal(t, []string{"Delete", "Find", "Save"}, adjacencies[1].Func)
+	require.Equal(t, []Call{
+		{Caller: "Create", Callee: "Save", Field: "repo"},
+		{Caller: "Get", Callee: "Find", Field: "repo"},
+		{Caller: "Remove", Callee: "Delete", Field: "repo"}, // through purge
+		{Caller: "notify", Callee: "Delete", Field: "repo"}, // through purge, called back
+		{Caller: "purge", Callee: "Delete", Field: "repo"},
+	}, adjacencies[1].Calls)
+}
+
+func TestParse_tags(t *testing.T) {
+	t.Parallel()
+	parse, err := Parse("testdata/tags", nil)
+	require.NoError(t, err)
+
+	store := parse.Graph.GetNodeByName("testdata/tags.Store")
+	require.NotNil(t, store)
+	require.Equal(t, []string{"infrastructure", "storage"}, store.Tags)
+	require.True(t, strings.HasSuffix(store.Provider.Filename, "tags.go"))
+	require.Equal(t, 8, store.Provider.Line)
+
+	users := parse.Graph.GetNodeByName("testdata/tags.Users")
+	require.NotNil(t, users)
+	require.Equal(t, []string{"domain"}, users.Tags)
+	require.Equal(t, 22, users.Provider.Line)
+}
+
+func TestParse_unknown_mode(t *testing.T) {
+	t.Parallel()
+	_, err := ParseWithConfig("testdata/fn", nil, Config{Mode: "unknown"})
+	require.ErrorIs(t, err, ErrUnknownMode)
+}
+
 func assertAdj(t *testing.T, expectedAdj, gotAdj []*Adj) {
+	require.Equal(t, len(expectedAdj), len(gotAdj))
 	for i := range expectedAdj {
 		require.Equal(t, expectedAdj[i].Func, gotAdj[i].Func)
+		require.Equal(t, expectedAdj[i].Via, gotAdj[i].Via)
+		require.Equal(t, expectedAdj[i].Optional, gotAdj[i].Optional)
 		assertNode(t, expectedAdj[i].Node, gotAdj[i].Node)
 
 	}
@@ -451,8 +1073,103 @@ func assertNode(t *testing.T, expected, got *Node) {
 	require.Equal(t, expected.StructName, got.StructName)
 	require.Equal(t, expected.PackageName, got.PackageName)
 	require.Equal(t, expected.Doc, got.Doc)
+	require.Equal(t, expected.Module, got.Module)
+	require.Equal(t, expected.Cleanup, got.Cleanup)
 
 	for i2 := range expected.Methods {
 		require.Equal(t, expected.Methods[i2].String(), got.Methods[i2].String())
 	}
 }
+
+func TestParse_sequence(t *testing.T) {
+	t.Parallel()
+	parse, err := Parse("testdata/sequence", nil)
+	require.NoError(t, err)
+
+	store := parse.Graph.GetNodeByName("testdata/sequence/orders.Store")
+	payments := parse.Graph.GetNodeByName("testdata/sequence/orders.Payments")
+	service := parse.Graph.GetNodeByName("testdata/sequence/orders.Service")
+	handler := parse.Graph.GetNodeByName("testdata/sequence/handlers.OrderHandler")
+	require.NotNil(t, handler)
+
+	assert.Equal(t, map[string][]Step{
+		"Create": {
+			{Kind: StepCall, Callee: "Count", Field: "store", Nodes: []*Node{store}},
+			{Kind: StepAlt, Branches: []Branch{
+				{Label: `kind == "free"`, Steps: []Step{{Kind: StepCall, Callee: "log"}}},
+				{Label: "default", Steps: []Step{{Kind: StepCall, Callee: "Place", Field: "placer", Nodes: []*Node{service}}}},
+			}},
+		},
+		"log": {
+			{Kind: StepCall, Callee: "Count", Field: "store", Nodes: []*Node{store}},
+		},
+	}, handler.Sequences)
+	assert.Equal(t, map[string][]Step{
+		"Cancel": {
+			{Kind: StepCall, Callee: "Count", Field: "store", Nodes: []*Node{store}},
+			{Kind: StepPar, Branches: []Branch{
+				{Label: "go s.payments.Charge(s.store.Count())", Steps: []Step{{Kind: StepCall, Callee: "Charge", Field: "payments", Nodes: []*Node{payments}}}},
+			}},
+			{Kind: StepCall, Callee: "Insert", Field: "store", Nodes: []*Node{store}},
+			{Kind: StepCall, Callee: "Refund", Field: "payments", Nodes: []*Node{payments}},
+			{Kind: StepCall, Callee: "Count", Field: "store", Nodes: []*Node{store}},
+		},
+		"Place": {
+			{Kind: StepLoop, Branches: []Branch{
+				{Label: "for _, item := range items", Steps: []Step{{Kind: StepCall, Callee: "Insert", Field: "store", Nodes: []*Node{store}}}},
+			}},
+			{Kind: StepCall, Callee: "Charge", Field: "payments", Nodes: []*Node{payments}},
+			{Kind: StepAlt, Branches: []Branch{
+				{Label: "if err != nil", Steps: []Step{{Kind: StepCall, Callee: "Refund", Field: "payments", Nodes: []*Node{payments}}}},
+			}},
+		},
+		"Retry": {
+			{Kind: StepLoop, Branches: []Branch{
+				{Label: "for i < 3", Steps: []Step{
+					{Kind: StepCall, Callee: "Charge", Field: "payments", Nodes: []*Node{payments}},
+					{Kind: StepAlt, Branches: []Branch{
+						{Label: "if s.payments.Charge(amount) == nil"},
+						{Label: "else if i == 2", Steps: []Step{{Kind: StepCall, Callee: "Refund", Field: "payments", Nodes: []*Node{payments}}}},
+						{Label: "else", Steps: []Step{{Kind: StepCall, Callee: "Count", Field: "store", Nodes: []*Node{store}}}},
+					}},
+				}},
+			}},
+		},
+	}, service.Sequences)
+	assert.Nil(t, store.Sequences)
+}
+
+func TestParse_binaries(t *testing.T) {
+	t.Parallel()
+	parse, err := Parse("testdata/binaries", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Binary{
+		{
+			Package:  "testdata/binaries/cmd/api",
+			Packages: []string{"testdata/binaries/api", "testdata/binaries/cmd/api", "testdata/binaries/store"},
+		},
+		{
+			Package:  "testdata/binaries/cmd/worker",
+			Packages: []string{"testdata/binaries/cmd/worker", "testdata/binaries/store", "testdata/binaries/worker"},
+		},
+	}, parse.Binaries)
+	require.NotNil(t, parse.Graph.GetNodeByName("database/sql.DB"))
+	require.NotNil(t, parse.Graph.GetNodeByName("net/http.Client"))
+}
+
+func TestParse_crossPackageProvider(t *testing.T) {
+	t.Parallel()
+	parse, err := Parse("testdata/cross_package", nil)
+	require.NoError(t, err)
+
+	service := parse.Graph.GetNodeByName("testdata/cross_package/domain.Service")
+	require.NotNil(t, service)
+	require.NotNil(t, service.P)
+	assert.Equal(t, "testdata/cross_package/domain", service.P.PkgPath)
+	assert.Equal(t, "factory.go", filepath.Base(service.Provider.Filename))
+
+	adj := parse.Graph.GetAdjacenciesSortedByName(service)
+	require.Len(t, adj, 1)
+	assert.Equal(t, "testdata/cross_package/domain.Repo", adj[0].Node.Name)
+}
@@ -0,0 +1,134 @@
+package parse
+
+// Hop is an edge of a path.
+type Hop struct {
+	From *Node
+	Adj  *Adj
+}
+
+// Path is a sequence of hops, each one starting from the node the previous one ends on.
+type Path []Hop
+
+// Nodes returns the nodes of the path, in order.
+func (p Path) Nodes() []*Node {
+	if len(p) == 0 {
+		return nil
+	}
+	nodes := []*Node{p[0].From}
+	for _, hop := range p {
+		nodes = append(nodes, hop.Adj.Node)
+	}
+	return nodes
+}
+
+// MaxPaths is the number of paths GetPaths returns at most when k is not positive.
+const MaxPaths = 1000
+
+// GetPaths returns the simple paths going from one node to another, shortest first.
+// When k is positive, only the k shortest paths are returned, otherwise at most MaxPaths.
+func (g *Graph) GetPaths(from, to *Node, k int) []Path {
+	paths := make([]Path, 0)
+	if from == nil || to == nil || from == to {
+		return paths
+	}
+	if k <= 0 {
+		k = MaxPaths
+	}
+	distances := g.getDistancesTo(to)
+	if _, ok := distances[from]; !ok {
+		return paths
+	}
+
+	// Iterative deepening, the paths of each length are enumerated depth first with a single visited set, a node is
+	// only followed when it can still reach the destination within the length, a path visits each node at most once.
+	s := pathSearch{graph: g, to: to, k: k, distances: distances, visited: map[*Node]bool{from: true}, paths: paths}
+	for length := distances[from]; length < len(distances) && len(s.paths) < k; length++ {
+		s.search(from, length)
+	}
+	return s.paths
+}
+
+// getDistancesTo returns the length of the shortest path from each node reaching the node, the others are absent.
+func (g *Graph) getDistancesTo(to *Node) map[*Node]int {
+	distances := map[*Node]int{to: 0}
+	queue := []*Node{to}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, inbound := range node.InboundEdges {
+			if _, ok := distances[inbound]; ok {
+				continue
+			}
+			distances[inbound] = distances[node] + 1
+			queue = append(queue, inbound)
+		}
+	}
+	return distances
+}
+
+type pathSearch struct {
+	graph     *Graph
+	to        *Node
+	k         int
+	distances map[*Node]int
+	visited   map[*Node]bool
+	current   Path
+	paths     []Path
+}
+
+// search appends the paths going from the node to the destination in exactly remaining hops.
+func (s *pathSearch) search(node *Node, remaining int) {
+	for _, adj := range s.graph.GetAdjacenciesSortedByName(node) {
+		if len(s.paths) == s.k {
+			return
+		}
+		distance, ok := s.distances[adj.Node]
+		if !ok || distance > remaining-1 || s.visited[adj.Node] {
+			continue
+		}
+		s.current = append(s.current, Hop{From: node, Adj: adj})
+		if adj.Node == s.to {
+			if remaining == 1 {
+				path := make(Path, len(s.current))
+				copy(path, s.current)
+				s.paths = append(s.paths, path)
+			}
+		} else {
+			s.visited[adj.Node] = true
+			s.search(adj.Node, remaining-1)
+			s.visited[adj.Node] = false
+		}
+		s.current = s.current[:len(s.current)-1]
+	}
+}
+
+// GetPathsGraph returns a new graph holding only the nodes and the edges of the paths.
+// The nodes are copied, the graph they come from is left untouched.
+func (g *Graph) GetPathsGraph(paths []Path) *Graph {
+	graph := NewGraph()
+	copies := make(map[*Node]*Node)
+	getCopy := func(node *Node) *Node {
+		if c, ok := copies[node]; ok {
+			return c
+		}
+		c := *node
+		copies[node] = &c
+		graph.AddNode(&c)
+		return &c
+	}
+
+	added := make(map[*Adj]bool)
+	for _, path := range paths {
+		for _, hop := range path {
+			from, to := getCopy(hop.From), getCopy(hop.Adj.Node)
+			if added[hop.Adj] {
+				continue
+			}
+			added[hop.Adj] = true
+			adj := *hop.Adj
+			adj.Node = to
+			graph.AddEdge(from, &adj)
+		}
+	}
+	return graph
+}
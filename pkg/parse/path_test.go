@@ -0,0 +1,111 @@
+package parse
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraph_paths(t *testing.T) {
+	graph := NewGraph()
+
+	nodeA := &Node{Name: "A"}
+	nodeB := &Node{Name: "B"}
+	nodeC := &Node{Name: "C"}
+	nodeD := &Node{Name: "D"}
+	nodeE := &Node{Name: "E"}
+
+	graph.AddNode(nodeA)
+	graph.AddNode(nodeB)
+	graph.AddNode(nodeC)
+	graph.AddNode(nodeD)
+	graph.AddNode(nodeE)
+
+	ab := &Adj{Node: nodeB, Func: []string{"FuncB"}}
+	ad := &Adj{Node: nodeD}
+	bc := &Adj{Node: nodeC}
+	bd := &Adj{Node: nodeD, Func: []string{"FuncD"}}
+	cd := &Adj{Node: nodeD}
+	dc := &Adj{Node: nodeC}
+	ae := &Adj{Node: nodeE}
+	graph.AddEdge(nodeA, ab)
+	graph.AddEdge(nodeA, ad)
+	graph.AddEdge(nodeA, ae)
+	graph.AddEdge(nodeB, bc)
+	graph.AddEdge(nodeB, bd)
+	graph.AddEdge(nodeC, cd)
+	graph.AddEdge(nodeD, dc)
+
+	t.Run("TestGetPaths", func(t *testing.T) {
+		assert.Equal(t, []Path{
+			{{From: nodeA, Adj: ad}},
+			{{From: nodeA, Adj: ab}, {From: nodeB, Adj: bd}},
+			{{From: nodeA, Adj: ab}, {From: nodeB, Adj: bc}, {From: nodeC, Adj: cd}},
+		}, graph.GetPaths(nodeA, nodeD, 0))
+	})
+
+	t.Run("TestGetPaths_k", func(t *testing.T) {
+		assert.Equal(t, []Path{
+			{{From: nodeA, Adj: ad}},
+			{{From: nodeA, Adj: ab}, {From: nodeB, Adj: bd}},
+		}, graph.GetPaths(nodeA, nodeD, 2))
+	})
+
+	t.Run("TestGetPaths_none", func(t *testing.T) {
+		assert.Empty(t, graph.GetPaths(nodeE, nodeA, 0))
+		assert.Empty(t, graph.GetPaths(nodeA, nodeA, 0))
+	})
+
+	t.Run("TestPathNodes", func(t *testing.T) {
+		assert.Equal(t, []*Node{nodeA, nodeB, nodeD}, Path{{From: nodeA, Adj: ab}, {From: nodeB, Adj: bd}}.Nodes())
+	})
+
+	t.Run("TestGetPathsGraph", func(t *testing.T) {
+		pathsGraph := graph.GetPathsGraph(graph.GetPaths(nodeA, nodeD, 2))
+
+		nodes := pathsGraph.GetNodesSortedByName()
+		assert.Len(t, nodes, 3)
+		assert.Equal(t, "A", nodes[0].Name)
+		assert.Equal(t, "B", nodes[1].Name)
+		assert.Equal(t, "D", nodes[2].Name)
+		assert.Len(t, pathsGraph.GetAdjacency(nodes[0]), 2)
+		assert.Equal(t, []string{"FuncD"}, pathsGraph.GetAdjacency(nodes[1])[0].Func)
+		assert.Same(t, nodes[2], pathsGraph.GetAdjacency(nodes[1])[0].Node)
+		assert.Len(t, nodeD.InboundEdges, 3, "the original graph is left untouched")
+	})
+}
+
+func TestGraph_GetPaths_bounded(t *testing.T) {
+	// 12 layers of 4 nodes, each node depending on every node of the next layer, 4^10 paths between the ends.
+	graph := NewGraph()
+	from, to := &Node{Name: "from"}, &Node{Name: "to"}
+	graph.AddNode(from)
+	graph.AddNode(to)
+	previous := []*Node{from}
+	for layer := 0; layer < 12; layer++ {
+		current := make([]*Node, 0, 4)
+		for i := 0; i < 4; i++ {
+			node := &Node{Name: fmt.Sprintf("%02d_%d", layer, i)}
+			graph.AddNode(node)
+			current = append(current, node)
+			for _, p := range previous {
+				graph.AddEdge(p, &Adj{Node: node})
+			}
+		}
+		previous = current
+	}
+	for _, p := range previous {
+		graph.AddEdge(p, &Adj{Node: to})
+	}
+	graph.AddEdge(from, &Adj{Node: previous[0]})
+
+	paths := graph.GetPaths(from, to, 0)
+	assert.Len(t, paths, MaxPaths)
+	assert.Len(t, paths[0], 2, "the shortest path comes first")
+	for i := 1; i < len(paths); i++ {
+		assert.LessOrEqual(t, len(paths[i-1]), len(paths[i]))
+	}
+
+	assert.Len(t, graph.GetPaths(from, to, 3), 3)
+}
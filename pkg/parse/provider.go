@@ -2,6 +2,7 @@ package parse
 
 import (
 	"go/ast"
+	"go/token"
 	"go/types"
 
 	"github.com/emilien-puget/go-dependency-graph/pkg/parse/struct_decl"
@@ -14,48 +15,77 @@ type dep struct {
 	VarName        string
 	Funcs          []string
 	External       bool
+	Interface      *types.Named // set when the dependency is injected through a named interface
+	Optional       bool         // set when the dependency is injected through a functional option
+	Fields         []string     // the fields of the provided struct the dependency is assigned to
 }
 
-func searchProvider(funcdecl *ast.FuncDecl, packageName string, imports map[string]importDecl, typesInfo *types.Info, t map[string]map[string]*struct_decl.Decl) (name string, deps map[string][]dep, decl *struct_decl.Decl) {
-	if len(funcdecl.Name.Name) < 3 || funcdecl.Name.Name[:3] != "New" {
-		return "", nil, nil
+// providedStruct represents the struct created by a provider.
+type providedStruct struct {
+	PackageName string
+	Name        string
+	Rule        string // the rule the provider was matched with
+}
+
+func searchProvider(funcdecl *ast.FuncDecl, packageName, modulePath string, imports map[string]importDecl, typesInfo *types.Info, t map[string]map[string]*struct_decl.Decl, funcDecls map[*types.Func]funcSource, matcher providerMatcher) (provided providedStruct, deps map[string][]dep, decl *struct_decl.Decl) {
+	rule := matcher.match(funcdecl)
+	if rule == "" {
+		return providedStruct{}, nil, nil
+	}
+	pkgName, name := searchDependencyName(funcdecl)
+	if pkgName == "" {
+		pkgName = packageName
+	} else {
+		pkgName = imports[pkgName].Path
 	}
-	name = searchDependencyName(funcdecl)
-	decl, ok := t[packageName][name]
+	decl, ok := t[pkgName][name]
 	if !ok {
-		return "", nil, nil
+		return providedStruct{}, nil, nil
 	}
 
-	deps = searchDependencies(funcdecl, packageName, imports, typesInfo)
+	deps = searchDependencies(funcdecl, packageName, modulePath, imports, typesInfo)
 
-	searchDependenciesAssignment(funcdecl, deps, decl)
-	return name, deps, decl
+	searchDependenciesAssignment(funcdecl, deps, decl, typesInfo, funcDecls)
+	return providedStruct{PackageName: pkgName, Name: name, Rule: rule}, deps, decl
 }
 
 // searchDependencyName search the created dependency as the first variable returned.
-func searchDependencyName(funcdecl *ast.FuncDecl) string {
+// pkgName is the name the package of the dependency is imported with, empty if it is declared in the same package.
+func searchDependencyName(funcdecl *ast.FuncDecl) (pkgName, name string) {
 	results := funcdecl.Type.Results
 	if results == nil {
-		return ""
+		return "", ""
 	}
-	switch t := funcdecl.Type.Results.List[0].Type.(type) { // get the type of the dependency.
-	case *ast.StarExpr: // dependency returned as a pointer.
+	typ := funcdecl.Type.Results.List[0].Type // get the type of the dependency.
+	if star, ok := typ.(*ast.StarExpr); ok {  // dependency returned as a pointer.
+		typ = star.X
+	}
+	switch t := typ.(type) {
+	case *ast.SelectorExpr: // dependency declared in another package.
 		ident, ok := t.X.(*ast.Ident)
 		if !ok {
-			return ""
+			return "", ""
 		}
-		return ident.Name
-	case *ast.Ident: // dependency returned as a value.
-		return t.Name
+		return ident.Name, t.Sel.Name
+	case *ast.Ident: // dependency declared in the same package.
+		return "", t.Name
 	}
-	return ""
+	return "", ""
 }
 
 // searchDependencies returns the dependency found in the provider type declaration.
-func searchDependencies(funcdecl *ast.FuncDecl, name string, imports map[string]importDecl, info *types.Info) (deps map[string][]dep) {
+// The fields of a parameter struct are unwrapped, each of them is a dependency named after the parameter and the field.
+func searchDependencies(funcdecl *ast.FuncDecl, name, modulePath string, imports map[string]importDecl, info *types.Info) (deps map[string][]dep) {
 	deps = map[string][]dep{}
 	for _, param := range funcdecl.Type.Params.List {
-		if !checkDepsMethods(info.TypeOf(param.Type)) { // ignore dependencies without methods
+		paramType := info.TypeOf(param.Type)
+		varName := ""
+		for _, name := range param.Names {
+			varName = name.String()
+		}
+		iface := getNamedInterface(paramType)
+		if iface == nil && !checkDepsMethods(paramType) { // ignore dependencies without methods
+			searchParameterObjectDependencies(paramType, varName, modulePath, deps)
 			continue
 		}
 		packageName, serviceName := getDepID(param.Type)
@@ -69,10 +99,6 @@ func searchDependencies(funcdecl *ast.FuncDecl, name string, imports map[string]
 		} else {
 			packageName = imp.Path
 		}
-		varName := ""
-		for _, name := range param.Names {
-			varName = name.String()
-		}
 		if external {
 			packageName = imp.Path
 		}
@@ -81,11 +107,44 @@ func searchDependencies(funcdecl *ast.FuncDecl, name string, imports map[string]
 			PackageName:    packageName,
 			DependencyName: serviceName,
 			External:       external,
+			Interface:      iface,
 		})
 	}
 	return deps
 }
 
+// searchParameterObjectDependencies adds a dependency for each field of a parameter struct having methods.
+func searchParameterObjectDependencies(paramType types.Type, varName, modulePath string, deps map[string][]dep) {
+	named := getNamedType(paramType)
+	if named == nil {
+		return
+	}
+	s, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+	for i := 0; i < s.NumFields(); i++ {
+		f := s.Field(i)
+		fieldNamed := getNamedType(f.Type())
+		if fieldNamed == nil {
+			continue
+		}
+		iface := getNamedInterface(f.Type())
+		if _, isStruct := fieldNamed.Underlying().(*types.Struct); iface == nil && !(isStruct && checkDepsMethods(f.Type())) { // ignore the configuration values such as time.Duration
+			continue
+		}
+		pkgPath := fieldNamed.Obj().Pkg().Path()
+		fieldVarName := varName + "." + f.Name()
+		deps[fieldVarName] = append(deps[fieldVarName], dep{
+			VarName:        fieldVarName,
+			PackageName:    pkgPath,
+			DependencyName: fieldNamed.Obj().Name(),
+			External:       isExternal(pkgPath, modulePath),
+			Interface:      iface,
+		})
+	}
+}
+
 func checkDepsMethods(t types.Type) bool {
 	ptrType, ok := t.(*types.Pointer)
 	if ok {
@@ -102,6 +161,19 @@ func checkDepsMethods(t types.Type) bool {
 	return false
 }
 
+// getNamedInterface returns t if it is a named interface declaring at least one method.
+func getNamedInterface(t types.Type) *types.Named {
+	namedType, ok := t.(*types.Named)
+	if !ok {
+		return nil
+	}
+	iface, ok := namedType.Underlying().(*types.Interface)
+	if !ok || iface.NumMethods() == 0 {
+		return nil
+	}
+	return namedType
+}
+
 func getDepID(dep ast.Expr) (packageName, serviceName string) {
 	if depStar, ok := dep.(*ast.StarExpr); ok {
 		dep = depStar.X
@@ -121,51 +193,361 @@ func getDepID(dep ast.Expr) (packageName, serviceName string) {
 	return "", ""
 }
 
-// searchDependenciesAssignment parse the provider function to search for a return.
-// this return is then parsed to look for injected functions to complete the deps found in the previous step.
-func searchDependenciesAssignment(funcdecl *ast.FuncDecl, deps map[string][]dep, s *struct_decl.Decl) {
-	if funcdecl.Body != nil {
-		for _, stmt := range funcdecl.Body.List {
-			retStmt, ok := stmt.(*ast.ReturnStmt)
-			if !ok {
-				continue
+// searchDependenciesAssignment follows the dependencies of a provider to the fields of the struct it creates.
+// The provider body is walked in order, the local variables are bound to the dependencies they hold, so a dependency
+// is followed through its aliases, and a variable bound in a branch holds any of the values of the branches after it.
+// The composite literals of the struct, the assignments to its fields and the calls to its methods are searched, so the
+// funcs of the dependencies are found whatever the way the struct is built and returned.
+// It returns the name of the dependencies assigned to a field.
+func searchDependenciesAssignment(funcdecl *ast.FuncDecl, deps map[string][]dep, s *struct_decl.Decl, info *types.Info, funcDecls map[*types.Func]funcSource) (assigned map[string]bool) {
+	as := &assignmentSearch{
+		s:         s,
+		funcDecls: funcDecls,
+		visited:   make(map[*ast.FuncDecl]bool),
+	}
+	return as.search(funcdecl, deps, info)
+}
+
+// assignmentSearch searches the assignments of the dependencies to the fields of a struct.
+type assignmentSearch struct {
+	s         *struct_decl.Decl
+	funcDecls map[*types.Func]funcSource
+	visited   map[*ast.FuncDecl]bool
+}
+
+// bindings maps the variables of a function to the names they are bound to, a parameter is bound to its own name.
+type bindings map[types.Object][]string
+
+func (b bindings) copy() bindings {
+	c := make(bindings, len(b))
+	for obj, names := range b {
+		c[obj] = names
+	}
+	return c
+}
+
+// merge binds each variable to the names it is bound to in any of the branches.
+func (b bindings) merge(branches ...bindings) {
+	merged := make(bindings)
+	for _, branch := range branches {
+		for obj, names := range branch {
+			merged[obj] = appendUnique(merged[obj], names...)
+		}
+	}
+	for obj := range b {
+		delete(b, obj)
+	}
+	for obj, names := range merged {
+		b[obj] = names
+	}
+}
+
+func (as *assignmentSearch) search(funcdecl *ast.FuncDecl, deps map[string][]dep, info *types.Info) (assigned map[string]bool) {
+	assigned = make(map[string]bool)
+	if funcdecl == nil || funcdecl.Body == nil || info == nil || as.s.ActualNamedType == nil {
+		return assigned
+	}
+	as.visited[funcdecl] = true
+	defer delete(as.visited, funcdecl)
+
+	env := make(bindings)
+	for _, field := range funcdecl.Type.Params.List {
+		for _, name := range field.Names {
+			if obj := info.Defs[name]; obj != nil {
+				env[obj] = []string{name.Name}
 			}
-			uExpr, ok := retStmt.Results[0].(*ast.UnaryExpr)
-			if !ok {
+		}
+	}
+	w := assignmentWalk{assignmentSearch: as, deps: deps, info: info, assigned: assigned}
+	w.stmts(funcdecl.Body.List, env)
+	return assigned
+}
+
+// assignmentWalk walks the statements of a function, in order.
+type assignmentWalk struct {
+	*assignmentSearch
+	deps     map[string][]dep
+	info     *types.Info
+	assigned map[string]bool
+}
+
+func (w assignmentWalk) stmts(list []ast.Stmt, env bindings) {
+	for _, stmt := range list {
+		w.stmt(stmt, env)
+	}
+}
+
+func (w assignmentWalk) stmt(stmt ast.Stmt, env bindings) {
+	switch st := stmt.(type) {
+	case nil:
+	case *ast.BlockStmt:
+		w.stmts(st.List, env)
+	case *ast.LabeledStmt:
+		w.stmt(st.Stmt, env)
+	case *ast.AssignStmt: // s.repo = repo, r := repo.
+		w.assign(st.Lhs, st.Rhs, env)
+	case *ast.DeclStmt: // var r = repo.
+		d, ok := st.Decl.(*ast.GenDecl)
+		if !ok {
+			return
+		}
+		for _, spec := range d.Specs {
+			if vs, ok := spec.(*ast.ValueSpec); ok {
+				lhs := make([]ast.Expr, 0, len(vs.Names))
+				for _, name := range vs.Names {
+					lhs = append(lhs, name)
+				}
+				w.assign(lhs, vs.Values, env)
+			}
+		}
+	case *ast.IfStmt:
+		w.stmt(st.Init, env)
+		w.expr(st.Cond, env)
+		body, els := env.copy(), env.copy()
+		w.stmts(st.Body.List, body)
+		w.stmt(st.Else, els)
+		env.merge(body, els)
+	case *ast.ForStmt:
+		w.stmt(st.Init, env)
+		w.expr(st.Cond, env)
+		body := env.copy()
+		w.stmts(st.Body.List, body)
+		w.stmt(st.Post, body)
+		env.merge(env, body)
+	case *ast.RangeStmt:
+		w.expr(st.X, env)
+		body := env.copy()
+		w.stmts(st.Body.List, body)
+		env.merge(env, body)
+	case *ast.SwitchStmt:
+		w.stmt(st.Init, env)
+		w.expr(st.Tag, env)
+		w.clauses(st.Body, env)
+	case *ast.TypeSwitchStmt:
+		w.stmt(st.Init, env)
+		w.stmt(st.Assign, env)
+		w.clauses(st.Body, env)
+	case *ast.SelectStmt:
+		w.clauses(st.Body, env)
+	default:
+		w.expr(stmt, env)
+	}
+}
+
+// clauses walks the clauses of a switch or a select, none of them may run.
+func (w assignmentWalk) clauses(body *ast.BlockStmt, env bindings) {
+	branches := []bindings{env.copy()}
+	for _, clause := range body.List {
+		branch := env.copy()
+		switch c := clause.(type) {
+		case *ast.CaseClause:
+			for _, expr := range c.List {
+				w.expr(expr, branch)
+			}
+			w.stmts(c.Body, branch)
+		case *ast.CommClause:
+			w.stmt(c.Comm, branch)
+			w.stmts(c.Body, branch)
+		}
+		branches = append(branches, branch)
+	}
+	env.merge(branches...)
+}
+
+// assign binds the variables to the names the values are bound to, and follows the values assigned to the fields of
+// the struct.
+func (w assignmentWalk) assign(lhs, rhs []ast.Expr, env bindings) {
+	for _, expr := range rhs {
+		w.expr(expr, env)
+	}
+	for i, expr := range lhs {
+		var value ast.Expr
+		if len(lhs) == len(rhs) {
+			value = rhs[i]
+		}
+		switch e := expr.(type) {
+		case *ast.Ident:
+			obj := getObject(w.info, e)
+			if obj == nil {
 				continue
 			}
-			cpLit, ok := uExpr.X.(*ast.CompositeLit)
-			if !ok {
+			if names := w.bound(value, env); len(names) > 0 {
+				env[obj] = names
 				continue
 			}
-			for _, elt := range cpLit.Elts {
-				kvExpr, ok := elt.(*ast.KeyValueExpr)
-				if !ok {
-					continue
-				}
-				setDepsFunc(kvExpr, deps, s)
+			delete(env, obj)
+		case *ast.SelectorExpr:
+			if value != nil && w.isStruct(w.info.TypeOf(e.X)) {
+				w.setField(e.Sel.Name, value, env)
 			}
 		}
 	}
 }
 
-func setDepsFunc(kvExpr *ast.KeyValueExpr, deps map[string][]dep, s *struct_decl.Decl) {
-	switch value := kvExpr.Value.(type) {
+// expr follows the values given to the fields of the struct in the composite literals and the method calls of the
+// expression, the function literals are walked with the bindings of the expression.
+func (w assignmentWalk) expr(n ast.Node, env bindings) {
+	if n == nil {
+		return
+	}
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			w.stmts(node.Body.List, env.copy())
+			return false
+		case *ast.CompositeLit: // return &T{repo: repo}, s := T{repo: repo}.
+			if !w.isStruct(w.info.TypeOf(node)) {
+				return true
+			}
+			for _, elt := range node.Elts {
+				if kvExpr, ok := elt.(*ast.KeyValueExpr); ok {
+					if key, ok := kvExpr.Key.(*ast.Ident); ok {
+						w.setField(key.Name, kvExpr.Value, env)
+					}
+				}
+			}
+		case *ast.CallExpr: // s.setRepo(repo).
+			w.searchMethodCall(node, env)
+		}
+		return true
+	})
+}
+
+// bound returns the names an expression is bound to, a variable, or the field of a parameter struct such as p.Repo.
+func (w assignmentWalk) bound(expr ast.Expr, env bindings) []string {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return w.bound(e.X, env)
+	case *ast.StarExpr:
+		return w.bound(e.X, env)
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			return w.bound(e.X, env)
+		}
+	case *ast.Ident:
+		return env[getObject(w.info, e)]
 	case *ast.SelectorExpr:
-		x, ok := value.X.(*ast.Ident)
-		if !ok {
-			return
+		var names []string
+		for _, name := range w.bound(e.X, env) {
+			if _, ok := w.deps[name+"."+e.Sel.Name]; ok {
+				names = append(names, name+"."+e.Sel.Name)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// getDeps returns the names of the dependencies an expression is bound to.
+func (w assignmentWalk) getDeps(expr ast.Expr, env bindings) []string {
+	var names []string
+	for _, name := range w.bound(expr, env) {
+		if _, ok := w.deps[name]; ok {
+			names = append(names, name)
 		}
-		if _, ok := deps[x.String()]; ok {
-			for i := range deps[x.String()] {
-				deps[x.String()][i].Funcs = append(deps[x.String()][i].Funcs, value.Sel.String())
+	}
+	return names
+}
+
+// setField adds to the dependencies assigned to the field the methods the field declares, or the method of a
+// dependency assigned as a method value, such as repo.Find.
+func (w assignmentWalk) setField(field string, value ast.Expr, env bindings) {
+	if names := w.getDeps(value, env); len(names) > 0 {
+		for _, name := range names {
+			w.assigned[name] = true
+			for i := range w.deps[name] {
+				w.deps[name][i].Funcs = appendUnique(w.deps[name][i].Funcs, w.s.Fields[field].Methods...)
+				w.deps[name][i].Fields = appendUnique(w.deps[name][i].Fields, field)
 			}
 		}
-	case *ast.Ident:
-		if _, ok := deps[value.Name]; ok {
-			for i := range deps[value.Name] {
-				deps[value.Name][i].Funcs = s.Fields[kvExpr.Key.(*ast.Ident).Name].Methods
+		return
+	}
+	sel, ok := value.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	for _, name := range w.getDeps(sel.X, env) {
+		w.assigned[name] = true
+		for i := range w.deps[name] {
+			w.deps[name][i].Funcs = appendUnique(w.deps[name][i].Funcs, sel.Sel.Name)
+		}
+	}
+}
+
+// searchMethodCall follows the dependencies given to a method of the struct to the fields the method assigns them to.
+func (w assignmentWalk) searchMethodCall(call *ast.CallExpr, env bindings) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !w.isStruct(w.info.TypeOf(sel.X)) {
+		return
+	}
+	fn, ok := w.info.Uses[sel.Sel].(*types.Func)
+	if !ok {
+		return
+	}
+	src, ok := w.funcDecls[fn]
+	if !ok || w.visited[src.Decl] {
+		return
+	}
+
+	params := getParamNames(src.Decl.Type)
+	methodDeps := make(map[string][]dep)
+	varNames := make(map[string][]string) // method parameter name to dependency names.
+	for i, arg := range call.Args {
+		if i >= len(params) || params[i] == "" {
+			continue
+		}
+		names := w.getDeps(arg, env)
+		if len(names) == 0 {
+			continue
+		}
+		methodDeps[params[i]] = []dep{{VarName: params[i]}}
+		varNames[params[i]] = names
+	}
+	if len(methodDeps) == 0 {
+		return
+	}
+
+	methodAssigned := w.search(src.Decl, methodDeps, src.P.TypesInfo)
+	for param, names := range varNames {
+		if !methodAssigned[param] {
+			continue
+		}
+		for _, name := range names {
+			w.assigned[name] = true
+			for i := range w.deps[name] {
+				w.deps[name][i].Funcs = appendUnique(w.deps[name][i].Funcs, methodDeps[param][0].Funcs...)
+				w.deps[name][i].Fields = appendUnique(w.deps[name][i].Fields, methodDeps[param][0].Fields...)
 			}
 		}
 	}
 }
+
+// isStruct reports whether t is the struct searched, or a pointer to it.
+func (as *assignmentSearch) isStruct(t types.Type) bool {
+	return t != nil && types.Identical(derefType(t), as.s.ActualNamedType)
+}
+
+// getParamNames returns the name of each parameter, empty for the unnamed ones.
+func getParamNames(funcType *ast.FuncType) []string {
+	var names []string
+	for _, field := range funcType.Params.List {
+		if len(field.Names) == 0 {
+			names = append(names, "")
+			continue
+		}
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+	}
+	return names
+}
+
+// appendUnique appends the values missing from the slice.
+func appendUnique(slice []string, values ...string) []string {
+	for _, value := range values {
+		if !containsString(slice, value) {
+			slice = append(slice, value)
+		}
+	}
+	return slice
+}
@@ -0,0 +1,88 @@
+package parse
+
+import (
+	"fmt"
+	"go/ast"
+	"regexp"
+	"strings"
+)
+
+const (
+	// DefaultProviderNamePattern matches the functions named New*.
+	DefaultProviderNamePattern = "^New"
+
+	providerDirective = "//depgraph:provider"
+
+	// ProviderRuleDirective is the rule of the providers annotated with the //depgraph:provider directive.
+	ProviderRuleDirective = "directive"
+	providerRuleName      = "name:"
+	providerRuleMethod    = "method:"
+)
+
+// ProviderConfig configures how the functions are recognized as providers in ModeProvider.
+// Whatever the configuration, a function annotated with the //depgraph:provider directive is a provider.
+type ProviderConfig struct {
+	NamePatterns []string // regular expressions matched against the function names, DefaultProviderNamePattern if empty
+	Methods      bool     // whether the methods matching a name pattern are providers, such as the factory methods of a builder
+}
+
+// defaultProviderName matches the methods that are providers whatever the configuration, as the methods named New* always were.
+var defaultProviderName = regexp.MustCompile(DefaultProviderNamePattern)
+
+// providerMatcher decides whether a function is a provider.
+type providerMatcher struct {
+	patterns []*regexp.Regexp
+	methods  bool
+}
+
+func newProviderMatcher(c ProviderConfig) (providerMatcher, error) {
+	namePatterns := c.NamePatterns
+	if len(namePatterns) == 0 {
+		namePatterns = []string{DefaultProviderNamePattern}
+	}
+	m := providerMatcher{methods: c.Methods}
+	for _, pattern := range namePatterns {
+		r, err := regexp.Compile(pattern)
+		if err != nil {
+			return providerMatcher{}, fmt.Errorf("regexp.Compile:%w", err)
+		}
+		m.patterns = append(m.patterns, r)
+	}
+	return m, nil
+}
+
+// match returns the rule the function is matched with, empty if it is not a provider.
+// The rules are reported as "directive", "name:<pattern>" for functions and "method:<pattern>" for methods.
+func (m providerMatcher) match(funcdecl *ast.FuncDecl) string {
+	if hasProviderDirective(funcdecl.Doc) {
+		return ProviderRuleDirective
+	}
+	isMethod := funcdecl.Recv != nil
+	for _, pattern := range m.patterns {
+		if !pattern.MatchString(funcdecl.Name.Name) {
+			continue
+		}
+		if !isMethod {
+			return providerRuleName + pattern.String()
+		}
+		if m.methods {
+			return providerRuleMethod + pattern.String()
+		}
+	}
+	if isMethod && defaultProviderName.MatchString(funcdecl.Name.Name) {
+		return providerRuleMethod + DefaultProviderNamePattern
+	}
+	return ""
+}
+
+func hasProviderDirective(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, comment := range doc.List {
+		if strings.TrimSpace(comment.Text) == providerDirective {
+			return true
+		}
+	}
+	return false
+}
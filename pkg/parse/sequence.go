@@ -0,0 +1,275 @@
+package parse
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// StepKind is the kind of a step of a method body.
+type StepKind string
+
+const (
+	StepCall StepKind = "call" // A call of a dependency method, or of a method of the receiver
+	StepLoop StepKind = "loop" // A loop holding calls, in its single branch
+	StepAlt  StepKind = "alt"  // A conditional holding calls, each branch is a case
+	StepPar  StepKind = "par"  // A goroutine started with the go statement, in its single branch
+)
+
+// Step is a call made by a method, or a block of its body holding calls, in the order of the body.
+type Step struct {
+	Kind     StepKind
+	Callee   string   // The method called
+	Field    string   // The field of the receiver the dependency is called through, empty for a method of the receiver
+	Nodes    []*Node  // The dependencies the call is attributed to, empty for a method of the receiver
+	Branches []Branch // The branches of a block
+}
+
+// Branch is a branch of a block, the label is its condition.
+type Branch struct {
+	Label string
+	Steps []Step
+}
+
+// searchSequence returns the steps of the method, the calls on the receiver fields attributed to the edges, as done
+// by searchMethodCalls, and the calls of the methods of the receiver, nested in the loops and conditionals holding them.
+// The deferred calls are made at the end of the method, in the reverse order, and the goroutines are par blocks.
+func searchSequence(src funcSource, adjacencies []*Adj) []Step {
+	fields, ok := newReceiverFields(src.Decl, src.P.TypesInfo)
+	if !ok {
+		return nil
+	}
+	s := &sequenceSearch{fields: fields, adjacencies: adjacencies}
+	return s.body(src.Decl.Body)
+}
+
+type sequenceSearch struct {
+	fields      *receiverFields
+	adjacencies []*Adj
+	deferred    [][]Step // The steps deferred by the function being searched, in the order of the defer statements
+}
+
+// body returns the steps of a function body, followed by its deferred steps.
+func (s *sequenceSearch) body(body *ast.BlockStmt) []Step {
+	outer := s.deferred
+	s.deferred = nil
+	steps := s.stmts(body.List)
+	for i := len(s.deferred) - 1; i >= 0; i-- {
+		steps = append(steps, s.deferred[i]...)
+	}
+	s.deferred = outer
+	return steps
+}
+
+// deferredCall returns the steps made by evaluating the function and the arguments of a defer or go statement, and
+// the steps made by the call itself, later.
+func (s *sequenceSearch) deferredCall(call *ast.CallExpr) (now, later []Step) {
+	for _, arg := range call.Args {
+		now = append(now, s.node(arg)...)
+	}
+	if lit, ok := call.Fun.(*ast.FuncLit); ok {
+		return now, s.body(lit.Body)
+	}
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+		now = append(s.node(sel.X), now...)
+	}
+	if step, ok := s.call(call); ok {
+		later = append(later, step)
+	}
+	return now, later
+}
+
+func (s *sequenceSearch) stmts(list []ast.Stmt) []Step {
+	var steps []Step
+	for _, stmt := range list {
+		steps = append(steps, s.stmt(stmt)...)
+	}
+	return steps
+}
+
+func (s *sequenceSearch) stmt(stmt ast.Stmt) []Step {
+	switch st := stmt.(type) {
+	case *ast.BlockStmt:
+		return s.stmts(st.List)
+	case *ast.LabeledStmt:
+		return s.stmt(st.Stmt)
+	case *ast.IfStmt:
+		steps := s.node(st.Init)
+		steps = append(steps, s.node(st.Cond)...)
+		return append(steps, block(StepAlt, s.ifBranches(st, "if ", nil))...)
+	case *ast.ForStmt:
+		steps := s.node(st.Init)
+		label := "for"
+		if st.Cond != nil {
+			label += " " + types.ExprString(st.Cond)
+		}
+		body := append(s.node(st.Cond), s.stmts(st.Body.List)...)
+		body = append(body, s.node(st.Post)...)
+		return append(steps, block(StepLoop, []Branch{{Label: label, Steps: body}})...)
+	case *ast.RangeStmt:
+		steps := s.node(st.X)
+		label := "for range " + types.ExprString(st.X)
+		if st.Key != nil {
+			vars := types.ExprString(st.Key)
+			if st.Value != nil {
+				vars += ", " + types.ExprString(st.Value)
+			}
+			label = "for " + vars + " := range " + types.ExprString(st.X)
+		}
+		return append(steps, block(StepLoop, []Branch{{Label: label, Steps: s.stmts(st.Body.List)}})...)
+	case *ast.SwitchStmt:
+		steps := s.node(st.Init)
+		steps = append(steps, s.node(st.Tag)...)
+		prefix := "case "
+		if st.Tag != nil {
+			prefix = types.ExprString(st.Tag) + " == "
+		}
+		return append(steps, block(StepAlt, s.caseBranches(st.Body, prefix))...)
+	case *ast.TypeSwitchStmt:
+		steps := s.node(st.Init)
+		steps = append(steps, s.node(st.Assign)...)
+		return append(steps, block(StepAlt, s.caseBranches(st.Body, "case "))...)
+	case *ast.DeferStmt:
+		now, later := s.deferredCall(st.Call)
+		s.deferred = append(s.deferred, later)
+		return now
+	case *ast.GoStmt:
+		now, later := s.deferredCall(st.Call)
+		label := "go " + types.ExprString(st.Call)
+		if _, ok := st.Call.Fun.(*ast.FuncLit); ok {
+			label = "go func()"
+		}
+		return append(now, block(StepPar, []Branch{{Label: label, Steps: later}})...)
+	case *ast.SelectStmt:
+		var branches []Branch
+		for _, clause := range st.Body.List {
+			cc := clause.(*ast.CommClause)
+			label := "default"
+			if cc.Comm != nil {
+				label = "case " + stmtString(cc.Comm)
+			}
+			branches = append(branches, Branch{Label: label, Steps: append(s.node(cc.Comm), s.stmts(cc.Body)...)})
+		}
+		return block(StepAlt, branches)
+	}
+	return s.node(stmt)
+}
+
+// ifBranches returns a branch per condition of the if else chain, and one for the final else, the calls made to
+// evaluate a condition start its branch.
+func (s *sequenceSearch) ifBranches(st *ast.IfStmt, prefix string, conditionSteps []Step) []Branch {
+	branches := []Branch{{Label: prefix + types.ExprString(st.Cond), Steps: append(conditionSteps, s.stmts(st.Body.List)...)}}
+	switch e := st.Else.(type) {
+	case *ast.IfStmt:
+		// an else if condition is evaluated once the previous ones are false
+		branches = append(branches, s.ifBranches(e, "else if ", append(s.node(e.Init), s.node(e.Cond)...))...)
+	case *ast.BlockStmt:
+		branches = append(branches, Branch{Label: "else", Steps: s.stmts(e.List)})
+	}
+	return branches
+}
+
+func (s *sequenceSearch) caseBranches(body *ast.BlockStmt, prefix string) []Branch {
+	var branches []Branch
+	for _, clause := range body.List {
+		cc := clause.(*ast.CaseClause)
+		label := "default"
+		if cc.List != nil {
+			exprs := make([]string, 0, len(cc.List))
+			for _, expr := range cc.List {
+				exprs = append(exprs, types.ExprString(expr))
+			}
+			label = prefix + strings.Join(exprs, ", ")
+		}
+		var steps []Step
+		for _, expr := range cc.List {
+			steps = append(steps, s.node(expr)...)
+		}
+		branches = append(branches, Branch{Label: label, Steps: append(steps, s.stmts(cc.Body)...)})
+	}
+	return branches
+}
+
+// node returns the calls made by a statement or an expression, the arguments of a call are evaluated before it.
+func (s *sequenceSearch) node(n ast.Node) []Step {
+	if n == nil {
+		return nil
+	}
+	var steps []Step
+	var stack []ast.Node
+	ast.Inspect(n, func(n ast.Node) bool {
+		if n != nil {
+			stack = append(stack, n)
+			return true
+		}
+		// the node is left, its children are visited
+		n, stack = stack[len(stack)-1], stack[:len(stack)-1]
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			s.fields.track(node)
+		case *ast.CallExpr:
+			if step, ok := s.call(node); ok {
+				steps = append(steps, step)
+			}
+		}
+		return true
+	})
+	return steps
+}
+
+func (s *sequenceSearch) call(call *ast.CallExpr) (Step, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return Step{}, false
+	}
+	if s.fields.isReceiver(sel.X) {
+		if _, ok := s.fields.info.Uses[sel.Sel].(*types.Func); ok {
+			return Step{Kind: StepCall, Callee: sel.Sel.Name}, true
+		}
+		return Step{}, false
+	}
+	field, ok := s.fields.get(sel.X)
+	if !ok {
+		return Step{}, false
+	}
+	step := Step{Kind: StepCall, Callee: sel.Sel.Name, Field: field.Name}
+	for _, adj := range s.adjacencies {
+		if isInjectedIn(adj, field) {
+			step.Nodes = append(step.Nodes, adj.Node)
+		}
+	}
+	if len(step.Nodes) == 0 {
+		return Step{}, false
+	}
+	return step, true
+}
+
+// block returns the block, none when none of its branches holds a call.
+func block(kind StepKind, branches []Branch) []Step {
+	for _, branch := range branches {
+		if len(branch.Steps) > 0 {
+			return []Step{{Kind: kind, Branches: branches}}
+		}
+	}
+	return nil
+}
+
+func stmtString(stmt ast.Stmt) string {
+	switch st := stmt.(type) {
+	case *ast.ExprStmt:
+		return types.ExprString(st.X)
+	case *ast.SendStmt:
+		return types.ExprString(st.Chan) + " <- " + types.ExprString(st.Value)
+	case *ast.AssignStmt:
+		lhs := make([]string, 0, len(st.Lhs))
+		for _, expr := range st.Lhs {
+			lhs = append(lhs, types.ExprString(expr))
+		}
+		rhs := make([]string, 0, len(st.Rhs))
+		for _, expr := range st.Rhs {
+			rhs = append(rhs, types.ExprString(expr))
+		}
+		return strings.Join(lhs, ", ") + " " + st.Tok.String() + " " + strings.Join(rhs, ", ")
+	}
+	return ""
+}
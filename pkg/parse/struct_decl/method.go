@@ -7,10 +7,23 @@ import (
 )
 
 type Method struct {
-	TypFuc *types.Func
+	TypFuc    *types.Func
+	Name      string // The name of a method imported without type information
+	Signature string // The signature of a method imported without type information, returned by String
+}
+
+// GetName returns the name of the method.
+func (m Method) GetName() string {
+	if m.TypFuc == nil {
+		return m.Name
+	}
+	return m.TypFuc.Name()
 }
 
 func (m Method) String() string {
+	if m.TypFuc == nil {
+		return m.Signature
+	}
 	ret := m.tupleAsString(m.TypFuc.Type().(*types.Signature).Results())
 	if ret == "" {
 		return fmt.Sprintf("%s(%s)", m.TypFuc.Name(), m.tupleAsString(m.TypFuc.Type().(*types.Signature).Params()))
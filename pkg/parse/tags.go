@@ -0,0 +1,59 @@
+package parse
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const tagDirective = "//depgraph:tag"
+
+// searchTags sets on the nodes the tags of their struct, declared with a //depgraph:tag directive in its doc.
+// Several tags are separated by commas, such as //depgraph:tag domain,billing.
+func searchTags(pkgs []*packages.Package, graph *Graph) {
+	for _, p := range pkgs {
+		for _, f := range p.Syntax {
+			for _, decl := range f.Decls {
+				d, ok := decl.(*ast.GenDecl)
+				if !ok || d.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range d.Specs {
+					ts, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					node := graph.GetNodeByName(p.ID + "." + ts.Name.Name)
+					if node == nil {
+						continue
+					}
+					doc := ts.Doc
+					if doc == nil && len(d.Specs) == 1 {
+						doc = d.Doc
+					}
+					node.Tags = appendUnique(node.Tags, getTags(doc)...)
+				}
+			}
+		}
+	}
+}
+
+func getTags(doc *ast.CommentGroup) []string {
+	if doc == nil {
+		return nil
+	}
+	var tags []string
+	for _, comment := range doc.List {
+		if !strings.HasPrefix(comment.Text, tagDirective) {
+			continue
+		}
+		for _, tag := range strings.Split(strings.TrimPrefix(comment.Text, tagDirective), ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
@@ -0,0 +1,23 @@
+package assignment
+
+// Repository stores the users.
+type Repository struct{}
+
+func (r *Repository) Find() {}
+
+func (r *Repository) Save() {}
+
+// Cache keeps the recently found users.
+type Cache struct{}
+
+func (c *Cache) Get() {}
+
+// Clock tells the time.
+type Clock struct{}
+
+func (c *Clock) Now() {}
+
+// Logger writes the logs.
+type Logger struct{}
+
+func (l *Logger) Log() {}
@@ -0,0 +1,3 @@
+module testdata/assignment
+
+go 1.19
@@ -0,0 +1,94 @@
+package assignment
+
+import (
+	"errors"
+)
+
+// A has its fields assigned after being created.
+type A struct {
+	repo interface {
+		Find()
+	}
+}
+
+func NewA(repo *Repository) *A {
+	a := &A{}
+	a.repo = repo
+	return a
+}
+
+// B is returned as a value.
+type B struct {
+	cache interface {
+		Get()
+	}
+}
+
+func NewB(cache *Cache) B {
+	return B{
+		cache: cache,
+	}
+}
+
+// C is returned from an if block.
+type C struct {
+	repo interface {
+		Save()
+	}
+	clock interface {
+		Now()
+	}
+}
+
+func NewC(repo *Repository, clock *Clock) (*C, error) {
+	if repo != nil {
+		return &C{
+			repo:  repo,
+			clock: clock,
+		}, nil
+	}
+	return nil, errors.New("missing repository")
+}
+
+// D has its fields assigned by a helper method.
+type D struct {
+	logger interface {
+		Log()
+	}
+}
+
+func NewD(logger *Logger) *D {
+	d := &D{}
+	d.setLogger(logger)
+	return d
+}
+
+func (d *D) setLogger(logger *Logger) {
+	d.logger = logger
+}
+
+// E has its fields assigned from aliases of the parameters.
+type E struct {
+	repo interface {
+		Find()
+	}
+	cache interface {
+		Get()
+	}
+}
+
+func NewE(repo *Repository, cache *Cache) *E {
+	r := repo
+	var c *Cache
+	if cache != nil {
+		c = cache
+	}
+	e := &E{repo: r}
+	e.setCache(c)
+	return e
+}
+
+func (e *E) setCache(cache *Cache) {
+	c := cache
+	e.cache = c
+}
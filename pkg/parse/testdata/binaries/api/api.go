@@ -0,0 +1,24 @@
+package api
+
+import (
+	"net/http"
+
+	"testdata/binaries/store"
+)
+
+type Handler struct {
+	store  *store.Store
+	client *http.Client
+}
+
+func NewHandler(store *store.Store, client *http.Client) *Handler {
+	return &Handler{store: store, client: client}
+}
+
+func (h *Handler) Create(id string) error {
+	_, err := h.client.Get("https://payments/" + id)
+	if err != nil {
+		return err
+	}
+	return h.store.Save(id)
+}
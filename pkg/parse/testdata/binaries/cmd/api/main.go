@@ -0,0 +1,15 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+
+	"testdata/binaries/api"
+	"testdata/binaries/store"
+)
+
+func main() {
+	db, _ := sql.Open("mysql", "")
+	h := api.NewHandler(store.NewStore(db), http.DefaultClient)
+	_ = h.Create("1")
+}
@@ -0,0 +1,13 @@
+package main
+
+import (
+	"database/sql"
+
+	"testdata/binaries/store"
+	"testdata/binaries/worker"
+)
+
+func main() {
+	db, _ := sql.Open("mysql", "")
+	_ = worker.NewWorker(store.NewStore(db)).Run()
+}
@@ -0,0 +1,3 @@
+module testdata/binaries
+
+go 1.19
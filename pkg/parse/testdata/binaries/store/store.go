@@ -0,0 +1,16 @@
+package store
+
+import "database/sql"
+
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+func (s *Store) Save(id string) error {
+	_, err := s.db.Exec("INSERT INTO orders VALUES (?)", id)
+	return err
+}
@@ -0,0 +1,15 @@
+package worker
+
+import "testdata/binaries/store"
+
+type Worker struct {
+	store *store.Store
+}
+
+func NewWorker(store *store.Store) *Worker {
+	return &Worker{store: store}
+}
+
+func (w *Worker) Run() error {
+	return w.store.Save("retry")
+}
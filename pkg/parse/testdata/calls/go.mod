@@ -0,0 +1,3 @@
+module testdata/calls
+
+go 1.19
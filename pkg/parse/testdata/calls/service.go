@@ -0,0 +1,69 @@
+package calls
+
+// Repository stores the users.
+type Repository struct{}
+
+func (r *Repository) Find() {}
+
+func (r *Repository) Save() {}
+
+func (r *Repository) Delete() {}
+
+func NewRepository() *Repository {
+	return &Repository{}
+}
+
+// Notifier sends the notifications.
+type Notifier interface {
+	Notify()
+}
+
+// Mailer is a Notifier sending mails.
+type Mailer struct{}
+
+func (m *Mailer) Notify() {}
+
+func NewMailer() *Mailer {
+	return &Mailer{}
+}
+
+// Service manages the users.
+type Service struct {
+	repo interface {
+		Find()
+		Save()
+		Delete()
+	}
+	notifier Notifier
+}
+
+func NewService(repo *Repository, notifier Notifier) *Service {
+	return &Service{
+		repo:     repo,
+		notifier: notifier,
+	}
+}
+
+func (s *Service) Get() {
+	s.repo.Find()
+}
+
+func (s *Service) Create() {
+	repo := s.repo
+	repo.Save()
+	s.notifier.Notify()
+}
+
+func (s *Service) Remove() {
+	s.purge()
+}
+
+func (s *Service) purge() {
+	s.repo.Delete()
+	s.notify()
+}
+
+func (s *Service) notify() {
+	s.notifier.Notify()
+	s.purge()
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"testdata/composition/service"
+)
+
+func main() {
+	logger := service.NewLogger()
+	primary := service.NewService(service.NewSQLRepository(), logger)
+	fallback := service.NewService(service.NewMemoryRepository(), logger)
+	h := &service.Handler{}
+	h.Service = primary
+	run(h, fallback)
+}
+
+func run(h *service.Handler, fallback *service.Service) {
+	h.Serve()
+	fallback.Run()
+}
@@ -0,0 +1,3 @@
+module testdata/composition
+
+go 1.19
@@ -0,0 +1,59 @@
+package service
+
+// Repository finds the users.
+type Repository interface {
+	Find()
+}
+
+// SQLRepository is a Repository backed by a database.
+type SQLRepository struct{}
+
+func (r *SQLRepository) Find() {}
+
+func NewSQLRepository() *SQLRepository {
+	return &SQLRepository{}
+}
+
+// MemoryRepository is a Repository kept in memory.
+type MemoryRepository struct{}
+
+func (r *MemoryRepository) Find() {}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+// Logger writes the logs.
+type Logger struct{}
+
+func (l *Logger) Log() {}
+
+func NewLogger() *Logger {
+	return &Logger{}
+}
+
+// Service serves the users.
+type Service struct {
+	repo   Repository
+	logger interface {
+		Log()
+	}
+}
+
+func (s *Service) Run() {}
+
+func NewService(repo Repository, logger *Logger) *Service {
+	return &Service{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Handler exposes a Service.
+type Handler struct {
+	Service interface {
+		Run()
+	}
+}
+
+func (h *Handler) Serve() {}
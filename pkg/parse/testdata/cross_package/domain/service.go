@@ -0,0 +1,16 @@
+package domain
+
+type Repo struct{}
+
+func (r *Repo) Find(id string) string {
+	return id
+}
+
+// Service is built by the factory package.
+type Service struct {
+	Repo *Repo
+}
+
+func (s *Service) Get(id string) string {
+	return s.Repo.Find(id)
+}
@@ -0,0 +1,11 @@
+package factory
+
+import "testdata/cross_package/domain"
+
+func NewRepo() *domain.Repo {
+	return &domain.Repo{}
+}
+
+func NewService(repo *domain.Repo) *domain.Service {
+	return &domain.Service{Repo: repo}
+}
@@ -0,0 +1,3 @@
+module testdata/cross_package
+
+go 1.19
@@ -0,0 +1,13 @@
+module testdata/fx_sample
+
+go 1.19
+
+require (
+	go.uber.org/dig v1.17.0
+	go.uber.org/fx v1.20.0
+)
+
+replace (
+	go.uber.org/dig => ../stub/dig
+	go.uber.org/fx => ../stub/fx
+)
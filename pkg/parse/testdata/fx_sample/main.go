@@ -0,0 +1,76 @@
+package main
+
+import (
+	"testdata/fx_sample/user"
+
+	"go.uber.org/dig"
+	"go.uber.org/fx"
+)
+
+// Logger writes log lines.
+type Logger interface {
+	Log(msg string)
+}
+
+// StdLogger is a Logger writing to stdout.
+type StdLogger struct{}
+
+func NewStdLogger() *StdLogger {
+	return &StdLogger{}
+}
+
+func (l *StdLogger) Log(msg string) {}
+
+// Cache keeps recently served responses.
+type Cache struct{}
+
+func NewCache() *Cache {
+	return &Cache{}
+}
+
+func (c *Cache) Get(key string) string {
+	return ""
+}
+
+// Server serves every registered handler.
+type Server struct {
+	handlers []user.Handler
+	logger   Logger
+	cache    *Cache
+}
+
+type ServerParams struct {
+	fx.In
+
+	Handlers []user.Handler `group:"handlers"`
+	Logger   Logger
+	Cache    *Cache `name:"cache"`
+	Lc       fx.Lifecycle
+}
+
+func NewServer(p ServerParams) *Server {
+	return &Server{
+		handlers: p.Handlers,
+		logger:   p.Logger,
+		cache:    p.Cache,
+	}
+}
+
+func (s *Server) Start() {}
+
+func container() *dig.Container {
+	c := dig.New()
+	_ = c.Provide(NewCache, dig.Name("cache"))
+	return c
+}
+
+func main() {
+	fx.New(
+		user.Module,
+		fx.Provide(
+			NewServer,
+			fx.Annotate(NewStdLogger, fx.As(new(Logger))),
+		),
+		fx.Invoke(func(s *Server) { s.Start() }),
+	).Run()
+}
@@ -0,0 +1,11 @@
+package user
+
+import "go.uber.org/fx"
+
+var Module = fx.Module("user",
+	fx.Provide(
+		fx.Annotate(NewRepository, fx.ResultTags(`name:"primary"`)),
+		fx.Annotate(NewCreateHandler, fx.ResultTags(`group:"handlers"`)),
+		fx.Annotate(NewListHandler, fx.ParamTags(`name:"primary"`), fx.ResultTags(`group:"handlers"`)),
+	),
+)
@@ -0,0 +1,58 @@
+package user
+
+import "go.uber.org/fx"
+
+type Handler interface {
+	Pattern() string
+}
+
+// Repository stores users.
+type Repository struct{}
+
+func NewRepository() *Repository {
+	return &Repository{}
+}
+
+func (r *Repository) Find(id string) error {
+	return nil
+}
+
+// CreateHandler creates users.
+type CreateHandler struct {
+	repo *Repository
+}
+
+type CreateHandlerParams struct {
+	fx.In
+
+	Repo *Repository `name:"primary"`
+}
+
+func NewCreateHandler(p CreateHandlerParams) Handler {
+	return &CreateHandler{
+		repo: p.Repo,
+	}
+}
+
+func (h *CreateHandler) Pattern() string {
+	return "POST /users"
+}
+
+type finder interface {
+	Find(id string) error
+}
+
+// ListHandler lists users.
+type ListHandler struct {
+	repo finder
+}
+
+func NewListHandler(repo *Repository) Handler {
+	return &ListHandler{
+		repo: repo,
+	}
+}
+
+func (h *ListHandler) Pattern() string {
+	return "GET /users"
+}
@@ -0,0 +1,15 @@
+package interface_param
+
+type A struct {
+	repo UserRepository
+}
+
+func NewA(repo UserRepository) *A {
+	return &A{
+		repo: repo,
+	}
+}
+
+func (a A) Create(id string) error {
+	return a.repo.Find(id)
+}
@@ -0,0 +1,15 @@
+package interface_param
+
+type Notifier interface {
+	Notify(msg string)
+}
+
+type B struct {
+	n Notifier
+}
+
+func NewB(n Notifier) *B {
+	return &B{
+		n: n,
+	}
+}
@@ -0,0 +1,3 @@
+module testdata/interface_param
+
+go 1.19
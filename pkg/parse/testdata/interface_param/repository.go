@@ -0,0 +1,36 @@
+package interface_param
+
+type UserRepository interface {
+	Find(id string) error
+	Save(id string) error
+}
+
+// SQLRepository stores users in a database.
+type SQLRepository struct{}
+
+func NewSQLRepository() *SQLRepository {
+	return &SQLRepository{}
+}
+
+func (r SQLRepository) Find(id string) error {
+	return nil
+}
+
+func (r SQLRepository) Save(id string) error {
+	return nil
+}
+
+// MemoryRepository stores users in memory.
+type MemoryRepository struct{}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+func (r *MemoryRepository) Find(id string) error {
+	return nil
+}
+
+func (r *MemoryRepository) Save(id string) error {
+	return nil
+}
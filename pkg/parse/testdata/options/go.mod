@@ -0,0 +1,3 @@
+module testdata/options
+
+go 1.19
@@ -0,0 +1,52 @@
+package options
+
+// Repository stores the users.
+type Repository struct{}
+
+func (r *Repository) Find() {}
+
+func (r *Repository) Save() {}
+
+func NewRepository() *Repository {
+	return &Repository{}
+}
+
+// Logger writes the logs.
+type Logger interface {
+	Log()
+}
+
+// StdLogger is a Logger writing to stdout.
+type StdLogger struct{}
+
+func (l *StdLogger) Log() {}
+
+func NewStdLogger() *StdLogger {
+	return &StdLogger{}
+}
+
+// HandlerDeps holds the dependencies of the Handler.
+type HandlerDeps struct {
+	Repo   *Repository
+	Logger Logger
+	Name   string
+}
+
+// Handler handles the requests.
+type Handler struct {
+	repo interface {
+		Find()
+	}
+	logger Logger
+	name   string
+}
+
+func (h *Handler) Serve() {}
+
+func NewHandler(deps HandlerDeps) *Handler {
+	return &Handler{
+		repo:   deps.Repo,
+		logger: deps.Logger,
+		name:   deps.Name,
+	}
+}
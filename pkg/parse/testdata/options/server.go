@@ -0,0 +1,49 @@
+package options
+
+// Metrics counts the requests.
+type Metrics struct{}
+
+func (m *Metrics) Inc() {}
+
+// Server serves the handler.
+type Server struct {
+	handler interface {
+		Serve()
+	}
+	metrics interface {
+		Inc()
+	}
+	logger Logger
+	name   string
+}
+
+// Option configures the Server.
+type Option func(*Server)
+
+func WithMetrics(m *Metrics) Option {
+	return func(s *Server) {
+		s.metrics = m
+	}
+}
+
+func WithLogger(l Logger) Option {
+	return func(s *Server) {
+		s.logger = l
+	}
+}
+
+func WithName(name string) Option {
+	return func(s *Server) {
+		s.name = name
+	}
+}
+
+func NewServer(handler *Handler, opts ...Option) *Server {
+	s := &Server{
+		handler: handler,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
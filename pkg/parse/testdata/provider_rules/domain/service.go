@@ -0,0 +1,10 @@
+package domain
+
+// Service handles the items.
+type Service struct {
+	Repo interface {
+		Find()
+	}
+}
+
+func (s *Service) Handle() {}
@@ -0,0 +1,54 @@
+package factory
+
+import (
+	"testdata/provider_rules/domain"
+	"testdata/provider_rules/storage"
+)
+
+func NewService(repo *storage.Repository) *domain.Service {
+	return &domain.Service{
+		Repo: repo,
+	}
+}
+
+// OpenCache opens the shared cache.
+//
+//depgraph:provider
+func OpenCache() *storage.Cache {
+	return &storage.Cache{}
+}
+
+// Handler serves the items.
+type Handler struct {
+	service interface {
+		Handle()
+	}
+}
+
+// Builder builds the handlers.
+type Builder struct{}
+
+func (b *Builder) BuildHandler(service *domain.Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// Worker processes the items in the background.
+type Worker struct {
+	service interface {
+		Handle()
+	}
+}
+
+func (b *Builder) NewWorker(service *domain.Service) *Worker {
+	return &Worker{
+		service: service,
+	}
+}
+
+func CreateHandler(service *domain.Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
@@ -0,0 +1,3 @@
+module testdata/provider_rules
+
+go 1.19
@@ -0,0 +1,15 @@
+package storage
+
+// Repository stores the items.
+type Repository struct{}
+
+func (r *Repository) Find() {}
+
+func MakeRepository() *Repository {
+	return &Repository{}
+}
+
+// Cache keeps the recently found items.
+type Cache struct{}
+
+func (c *Cache) Get() {}
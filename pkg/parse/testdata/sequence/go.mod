@@ -0,0 +1,3 @@
+module testdata/sequence
+
+go 1.19
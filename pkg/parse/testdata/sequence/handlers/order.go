@@ -0,0 +1,35 @@
+package handlers
+
+import "testdata/sequence/orders"
+
+// Placer places the orders.
+type Placer interface {
+	Place(items []string, amount int) error
+}
+
+// OrderHandler serves the orders.
+type OrderHandler struct {
+	placer Placer
+	store  *orders.Store
+}
+
+func NewOrderHandler(placer *orders.Service, store *orders.Store) *OrderHandler {
+	return &OrderHandler{placer: placer, store: store}
+}
+
+func (h *OrderHandler) Create(kind string, items []string) error {
+	if h.store.Count() > 100 {
+		return nil
+	}
+	switch kind {
+	case "free":
+		h.log()
+	default:
+		return h.placer.Place(items, len(items))
+	}
+	return nil
+}
+
+func (h *OrderHandler) log() {
+	_ = h.store.Count()
+}
@@ -0,0 +1,67 @@
+package orders
+
+// Store stores the orders.
+type Store struct{}
+
+func (s *Store) Insert(item string) error { return nil }
+
+func (s *Store) Count() int { return 0 }
+
+func NewStore() *Store {
+	return &Store{}
+}
+
+// Payments charges the customers.
+type Payments struct{}
+
+func (p *Payments) Charge(amount int) error { return nil }
+
+func (p *Payments) Refund(amount int) {}
+
+func NewPayments() *Payments {
+	return &Payments{}
+}
+
+// Service places the orders.
+type Service struct {
+	store    *Store
+	payments *Payments
+}
+
+func NewService(store *Store, payments *Payments) *Service {
+	return &Service{store: store, payments: payments}
+}
+
+func (s *Service) Place(items []string, amount int) error {
+	for _, item := range items {
+		if err := s.store.Insert(item); err != nil {
+			return err
+		}
+	}
+	if err := s.payments.Charge(amount); err != nil {
+		s.payments.Refund(amount)
+		return err
+	}
+	return nil
+}
+
+func (s *Service) Retry(amount int) {
+	for i := 0; i < 3; i++ {
+		if s.payments.Charge(amount) == nil {
+			return
+		} else if i == 2 {
+			s.payments.Refund(amount)
+		} else {
+			s.store.Count()
+		}
+	}
+}
+
+func (s *Service) Cancel(amount int) {
+	defer s.store.Count()
+	defer func() {
+		s.payments.Refund(amount)
+	}()
+	go s.payments.Charge(s.store.Count())
+	_ = s.store.Insert("cancelled")
+}
@@ -0,0 +1,30 @@
+// Package dig is a minimal stand-in for go.uber.org/dig, it only declares the API used by the test data.
+package dig
+
+type In struct{}
+
+type Out struct{}
+
+type Container struct{}
+
+type ProvideOption interface{}
+
+func New() *Container {
+	return &Container{}
+}
+
+func (c *Container) Provide(constructor interface{}, opts ...ProvideOption) error {
+	return nil
+}
+
+func As(i ...interface{}) ProvideOption {
+	return nil
+}
+
+func Name(name string) ProvideOption {
+	return nil
+}
+
+func Group(group string) ProvideOption {
+	return nil
+}
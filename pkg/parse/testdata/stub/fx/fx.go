@@ -0,0 +1,64 @@
+// Package fx is a minimal stand-in for go.uber.org/fx, it only declares the API used by the test data.
+package fx
+
+import "go.uber.org/dig"
+
+type In = dig.In
+
+type Out = dig.Out
+
+type Option interface{}
+
+type Annotation interface{}
+
+type App struct{}
+
+type Lifecycle interface {
+	Append(hook interface{})
+}
+
+func New(opts ...Option) *App {
+	return &App{}
+}
+
+func (app *App) Run() {}
+
+func Provide(constructors ...interface{}) Option {
+	return nil
+}
+
+func Supply(values ...interface{}) Option {
+	return nil
+}
+
+func Invoke(funcs ...interface{}) Option {
+	return nil
+}
+
+func Options(opts ...Option) Option {
+	return nil
+}
+
+func Module(name string, opts ...Option) Option {
+	return nil
+}
+
+func Annotate(t interface{}, anns ...Annotation) interface{} {
+	return t
+}
+
+func As(interfaces ...interface{}) Annotation {
+	return nil
+}
+
+func Self() interface{} {
+	return nil
+}
+
+func ParamTags(tags ...string) Annotation {
+	return nil
+}
+
+func ResultTags(tags ...string) Annotation {
+	return nil
+}
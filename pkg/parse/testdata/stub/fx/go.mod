@@ -0,0 +1,5 @@
+module go.uber.org/fx
+
+go 1.19
+
+require go.uber.org/dig v1.17.0
@@ -0,0 +1,40 @@
+// Package wire is a minimal stand-in for github.com/google/wire, it only declares the API used by the test data.
+package wire
+
+type ProviderSet struct{}
+
+type Binding struct{}
+
+type StructProvider struct{}
+
+type StructFields struct{}
+
+type ProvidedValue struct{}
+
+func NewSet(...interface{}) ProviderSet {
+	return ProviderSet{}
+}
+
+func Build(...interface{}) string {
+	return "implementation not generated, run wire"
+}
+
+func Bind(iface, to interface{}) Binding {
+	return Binding{}
+}
+
+func Struct(structType interface{}, fieldNames ...string) StructProvider {
+	return StructProvider{}
+}
+
+func FieldsOf(structType interface{}, fieldNames ...string) StructFields {
+	return StructFields{}
+}
+
+func Value(interface{}) ProvidedValue {
+	return ProvidedValue{}
+}
+
+func InterfaceValue(typ interface{}, x interface{}) ProvidedValue {
+	return ProvidedValue{}
+}
@@ -0,0 +1,3 @@
+module testdata/tags
+
+go 1.19
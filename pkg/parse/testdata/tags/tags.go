@@ -0,0 +1,24 @@
+package tags
+
+// Store stores the users.
+//
+//depgraph:tag infrastructure, storage
+type Store struct{}
+
+func NewStore() *Store {
+	return &Store{}
+}
+
+func (s *Store) Save() {}
+
+type (
+	// Users manages the users.
+	//depgraph:tag domain
+	Users struct {
+		store *Store
+	}
+)
+
+func NewUsers(store *Store) *Users {
+	return &Users{store: store}
+}
@@ -1,3 +1,7 @@
 module testdata/wire_sample
 
 go 1.19
+
+require github.com/google/wire v0.5.0
+
+replace github.com/google/wire => ../stub/wire
@@ -0,0 +1,30 @@
+//go:build wireinject
+// +build wireinject
+
+// copied from https://github.com/google/wire/blob/main/_tutorial/wire.go for test purpose
+// Copyright 2018 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// The build tag makes sure the stub is not built in the final build.
+
+package main
+
+import "github.com/google/wire"
+
+// InitializeEvent creates an Event. It will error if the Event is staffed with
+// a grumpy greeter.
+func InitializeEvent(phrase string) (Event, error) {
+	wire.Build(NewEvent, NewGreeter, NewMessage)
+	return Event{}, nil
+}
@@ -0,0 +1,34 @@
+package wire_sets
+
+// Logger writes log lines.
+type Logger struct{}
+
+func (l *Logger) Log(msg string) {}
+
+// Clock tells the time.
+type Clock struct{}
+
+func (c Clock) Now() int64 {
+	return 0
+}
+
+// Service reads from the store.
+type Service struct {
+	Store  Store
+	Logger *Logger
+	Clock  Clock
+	cache  map[string]string
+}
+
+func (s *Service) Do() {}
+
+// Handler serves the service.
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) Serve() {}
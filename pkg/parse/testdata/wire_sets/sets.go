@@ -0,0 +1,15 @@
+package wire_sets
+
+import "github.com/google/wire"
+
+var StoreSet = wire.NewSet(
+	NewRedisStore,
+	wire.Bind(new(Store), new(*RedisStore)),
+)
+
+var ServiceSet = wire.NewSet(
+	StoreSet,
+	wire.Struct(new(Service), "*"),
+	wire.FieldsOf(new(*Config), "Logger"),
+	wire.Value(Clock{}),
+)
@@ -0,0 +1,28 @@
+package wire_sets
+
+type Store interface {
+	Get(key string) (string, error)
+}
+
+// Config holds the settings.
+type Config struct {
+	Addr   string
+	Logger *Logger
+}
+
+func (c *Config) Validate() error {
+	return nil
+}
+
+// RedisStore is a Store backed by redis.
+type RedisStore struct {
+	addr string
+}
+
+func NewRedisStore(cfg *Config) (*RedisStore, func(), error) {
+	return &RedisStore{addr: cfg.Addr}, func() {}, nil
+}
+
+func (s *RedisStore) Get(key string) (string, error) {
+	return "", nil
+}
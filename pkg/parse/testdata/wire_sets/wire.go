@@ -0,0 +1,11 @@
+//go:build wireinject
+// +build wireinject
+
+package wire_sets
+
+import "github.com/google/wire"
+
+func InitializeHandler(cfg *Config) (*Handler, func(), error) {
+	wire.Build(ServiceSet, NewHandler)
+	return nil, nil, nil
+}
@@ -0,0 +1,293 @@
+package parse
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+
+	mymap "github.com/emilien-puget/go-dependency-graph/pkg/map"
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse/struct_decl"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	wirePackagePath = "github.com/google/wire"
+	wireAllFields   = "*"
+)
+
+// wireParser collects the providers declared in wire provider sets and injectors.
+type wireParser struct {
+	*declIndex
+	visiting  map[*types.Var]bool
+	providers map[token.Pos]*diProvider
+	bindings  map[string]diBinding
+}
+
+// parseWire builds the graph from the providers given to wire.NewSet and wire.Build.
+// Each provider set, or injector for the providers given directly to wire.Build, is recorded as the module of its nodes.
+func parseWire(pkgs []*packages.Package, schema *AstSchema, t map[string]map[string]*struct_decl.Decl, index *declIndex) {
+	wp := &wireParser{
+		declIndex: index,
+		visiting:  make(map[*types.Var]bool),
+		providers: make(map[token.Pos]*diProvider),
+		bindings:  make(map[string]diBinding),
+	}
+
+	for _, p := range pkgs {
+		for _, f := range p.Syntax {
+			for _, decl := range f.Decls {
+				switch d := decl.(type) {
+				case *ast.GenDecl:
+					wp.handleVarDecl(d, p)
+				case *ast.FuncDecl:
+					wp.handleInjector(d, p)
+				}
+			}
+		}
+	}
+
+	providers := make([]*diProvider, 0, len(wp.providers))
+	for _, provider := range wp.providers {
+		providers = append(providers, provider)
+	}
+	bindings := make([]diBinding, 0, len(wp.bindings))
+	for _, key := range mymap.OrderedKeys(wp.bindings) {
+		bindings = append(bindings, wp.bindings[key])
+	}
+	buildDIGraph(pkgs, schema, t, wp.funcDecls, providers, bindings)
+}
+
+// handleVarDecl registers the provider sets declared as package level variables.
+func (wp *wireParser) handleVarDecl(d *ast.GenDecl, p *packages.Package) {
+	if d.Tok != token.VAR {
+		return
+	}
+	for _, spec := range d.Specs {
+		vs, ok := spec.(*ast.ValueSpec)
+		if !ok || len(vs.Values) != len(vs.Names) {
+			continue
+		}
+		for i, name := range vs.Names {
+			call, ok := vs.Values[i].(*ast.CallExpr)
+			if ok && isWireFunc(getCalledFunc(p.TypesInfo, call), "NewSet") {
+				wp.handleSet(call, p, name.Name)
+			}
+		}
+	}
+}
+
+// handleInjector registers the providers given to wire.Build in an injector.
+func (wp *wireParser) handleInjector(d *ast.FuncDecl, p *packages.Package) {
+	if d.Body == nil {
+		return
+	}
+	ast.Inspect(d.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || !isWireFunc(getCalledFunc(p.TypesInfo, call), "Build") {
+			return true
+		}
+		wp.handleSet(call, p, d.Name.Name)
+		return false
+	})
+}
+
+func (wp *wireParser) handleSet(call *ast.CallExpr, p *packages.Package, set string) {
+	for _, arg := range call.Args {
+		wp.handleSetArg(arg, p, set)
+	}
+}
+
+// handleSetArg registers a provider function, another provider set, or one of the wire declarations.
+func (wp *wireParser) handleSetArg(expr ast.Expr, p *packages.Package, set string) {
+	switch e := expr.(type) {
+	case *ast.CallExpr:
+		wp.handleSetCall(e, p, set)
+	case *ast.Ident, *ast.SelectorExpr:
+		switch obj := p.TypesInfo.Uses[getIdent(e)].(type) {
+		case *types.Func:
+			wp.addProvider(e, obj, p, set)
+		case *types.Var:
+			wp.followSet(obj)
+		}
+	}
+}
+
+// followSet registers the providers of a provider set variable, those belong to the set they are declared in.
+func (wp *wireParser) followSet(v *types.Var) {
+	if wp.visiting[v] {
+		return
+	}
+	src, ok := wp.varValues[v]
+	if !ok {
+		return
+	}
+	call, ok := src.Value.(*ast.CallExpr)
+	if !ok || !isWireFunc(getCalledFunc(src.P.TypesInfo, call), "NewSet") {
+		return
+	}
+	wp.visiting[v] = true
+	wp.handleSet(call, src.P, v.Name())
+	delete(wp.visiting, v)
+}
+
+func (wp *wireParser) handleSetCall(call *ast.CallExpr, p *packages.Package, set string) {
+	fn := getCalledFunc(p.TypesInfo, call)
+	switch {
+	case isWireFunc(fn, "NewSet"):
+		wp.handleSet(call, p, set)
+	case isWireFunc(fn, "Bind") && len(call.Args) == 2:
+		wp.addBinding(getNewType(p.TypesInfo, call.Args[0]), getNewType(p.TypesInfo, call.Args[1]))
+	case isWireFunc(fn, "Struct") && len(call.Args) > 0:
+		wp.addStructProvider(call, p, set)
+	case isWireFunc(fn, "FieldsOf") && len(call.Args) > 0:
+		wp.addFieldsProvider(call, p, set)
+	case isWireFunc(fn, "Value") && len(call.Args) == 1:
+		wp.addValue(call, p.TypesInfo.TypeOf(call.Args[0]), set)
+	case isWireFunc(fn, "InterfaceValue") && len(call.Args) == 2:
+		concrete := p.TypesInfo.TypeOf(call.Args[1])
+		wp.addValue(call, concrete, set)
+		wp.addBinding(getNewType(p.TypesInfo, call.Args[0]), concrete)
+	}
+}
+
+// addProvider registers a provider function, a function listed in several sets is registered once.
+func (wp *wireParser) addProvider(expr ast.Expr, fn *types.Func, p *packages.Package, set string) {
+	if _, ok := wp.providers[fn.Pos()]; ok {
+		return
+	}
+	sig := fn.Type().(*types.Signature)
+	provider := &diProvider{
+		Pos:    fn.Pos(),
+		Module: set,
+	}
+	for i := 0; i < sig.Params().Len(); i++ {
+		v := sig.Params().At(i)
+		provider.Inputs = append(provider.Inputs, diValue{Type: derefType(v.Type()), Index: i, VarName: v.Name()})
+	}
+	for i := 0; i < sig.Results().Len(); i++ {
+		v := sig.Results().At(i)
+		switch {
+		case isErrorType(v.Type()):
+		case isCleanupFunc(v.Type()):
+			provider.Cleanup = true
+		default:
+			provider.Outputs = append(provider.Outputs, diValue{Type: derefType(v.Type()), Index: i})
+		}
+	}
+	provider.Fn, provider.FnP = wp.getFuncDecl(expr, p)
+	wp.providers[provider.Pos] = provider
+}
+
+// addStructProvider registers a wire.Struct provider, the listed fields are its inputs.
+func (wp *wireParser) addStructProvider(call *ast.CallExpr, p *packages.Package, set string) {
+	named := getNamedType(getNewType(p.TypesInfo, call.Args[0]))
+	if named == nil {
+		return
+	}
+	s, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+	fieldNames := make(map[string]bool)
+	for _, arg := range call.Args[1:] {
+		fieldNames[getStringValue(p.TypesInfo, arg)] = true
+	}
+
+	provider := &diProvider{
+		Pos:     call.Pos(),
+		Module:  set,
+		Outputs: []diValue{{Type: named, Index: 0}},
+	}
+	for i := 0; i < s.NumFields(); i++ {
+		f := s.Field(i)
+		if !fieldNames[wireAllFields] && !fieldNames[f.Name()] {
+			continue
+		}
+		if reflect.StructTag(s.Tag(i)).Get("wire") == "-" {
+			continue
+		}
+		provider.Inputs = append(provider.Inputs, diValue{Type: derefType(f.Type()), Index: -1, Field: f.Name()})
+	}
+	wp.providers[provider.Pos] = provider
+}
+
+// addFieldsProvider registers a wire.FieldsOf provider, the listed fields are provided from the struct.
+func (wp *wireParser) addFieldsProvider(call *ast.CallExpr, p *packages.Package, set string) {
+	named := getNamedType(derefType(getNewType(p.TypesInfo, call.Args[0])))
+	if named == nil {
+		return
+	}
+	s, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+	fieldNames := make(map[string]bool)
+	for _, arg := range call.Args[1:] {
+		fieldNames[getStringValue(p.TypesInfo, arg)] = true
+	}
+
+	provider := &diProvider{
+		Pos:    call.Pos(),
+		Module: set,
+		Inputs: []diValue{{Type: named, Index: 0}},
+	}
+	for i := 0; i < s.NumFields(); i++ {
+		f := s.Field(i)
+		if fieldNames[f.Name()] {
+			provider.Outputs = append(provider.Outputs, diValue{Type: derefType(f.Type()), Index: -1, Field: f.Name()})
+		}
+	}
+	wp.providers[provider.Pos] = provider
+}
+
+// addValue registers a value given to wire.Value or wire.InterfaceValue.
+func (wp *wireParser) addValue(call *ast.CallExpr, t types.Type, set string) {
+	if t == nil {
+		return
+	}
+	wp.providers[call.Pos()] = &diProvider{
+		Pos:     call.Pos(),
+		Module:  set,
+		Outputs: []diValue{{Type: derefType(t), Index: 0}},
+	}
+}
+
+// addBinding registers a wire.Bind, the implementation can be given as a pointer.
+func (wp *wireParser) addBinding(iface, implementation types.Type) {
+	if iface == nil || implementation == nil {
+		return
+	}
+	implementation = derefType(implementation)
+	wp.bindings[getValueKey(iface, "")+getValueKey(implementation, "")] = diBinding{
+		Interface:      iface,
+		Implementation: implementation,
+	}
+}
+
+// getNewType returns T for an expression typed *T, such as new(T).
+func getNewType(info *types.Info, expr ast.Expr) types.Type {
+	ptr, ok := info.TypeOf(expr).(*types.Pointer)
+	if !ok {
+		return nil
+	}
+	return ptr.Elem()
+}
+
+// derefType returns the type t points to, wire values are identified regardless of being provided as pointers.
+func derefType(t types.Type) types.Type {
+	if ptr, ok := t.(*types.Pointer); ok {
+		return ptr.Elem()
+	}
+	return t
+}
+
+// isCleanupFunc reports whether t is a func() as returned by the providers needing a cleanup.
+func isCleanupFunc(t types.Type) bool {
+	sig, ok := t.Underlying().(*types.Signature)
+	return ok && sig.Params().Len() == 0 && sig.Results().Len() == 0
+}
+
+func isWireFunc(fn *types.Func, name string) bool {
+	return fn != nil && fn.Pkg() != nil && fn.Pkg().Path() == wirePackagePath && fn.Name() == name
+}
@@ -0,0 +1,140 @@
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+)
+
+// Violation is a dependency, or a node, breaking a rule.
+type Violation struct {
+	Position string `json:"position"` // The position of the provider of the node, file:line
+	Rule     string `json:"rule"`
+	From     string `json:"from"`
+	To       string `json:"to,omitempty"`
+	Message  string `json:"message"`
+}
+
+// Check returns the violations of the rules by the edges of the graph, the positions are relative to baseDir, if any.
+func Check(as parse.AstSchema, c Config, baseDir string) ([]Violation, error) {
+	compiled, err := compileRules(c)
+	if err != nil {
+		return nil, err
+	}
+	violations := make([]Violation, 0)
+	for _, r := range compiled {
+		for _, node := range as.Graph.GetNodesSortedByName() {
+			if node.External || !r.from.match(node, as.ModulePath) {
+				continue
+			}
+			violations = append(violations, r.check(as, node, baseDir)...)
+		}
+	}
+	return violations, nil
+}
+
+func (r rule) check(as parse.AstSchema, node *parse.Node, baseDir string) []Violation {
+	var violations []Violation
+	position := getPosition(node, baseDir)
+	seen := make(map[*parse.Node]bool)
+	for _, adj := range as.Graph.GetAdjacenciesSortedByName(node) {
+		if seen[adj.Node] {
+			continue
+		}
+		seen[adj.Node] = true
+		for _, m := range r.deny {
+			if m.match(adj.Node, as.ModulePath) {
+				violations = append(violations, Violation{Position: position, Rule: r.Name, From: node.Name, To: adj.Node.Name, Message: "denied by " + m.source.String()})
+				break
+			}
+		}
+		// a package may always depend on itself, the dependencies outside of the module are checked like any other
+		if len(r.allow) > 0 && adj.Node.PackageName != node.PackageName && !matchAny(r.allow, adj.Node, as.ModulePath) {
+			violations = append(violations, Violation{Position: position, Rule: r.Name, From: node.Name, To: adj.Node.Name, Message: "not allowed"})
+		}
+	}
+	if r.MaxFanOut > 0 && len(seen) > r.MaxFanOut {
+		violations = append(violations, Violation{Position: position, Rule: r.Name, From: node.Name, Message: fmt.Sprintf("fan-out %d exceeds %d", len(seen), r.MaxFanOut)})
+	}
+	return violations
+}
+
+func matchAny(matchers []matcher, node *parse.Node, modulePath string) bool {
+	for _, m := range matchers {
+		if m.match(node, modulePath) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m matcher) match(node *parse.Node, modulePath string) bool {
+	if m.pkg != nil && !m.pkg.MatchString(node.PackageName) && !m.pkg.MatchString(strings.TrimPrefix(strings.TrimPrefix(node.PackageName, modulePath), "/")) {
+		return false
+	}
+	if m.str != nil && !m.str.MatchString(node.StructName) {
+		return false
+	}
+	if m.tag != "" && !containsString(node.Tags, m.tag) {
+		return false
+	}
+	return true
+}
+
+// getPosition returns the position of the provider of the node, the file of its struct when unknown.
+func getPosition(node *parse.Node, baseDir string) string {
+	file, line := node.Provider.Filename, node.Provider.Line
+	if file == "" {
+		file, line = node.FilePath, 0
+	}
+	if baseDir != "" && file != "" {
+		if abs, err := filepath.Abs(baseDir); err == nil {
+			if rel, err := filepath.Rel(abs, file); err == nil {
+				file = rel
+			}
+		}
+	}
+	if line == 0 {
+		return file
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteText writes the violations, one per line.
+func WriteText(w io.Writer, violations []Violation) error {
+	for _, v := range violations {
+		edge := v.From
+		if v.To != "" {
+			edge += " -> " + v.To
+		}
+		_, err := fmt.Fprintf(w, "%s: %s: %s: %s\n", v.Position, v.Rule, edge, v.Message)
+		if err != nil {
+			return fmt.Errorf("fmt.Fprintf:%w", err)
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the violations as an indented JSON document.
+func WriteJSON(w io.Writer, violations []Violation) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(violations)
+	if err != nil {
+		return fmt.Errorf("encoder.Encode:%w", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package rules
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	as, err := parse.Parse("testdata/layers", nil)
+	require.NoError(t, err)
+	c, err := Load("testdata/layers/rules.yaml")
+	require.NoError(t, err)
+
+	violations, err := Check(as, c, "testdata/layers")
+	require.NoError(t, err)
+
+	assert.Equal(t, []Violation{
+		{Position: "domain/orders.go:12", Rule: "domain-isolation", From: "testdata/layers/domain.Orders", To: "testdata/layers/infrastructure.Postgres", Message: "denied by package **/infrastructure"},
+		{Position: "handlers/handler.go:14", Rule: "handlers-layer", From: "testdata/layers/handlers.OrderHandler", To: "testdata/layers/infrastructure.Postgres", Message: "not allowed"},
+	}, violations)
+}
+
+func TestCheck_json(t *testing.T) {
+	as, err := parse.Parse("testdata/layers", nil)
+	require.NoError(t, err)
+	c, err := Load("testdata/layers/rules.json")
+	require.NoError(t, err)
+
+	violations, err := Check(as, c, "testdata/layers")
+	require.NoError(t, err)
+
+	assert.Equal(t, []Violation{
+		{Position: "domain/orders.go:12", Rule: "core-isolation", From: "testdata/layers/domain.Orders", To: "testdata/layers/infrastructure.Postgres", Message: "denied by struct ^Postgres$"},
+	}, violations)
+}
+
+func TestCheck_max_fan_out(t *testing.T) {
+	as, err := parse.Parse("testdata/layers", nil)
+	require.NoError(t, err)
+
+	violations, err := Check(as, Config{Rules: []Rule{{Name: "fan-out", From: Selector{Package: "**"}, MaxFanOut: 1}}}, "testdata/layers")
+	require.NoError(t, err)
+
+	assert.Equal(t, []Violation{
+		{Position: "handlers/handler.go:14", Rule: "fan-out", From: "testdata/layers/handlers.OrderHandler", Message: "fan-out 2 exceeds 1"},
+	}, violations)
+}
+
+func TestCheck_allow(t *testing.T) {
+	graph := parse.NewGraph()
+	handler := &parse.Node{Name: "m/handlers.Handler", PackageName: "m/handlers", StructName: "Handler"}
+	helper := &parse.Node{Name: "m/handlers.Helper", PackageName: "m/handlers", StructName: "Helper"}
+	service := &parse.Node{Name: "m/service.Service", PackageName: "m/service", StructName: "Service"}
+	client := &parse.Node{Name: "net/http.Client", PackageName: "net/http", StructName: "Client", External: true}
+	graph.AddNode(handler)
+	graph.AddNode(helper)
+	graph.AddNode(service)
+	graph.AddNode(client)
+	graph.AddEdge(handler, &parse.Adj{Node: helper})
+	graph.AddEdge(handler, &parse.Adj{Node: service})
+	graph.AddEdge(handler, &parse.Adj{Node: client})
+	as := parse.AstSchema{ModulePath: "m", Graph: graph}
+
+	violations, err := Check(as, Config{Rules: []Rule{{Name: "layers", From: Selector{Package: "handlers"}, Allow: []Selector{{Package: "service"}}}}}, "")
+	require.NoError(t, err)
+	assert.Equal(t, []Violation{
+		{Rule: "layers", From: "m/handlers.Handler", To: "net/http.Client", Message: "not allowed"},
+	}, violations)
+
+	violations, err = Check(as, Config{Rules: []Rule{{Name: "layers", From: Selector{Package: "handlers"}, Allow: []Selector{{Package: "service"}, {Package: "net/**"}}}}}, "")
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+}
+
+func TestCheck_invalid(t *testing.T) {
+	as := parse.AstSchema{Graph: parse.NewGraph()}
+
+	_, err := Check(as, Config{Rules: []Rule{{From: Selector{Package: "a"}}}}, "")
+	assert.ErrorIs(t, err, ErrInvalidRule)
+
+	_, err = Check(as, Config{Rules: []Rule{{From: Selector{}, MaxFanOut: 1}}}, "")
+	assert.ErrorIs(t, err, ErrInvalidSelector)
+
+	_, err = Check(as, Config{Rules: []Rule{{From: Selector{Struct: "("}, MaxFanOut: 1}}}, "")
+	assert.ErrorIs(t, err, ErrInvalidSelector)
+}
+
+func TestWriteText(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := WriteText(buf, []Violation{
+		{Position: "a.go:3", Rule: "layers", From: "a.A", To: "b.B", Message: "not allowed"},
+		{Position: "a.go:3", Rule: "fan-out", From: "a.A", Message: "fan-out 2 exceeds 1"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `a.go:3: layers: a.A -> b.B: not allowed
+a.go:3: fan-out: a.A: fan-out 2 exceeds 1
+`, buf.String())
+}
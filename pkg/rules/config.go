@@ -0,0 +1,146 @@
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/glob"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrInvalidRule     = errors.New("invalid rule")
+	ErrInvalidSelector = errors.New("invalid selector")
+)
+
+// Config is the content of a rules file.
+type Config struct {
+	Rules []Rule `json:"rules" yaml:"rules"`
+}
+
+// Rule constrains the dependencies of the nodes matched by From.
+// An edge to a node matched by Deny is a violation, and when Allow is set, an edge to a node none of its selectors
+// matches is a violation, an edge within a package is always allowed. A node having more dependencies than MaxFanOut
+// is a violation, MaxFanOut is disabled when 0.
+type Rule struct {
+	Name      string     `json:"name" yaml:"name"`
+	From      Selector   `json:"from" yaml:"from"`
+	Allow     []Selector `json:"allow,omitempty" yaml:"allow,omitempty"`
+	Deny      []Selector `json:"deny,omitempty" yaml:"deny,omitempty"`
+	MaxFanOut int        `json:"max_fan_out,omitempty" yaml:"max_fan_out,omitempty"`
+}
+
+// Selector matches the nodes, every criterion set must match.
+type Selector struct {
+	Package string `json:"package,omitempty" yaml:"package,omitempty"` // A glob matched against the package path, or the path relative to the module, ** matches several path elements
+	Struct  string `json:"struct,omitempty" yaml:"struct,omitempty"`   // A regular expression matched against the struct name
+	Tag     string `json:"tag,omitempty" yaml:"tag,omitempty"`         // A tag declared with the //depgraph:tag directive
+}
+
+// Load reads a rules file, a .json file is read as JSON, any other as YAML.
+func Load(path string) (Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("os.ReadFile:%w", err)
+	}
+	c := Config{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(content, &c)
+		if err != nil {
+			return Config{}, fmt.Errorf("json.Unmarshal:%w", err)
+		}
+		return c, nil
+	}
+	err = yaml.Unmarshal(content, &c)
+	if err != nil {
+		return Config{}, fmt.Errorf("yaml.Unmarshal:%w", err)
+	}
+	return c, nil
+}
+
+// rule is a compiled Rule.
+type rule struct {
+	Rule
+	from  matcher
+	allow []matcher
+	deny  []matcher
+}
+
+// matcher is a compiled Selector.
+type matcher struct {
+	pkg    *regexp.Regexp
+	str    *regexp.Regexp
+	tag    string
+	source Selector
+}
+
+func compileRules(c Config) ([]rule, error) {
+	compiled := make([]rule, 0, len(c.Rules))
+	for i, r := range c.Rules {
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("rule %d", i+1)
+		}
+		if len(r.Allow) == 0 && len(r.Deny) == 0 && r.MaxFanOut == 0 {
+			return nil, fmt.Errorf("%s: no allow, deny or max_fan_out: %w", r.Name, ErrInvalidRule)
+		}
+		cr := rule{Rule: r}
+		var err error
+		cr.from, err = compileSelector(r.From)
+		if err != nil {
+			return nil, fmt.Errorf("%s: from: %w", r.Name, err)
+		}
+		for _, s := range r.Allow {
+			m, err := compileSelector(s)
+			if err != nil {
+				return nil, fmt.Errorf("%s: allow: %w", r.Name, err)
+			}
+			cr.allow = append(cr.allow, m)
+		}
+		for _, s := range r.Deny {
+			m, err := compileSelector(s)
+			if err != nil {
+				return nil, fmt.Errorf("%s: deny: %w", r.Name, err)
+			}
+			cr.deny = append(cr.deny, m)
+		}
+		compiled = append(compiled, cr)
+	}
+	return compiled, nil
+}
+
+func compileSelector(s Selector) (matcher, error) {
+	if s.Package == "" && s.Struct == "" && s.Tag == "" {
+		return matcher{}, fmt.Errorf("empty selector: %w", ErrInvalidSelector)
+	}
+	m := matcher{tag: s.Tag, source: s}
+	if s.Package != "" {
+		m.pkg = glob.Compile(s.Package)
+	}
+	if s.Struct != "" {
+		var err error
+		m.str, err = regexp.Compile(s.Struct)
+		if err != nil {
+			return matcher{}, fmt.Errorf("%s: %w", err, ErrInvalidSelector)
+		}
+	}
+	return m, nil
+}
+
+func (s Selector) String() string {
+	var criteria []string
+	if s.Package != "" {
+		criteria = append(criteria, "package "+s.Package)
+	}
+	if s.Struct != "" {
+		criteria = append(criteria, "struct "+s.Struct)
+	}
+	if s.Tag != "" {
+		criteria = append(criteria, "tag "+s.Tag)
+	}
+	return strings.Join(criteria, ", ")
+}
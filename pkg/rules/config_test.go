@@ -0,0 +1,25 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	c, err := Load("testdata/layers/rules.yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, Config{Rules: []Rule{
+		{Name: "domain-isolation", From: Selector{Package: "domain"}, Deny: []Selector{{Package: "**/infrastructure"}}},
+		{Name: "handlers-layer", From: Selector{Package: "handlers"}, Allow: []Selector{{Package: "service"}}},
+		{Name: "core-fan-out", From: Selector{Tag: "core"}, MaxFanOut: 2},
+		{Name: "services-fan-out", From: Selector{Struct: "Service$"}, MaxFanOut: 1},
+	}}, c)
+}
+
+func TestLoad_error(t *testing.T) {
+	_, err := Load("testdata/layers/missing.yaml")
+	assert.Error(t, err)
+}
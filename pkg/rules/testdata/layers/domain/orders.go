@@ -0,0 +1,18 @@
+package domain
+
+import "testdata/layers/infrastructure"
+
+// Orders holds the order rules.
+//
+//depgraph:tag core
+type Orders struct {
+	db *infrastructure.Postgres
+}
+
+func NewOrders(db *infrastructure.Postgres) *Orders {
+	return &Orders{db: db}
+}
+
+func (o *Orders) Validate() {
+	o.db.Exec()
+}
@@ -0,0 +1,3 @@
+module testdata/layers
+
+go 1.19
@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"testdata/layers/infrastructure"
+	"testdata/layers/service"
+)
+
+// OrderHandler serves the orders.
+type OrderHandler struct {
+	service *service.OrderService
+	db      *infrastructure.Postgres
+}
+
+func NewOrderHandler(service *service.OrderService, db *infrastructure.Postgres) *OrderHandler {
+	return &OrderHandler{service: service, db: db}
+}
+
+func (h *OrderHandler) Serve() {
+	h.service.Create()
+	h.db.Exec()
+}
@@ -0,0 +1,10 @@
+package infrastructure
+
+// Postgres stores the orders.
+type Postgres struct{}
+
+func NewPostgres() *Postgres {
+	return &Postgres{}
+}
+
+func (p *Postgres) Exec() {}
@@ -0,0 +1,16 @@
+package service
+
+import "testdata/layers/domain"
+
+// OrderService manages the orders.
+type OrderService struct {
+	orders *domain.Orders
+}
+
+func NewOrderService(orders *domain.Orders) *OrderService {
+	return &OrderService{orders: orders}
+}
+
+func (s *OrderService) Create() {
+	s.orders.Validate()
+}
@@ -0,0 +1,29 @@
+package snapshot
+
+import (
+	"bufio"
+	"context"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+)
+
+// Generator writes the snapshot of the graph, to compare or render it later.
+type Generator struct{}
+
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+func (g Generator) GetDefaultResultFileName() string {
+	return "graph.json"
+}
+
+// GenerateFromSchema writes the snapshot of the graph.
+func (g Generator) GenerateFromSchema(ctx context.Context, writer *bufio.Writer, s parse.AstSchema) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	return Write(writer, FromSchema(s))
+}
@@ -0,0 +1,347 @@
+// Package snapshot saves a parsed graph as a JSON document, and rebuilds a graph from it without parsing the project.
+//
+// The format is versioned, a reader supports the documents of its version and of the previous ones. The format is
+// described by the JSON schema in schema.json.
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse/struct_decl"
+)
+
+// Version is the version of the snapshot format, increased on every change.
+const Version = 1
+
+var ErrUnsupportedVersion = errors.New("unsupported snapshot version")
+
+// Snapshot is a serializable copy of a parsed graph.
+type Snapshot struct {
+	Version    int       `json:"version"`
+	ModulePath string    `json:"module_path"`
+	Packages   []Package `json:"packages,omitempty"`
+	Nodes      []Node    `json:"nodes"`
+	Edges      []Edge    `json:"edges"`
+	Binaries   []Binary  `json:"binaries,omitempty"`
+}
+
+// Binary is a main package of the module.
+type Binary struct {
+	Package  string   `json:"package"`
+	Packages []string `json:"packages"` // The packages of the module the binary is built from, the main package included
+}
+
+// Package is a package holding nodes.
+type Package struct {
+	Path     string `json:"path"`
+	External bool   `json:"external,omitempty"`
+}
+
+// Node is a node of the graph.
+type Node struct {
+	Name         string            `json:"name"` // The fully qualified name of the struct, package.struct
+	Package      string            `json:"package"`
+	Struct       string            `json:"struct"`
+	External     bool              `json:"external,omitempty"`
+	Doc          string            `json:"doc,omitempty"`
+	Position     *Position         `json:"position,omitempty"` // The declaration of the struct
+	Provider     *Position         `json:"provider,omitempty"` // The provider of the node
+	Module       string            `json:"module,omitempty"`   // The dependency injection module
+	Cleanup      bool              `json:"cleanup,omitempty"`
+	ProviderRule string            `json:"provider_rule,omitempty"`
+	Instances    int               `json:"instances,omitempty"`
+	Tags         []string          `json:"tags,omitempty"`
+	Methods      []Method          `json:"methods,omitempty"`
+	Sequences    map[string][]Step `json:"sequences,omitempty"` // The steps of the methods calling dependencies, by method
+}
+
+// Method is a method of a node.
+type Method struct {
+	Name      string    `json:"name"`
+	Signature string    `json:"signature"` // The name, the parameters and the results, such as Get(id string) (error)
+	Position  *Position `json:"position,omitempty"`
+}
+
+// Step is a call made by a method, or a block of its body holding calls.
+type Step struct {
+	Kind     string   `json:"kind"` // call, loop, alt or par
+	Callee   string   `json:"callee,omitempty"`
+	Field    string   `json:"field,omitempty"` // The field of the receiver the dependency is called through
+	Nodes    []string `json:"nodes,omitempty"` // The dependencies the call is attributed to, empty for a method of the receiver
+	Branches []Branch `json:"branches,omitempty"`
+}
+
+// Branch is a branch of a block, the label is its condition.
+type Branch struct {
+	Label string `json:"label"`
+	Steps []Step `json:"steps,omitempty"`
+}
+
+// Position is a position in the sources, the file is relative to the project directory, with forward slashes.
+type Position struct {
+	File   string `json:"file"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
+
+// Edge is a dependency of a node on another.
+type Edge struct {
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+	Funcs    []string `json:"funcs,omitempty"` // The methods of the dependency used
+	Via      string   `json:"via,omitempty"`   // The interface the dependency is injected through
+	Optional bool     `json:"optional,omitempty"`
+	Fields   []string `json:"fields,omitempty"`
+	Param    string   `json:"param,omitempty"`
+	Calls    []Call   `json:"calls,omitempty"`
+}
+
+// Call is a call of a method of the dependency by a method of the consumer.
+type Call struct {
+	Caller string `json:"caller"`
+	Callee string `json:"callee"`
+	Field  string `json:"field,omitempty"`
+}
+
+// FromSchema returns the snapshot of a parsed graph, the packages, the nodes and the edges are sorted by name.
+func FromSchema(as parse.AstSchema) Snapshot {
+	s := Snapshot{
+		Version:    Version,
+		ModulePath: as.ModulePath,
+		Packages:   []Package{},
+		Nodes:      []Node{},
+		Edges:      []Edge{},
+	}
+	packages := make(map[string]bool)
+	for _, node := range as.Graph.GetNodesSortedByName() {
+		if external, ok := packages[node.PackageName]; !ok || external {
+			packages[node.PackageName] = node.External
+		}
+		s.Nodes = append(s.Nodes, getNode(node, as.Dir))
+		for _, adj := range as.Graph.GetAdjacenciesSortedByName(node) {
+			s.Edges = append(s.Edges, getEdge(node, adj))
+		}
+	}
+	for _, binary := range as.Binaries {
+		s.Binaries = append(s.Binaries, Binary{Package: binary.Package, Packages: binary.Packages})
+	}
+	for path, external := range packages {
+		s.Packages = append(s.Packages, Package{Path: path, External: external})
+	}
+	sort.Slice(s.Packages, func(i, j int) bool {
+		return s.Packages[i].Path < s.Packages[j].Path
+	})
+	return s
+}
+
+func getNode(node *parse.Node, dir string) Node {
+	n := Node{
+		Name:         node.Name,
+		Package:      node.PackageName,
+		Struct:       node.StructName,
+		External:     node.External,
+		Doc:          node.Doc,
+		Module:       node.Module,
+		Cleanup:      node.Cleanup,
+		ProviderRule: node.ProviderRule,
+		Instances:    node.Instances,
+		Tags:         node.Tags,
+	}
+	var fset *token.FileSet
+	if node.P != nil {
+		fset = node.P.Fset
+	}
+	switch {
+	case fset != nil && node.ActualNamedType != nil:
+		n.Position = getPosition(fset.Position(node.ActualNamedType.Obj().Pos()), dir)
+	case node.FilePath != "":
+		n.Position = getPosition(token.Position{Filename: node.FilePath}, dir)
+	}
+	n.Provider = getPosition(node.Provider, dir)
+	for _, method := range node.Methods {
+		m := Method{Name: method.GetName(), Signature: method.String()}
+		if method.TypFuc != nil && fset != nil {
+			m.Position = getPosition(fset.Position(method.TypFuc.Pos()), dir)
+		}
+		n.Methods = append(n.Methods, m)
+	}
+	for method, steps := range node.Sequences {
+		if len(steps) == 0 {
+			continue
+		}
+		if n.Sequences == nil {
+			n.Sequences = make(map[string][]Step)
+		}
+		n.Sequences[method] = getSteps(steps)
+	}
+	return n
+}
+
+func getSteps(steps []parse.Step) []Step {
+	snapshotSteps := make([]Step, 0, len(steps))
+	for _, step := range steps {
+		s := Step{Kind: string(step.Kind), Callee: step.Callee, Field: step.Field}
+		for _, node := range step.Nodes {
+			s.Nodes = append(s.Nodes, node.Name)
+		}
+		for _, branch := range step.Branches {
+			s.Branches = append(s.Branches, Branch{Label: branch.Label, Steps: getSteps(branch.Steps)})
+		}
+		snapshotSteps = append(snapshotSteps, s)
+	}
+	return snapshotSteps
+}
+
+// getPosition returns the position relative to the dir, nil when unknown.
+func getPosition(p token.Position, dir string) *Position {
+	if p.Filename == "" {
+		return nil
+	}
+	file := p.Filename
+	if dir != "" {
+		if rel, err := filepath.Rel(dir, file); err == nil {
+			file = rel
+		}
+	}
+	return &Position{File: filepath.ToSlash(file), Line: p.Line, Column: p.Column}
+}
+
+func getEdge(node *parse.Node, adj *parse.Adj) Edge {
+	e := Edge{
+		From:     node.Name,
+		To:       adj.Node.Name,
+		Funcs:    adj.Func,
+		Via:      adj.Via,
+		Optional: adj.Optional,
+		Fields:   adj.Fields,
+		Param:    adj.Param,
+	}
+	for _, c := range adj.Calls {
+		e.Calls = append(e.Calls, Call{Caller: c.Caller, Callee: c.Callee, Field: c.Field})
+	}
+	return e
+}
+
+// ToSchema rebuilds the graph of the snapshot, the nodes have no type information, their methods only a name and a
+// signature.
+func (s Snapshot) ToSchema() parse.AstSchema {
+	graph := parse.NewGraph()
+	for _, n := range s.Nodes {
+		node := &parse.Node{
+			Name:         n.Name,
+			PackageName:  n.Package,
+			StructName:   n.Struct,
+			External:     n.External,
+			Doc:          n.Doc,
+			Module:       n.Module,
+			Cleanup:      n.Cleanup,
+			ProviderRule: n.ProviderRule,
+			Instances:    n.Instances,
+			Tags:         n.Tags,
+		}
+		if n.Position != nil {
+			node.FilePath = filepath.FromSlash(n.Position.File)
+		}
+		if n.Provider != nil {
+			node.Provider = token.Position{Filename: filepath.FromSlash(n.Provider.File), Line: n.Provider.Line, Column: n.Provider.Column}
+		}
+		for _, m := range n.Methods {
+			node.Methods = append(node.Methods, struct_decl.Method{Name: m.Name, Signature: m.Signature})
+		}
+		graph.AddNode(node)
+	}
+	for _, e := range s.Edges {
+		from, to := graph.GetNodeByName(e.From), graph.GetNodeByName(e.To)
+		if from == nil || to == nil {
+			continue
+		}
+		adj := &parse.Adj{
+			Node:     to,
+			Func:     append([]string(nil), e.Funcs...),
+			Via:      e.Via,
+			Optional: e.Optional,
+			Fields:   e.Fields,
+			Param:    e.Param,
+		}
+		for _, c := range e.Calls {
+			adj.Calls = append(adj.Calls, parse.Call{Caller: c.Caller, Callee: c.Callee, Field: c.Field})
+		}
+		graph.AddEdge(from, adj)
+	}
+	for _, n := range s.Nodes {
+		node := graph.GetNodeByName(n.Name)
+		for method, steps := range n.Sequences {
+			if node.Sequences == nil {
+				node.Sequences = make(map[string][]parse.Step)
+			}
+			node.Sequences[method] = toSteps(graph, steps)
+		}
+	}
+	as := parse.AstSchema{
+		ModulePath: s.ModulePath,
+		Graph:      graph,
+	}
+	for _, binary := range s.Binaries {
+		as.Binaries = append(as.Binaries, parse.Binary{Package: binary.Package, Packages: binary.Packages})
+	}
+	return as
+}
+
+func toSteps(graph *parse.Graph, steps []Step) []parse.Step {
+	schemaSteps := make([]parse.Step, 0, len(steps))
+	for _, step := range steps {
+		s := parse.Step{Kind: parse.StepKind(step.Kind), Callee: step.Callee, Field: step.Field}
+		for _, name := range step.Nodes {
+			if node := graph.GetNodeByName(name); node != nil {
+				s.Nodes = append(s.Nodes, node)
+			}
+		}
+		for _, branch := range step.Branches {
+			s.Branches = append(s.Branches, parse.Branch{Label: branch.Label, Steps: toSteps(graph, branch.Steps)})
+		}
+		schemaSteps = append(schemaSteps, s)
+	}
+	return schemaSteps
+}
+
+// Write writes the snapshot as an indented JSON document.
+func Write(w io.Writer, s Snapshot) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(s)
+	if err != nil {
+		return fmt.Errorf("encoder.Encode:%w", err)
+	}
+	return nil
+}
+
+// Read reads a snapshot, written with a supported version of the format.
+func Read(r io.Reader) (Snapshot, error) {
+	s := Snapshot{}
+	err := json.NewDecoder(r).Decode(&s)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("json.Decode:%w", err)
+	}
+	if s.Version < 1 || s.Version > Version {
+		return Snapshot{}, fmt.Errorf("%d: %w", s.Version, ErrUnsupportedVersion)
+	}
+	return s, nil
+}
+
+// ReadFile reads the snapshot of a file.
+func ReadFile(path string) (Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("os.Open:%w", err)
+	}
+	defer f.Close()
+	return Read(f)
+}
@@ -0,0 +1,131 @@
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/diagrams/c4"
+	"github.com/emilien-puget/go-dependency-graph/pkg/diagrams/mermaid"
+	"github.com/emilien-puget/go-dependency-graph/pkg/diagrams/sequence"
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateFromSchema(t *testing.T) {
+	as, err := parse.Parse("../parse/testdata/inter", nil)
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	w := bufio.NewWriter(buf)
+	err = NewGenerator().GenerateFromSchema(context.Background(), w, as)
+	require.NoError(t, err)
+	require.NoError(t, w.Flush())
+
+	expected, err := os.ReadFile("testdata/inter.json")
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestToSchema(t *testing.T) {
+	as, err := parse.Parse("../parse/testdata/inter", nil)
+	require.NoError(t, err)
+	s, err := ReadFile("testdata/inter.json")
+	require.NoError(t, err)
+	imported := s.ToSchema()
+
+	exported := FromSchema(imported)
+	assert.Equal(t, s.Packages, exported.Packages)
+	assert.Equal(t, s.Edges, exported.Edges)
+	require.Len(t, exported.Nodes, len(s.Nodes))
+	for i, node := range s.Nodes {
+		require.Len(t, exported.Nodes[i].Methods, len(node.Methods))
+		for j, method := range node.Methods {
+			assert.Equal(t, method.Name, exported.Nodes[i].Methods[j].Name)
+			assert.Equal(t, method.Signature, exported.Nodes[i].Methods[j].Signature)
+		}
+	}
+
+	for name, generator := range map[string]interface {
+		GenerateFromSchema(context.Context, *bufio.Writer, parse.AstSchema) error
+	}{
+		"c4":      c4.NewGenerator(),
+		"mermaid": mermaid.NewGenerator(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, generate(t, generator.GenerateFromSchema, as), generate(t, generator.GenerateFromSchema, imported))
+		})
+	}
+}
+
+func generate(t *testing.T, fn func(context.Context, *bufio.Writer, parse.AstSchema) error, as parse.AstSchema) string {
+	buf := &bytes.Buffer{}
+	w := bufio.NewWriter(buf)
+	require.NoError(t, fn(context.Background(), w, as))
+	require.NoError(t, w.Flush())
+	return buf.String()
+}
+
+func TestToSchema_sequences(t *testing.T) {
+	as, err := parse.Parse("../parse/testdata/sequence", nil)
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, Write(buf, FromSchema(as)))
+	s, err := Read(buf)
+	require.NoError(t, err)
+	imported := s.ToSchema()
+
+	handler := imported.Graph.GetNodeByName("testdata/sequence/handlers.OrderHandler")
+	require.NotNil(t, handler)
+	require.NotEmpty(t, handler.Sequences["Create"])
+
+	generator := sequence.NewPlantUMLGenerator("handlers.OrderHandler.Create", 0)
+	assert.Equal(t, generate(t, generator.GenerateFromSchema, as), generate(t, generator.GenerateFromSchema, imported))
+}
+
+func TestToSchema_binaries(t *testing.T) {
+	as, err := parse.Parse("../parse/testdata/binaries", nil)
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, Write(buf, FromSchema(as)))
+	s, err := Read(buf)
+	require.NoError(t, err)
+	imported := s.ToSchema()
+
+	assert.Equal(t, as.Binaries, imported.Binaries)
+	generator := c4.NewContainerGenerator(c4.Metadata{})
+	assert.Equal(t, generate(t, generator.GenerateFromSchema, as), generate(t, generator.GenerateFromSchema, imported))
+}
+
+func TestRead_minimal(t *testing.T) {
+	s, err := Read(strings.NewReader(`{
+  "version": 1,
+  "module_path": "mod",
+  "nodes": [
+    {"name": "mod.A", "package": "mod", "struct": "A"},
+    {"name": "mod.B", "package": "mod", "struct": "B"}
+  ],
+  "edges": [
+    {"from": "mod.A", "to": "mod.B", "funcs": ["Get"]}
+  ]
+}`))
+	require.NoError(t, err)
+
+	as := s.ToSchema()
+	a := as.Graph.GetNodeByName("mod.A")
+	require.NotNil(t, a)
+	require.Len(t, as.Graph.GetAdjacency(a), 1)
+	assert.Equal(t, "mod.B", as.Graph.GetAdjacency(a)[0].Node.Name)
+	assert.Equal(t, []string{"Get"}, as.Graph.GetAdjacency(a)[0].Func)
+}
+
+func TestRead_unsupportedVersion(t *testing.T) {
+	_, err := Read(strings.NewReader(`{"version": 2}`))
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+}
@@ -0,0 +1,3 @@
+module testdata/service
+
+go 1.19
@@ -0,0 +1,55 @@
+package service
+
+// Repository stores the users.
+type Repository struct{}
+
+func (r *Repository) Find() {}
+
+func (r *Repository) Save() {}
+
+func (r *Repository) Delete() {}
+
+func NewRepository() *Repository {
+	return &Repository{}
+}
+
+// Cache caches the users.
+type Cache struct{}
+
+func (c *Cache) Get() {}
+
+func NewCache() *Cache {
+	return &Cache{}
+}
+
+// Audit records the changes, nothing consumes it.
+type Audit struct{}
+
+func (a *Audit) Record() {}
+
+func NewAudit() *Audit {
+	return &Audit{}
+}
+
+// Service manages the users.
+type Service struct {
+	repo interface {
+		Find()
+		Save()
+		Delete()
+	}
+	cache interface {
+		Get()
+	}
+}
+
+func NewService(repo *Repository, cache *Cache) *Service {
+	return &Service{
+		repo:  repo,
+		cache: cache,
+	}
+}
+
+func (s *Service) Get() {
+	s.repo.Find()
+}
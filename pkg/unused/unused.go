@@ -0,0 +1,244 @@
+package unused
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"go/types"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+)
+
+// Kind is the kind of finding.
+type Kind string
+
+const (
+	KindUnusedDependency      Kind = "unused_dependency"
+	KindUnusedInterfaceMethod Kind = "unused_interface_method"
+	KindUnconsumedProvider    Kind = "unconsumed_provider"
+)
+
+const mainPackage = "main"
+
+// Position is a position in the source code.
+type Position struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+func (p Position) String() string {
+	if p.Line == 0 {
+		return p.File
+	}
+	return fmt.Sprintf("%s:%d", p.File, p.Line)
+}
+
+// Finding is an unused element of the graph.
+type Finding struct {
+	Position
+	Kind       Kind   `json:"kind"`
+	Node       string `json:"node"`
+	Dependency string `json:"dependency,omitempty"`
+	Field      string `json:"field,omitempty"`
+	Method     string `json:"method,omitempty"`
+}
+
+// Report lists the findings by kind.
+type Report struct {
+	UnusedDependencies     []Finding `json:"unused_dependencies"`
+	UnusedInterfaceMethods []Finding `json:"unused_interface_methods"`
+	UnconsumedProviders    []Finding `json:"unconsumed_providers"`
+}
+
+// Len returns the number of findings.
+func (r Report) Len() int {
+	return len(r.UnusedDependencies) + len(r.UnusedInterfaceMethods) + len(r.UnconsumedProviders)
+}
+
+// Config configures the analysis.
+type Config struct {
+	// EntryPoints match the name of the nodes not consumed on purpose, when empty the nodes having dependencies are entry points.
+	EntryPoints []*regexp.Regexp
+	// BaseDir the positions are made relative to, if any.
+	BaseDir string
+}
+
+// Analyze searches the graph for the dependencies never called, the interface methods never called, and the providers never consumed.
+// It relies on the calls recorded on the edges, a struct whose methods could not be read is not reported.
+func Analyze(as parse.AstSchema, c Config) Report {
+	r := Report{
+		UnusedDependencies:     []Finding{},
+		UnusedInterfaceMethods: []Finding{},
+		UnconsumedProviders:    []Finding{},
+	}
+	for _, node := range as.Graph.GetNodesSortedByName() {
+		if node.External || node.ActualNamedType == nil {
+			continue
+		}
+		adjacencies := as.Graph.GetAdjacenciesSortedByName(node)
+		r.UnusedDependencies = append(r.UnusedDependencies, searchUnusedDependencies(node, adjacencies, c)...)
+		r.UnusedInterfaceMethods = append(r.UnusedInterfaceMethods, searchUnusedInterfaceMethods(node, adjacencies, c)...)
+		if len(node.InboundEdges) == 0 && !isEntryPoint(node, len(adjacencies) > 0, c) {
+			r.UnconsumedProviders = append(r.UnconsumedProviders, Finding{
+				Position: getPosition(node, node.ActualNamedType.Obj().Pos(), c),
+				Kind:     KindUnconsumedProvider,
+				Node:     node.Name,
+			})
+		}
+	}
+	return r
+}
+
+// searchUnusedDependencies returns the dependencies of the node on which no method is called.
+func searchUnusedDependencies(node *parse.Node, adjacencies []*parse.Adj, c Config) []Finding {
+	var findings []Finding
+	for _, adj := range adjacencies {
+		if len(adj.Calls) > 0 {
+			continue
+		}
+		f := Finding{
+			Position:   getPosition(node, node.ActualNamedType.Obj().Pos(), c),
+			Kind:       KindUnusedDependency,
+			Node:       node.Name,
+			Dependency: adj.Node.Name,
+		}
+		if len(adj.Fields) > 0 {
+			f.Field = adj.Fields[0]
+			if field := getField(node, f.Field); field != nil {
+				f.Position = getPosition(node, field.Pos(), c)
+			}
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// searchUnusedInterfaceMethods returns the methods of the interface fields the node never calls.
+// A field on which no method is called at all is left out, its dependency is already unused.
+func searchUnusedInterfaceMethods(node *parse.Node, adjacencies []*parse.Adj, c Config) []Finding {
+	called := make(map[string]map[string]bool)
+	for _, adj := range adjacencies {
+		for _, call := range adj.Calls {
+			if called[call.Field] == nil {
+				called[call.Field] = make(map[string]bool)
+			}
+			called[call.Field][call.Callee] = true
+		}
+	}
+
+	s, ok := node.ActualNamedType.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	var findings []Finding
+	for i := 0; i < s.NumFields(); i++ {
+		field := s.Field(i)
+		iface, ok := field.Type().Underlying().(*types.Interface)
+		if !ok || len(called[field.Name()]) == 0 {
+			continue
+		}
+		methods := make([]string, 0, iface.NumMethods())
+		for j := 0; j < iface.NumMethods(); j++ {
+			methods = append(methods, iface.Method(j).Name())
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			if called[field.Name()][method] {
+				continue
+			}
+			findings = append(findings, Finding{
+				Position: getPosition(node, field.Pos(), c),
+				Kind:     KindUnusedInterfaceMethod,
+				Node:     node.Name,
+				Field:    field.Name(),
+				Method:   method,
+			})
+		}
+	}
+	return findings
+}
+
+// isEntryPoint reports whether the node is not expected to be consumed.
+func isEntryPoint(node *parse.Node, hasDependencies bool, c Config) bool {
+	if node.P != nil && node.P.Name == mainPackage {
+		return true
+	}
+	if len(c.EntryPoints) == 0 {
+		return hasDependencies
+	}
+	for _, entryPoint := range c.EntryPoints {
+		if entryPoint.MatchString(node.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func getField(node *parse.Node, name string) *types.Var {
+	s, ok := node.ActualNamedType.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	for i := 0; i < s.NumFields(); i++ {
+		if s.Field(i).Name() == name {
+			return s.Field(i)
+		}
+	}
+	return nil
+}
+
+// getPosition returns the position of pos, the file of the node is used when the position is unknown.
+func getPosition(node *parse.Node, pos token.Pos, c Config) Position {
+	p := Position{File: node.FilePath}
+	if node.P != nil && node.P.Fset != nil && pos.IsValid() {
+		position := node.P.Fset.Position(pos)
+		p = Position{File: position.Filename, Line: position.Line}
+	}
+	if c.BaseDir != "" && p.File != "" {
+		if baseDir, err := filepath.Abs(c.BaseDir); err == nil {
+			if rel, err := filepath.Rel(baseDir, p.File); err == nil {
+				p.File = rel
+			}
+		}
+	}
+	return p
+}
+
+// WriteText writes the report, one finding per line.
+func WriteText(w io.Writer, r Report) error {
+	for _, f := range r.UnusedDependencies {
+		line := fmt.Sprintf("%s: %s: %s is never called", f.Position, f.Node, f.Dependency)
+		if f.Field != "" {
+			line = fmt.Sprintf("%s: %s: %s injected in %s is never called", f.Position, f.Node, f.Dependency, f.Field)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return fmt.Errorf("fmt.Fprintln:%w", err)
+		}
+	}
+	for _, f := range r.UnusedInterfaceMethods {
+		if _, err := fmt.Fprintf(w, "%s: %s: method %s of %s is never called\n", f.Position, f.Node, f.Method, f.Field); err != nil {
+			return fmt.Errorf("fmt.Fprintf:%w", err)
+		}
+	}
+	for _, f := range r.UnconsumedProviders {
+		if _, err := fmt.Fprintf(w, "%s: %s is never consumed\n", f.Position, f.Node); err != nil {
+			return fmt.Errorf("fmt.Fprintf:%w", err)
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the report as an indented JSON document.
+func WriteJSON(w io.Writer, r Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(r)
+	if err != nil {
+		return fmt.Errorf("encoder.Encode:%w", err)
+	}
+	return nil
+}
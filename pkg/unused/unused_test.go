@@ -0,0 +1,83 @@
+package unused
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyze(t *testing.T) {
+	as, err := parse.Parse("testdata/service", nil)
+	require.NoError(t, err)
+
+	r := Analyze(as, Config{BaseDir: "testdata/service"})
+
+	assert.Equal(t, []Finding{
+		{Position: Position{File: "service.go", Line: 41}, Kind: KindUnusedDependency, Node: "testdata/service.Service", Dependency: "testdata/service.Cache", Field: "cache"},
+	}, r.UnusedDependencies)
+	assert.Equal(t, []Finding{
+		{Position: Position{File: "service.go", Line: 36}, Kind: KindUnusedInterfaceMethod, Node: "testdata/service.Service", Field: "repo", Method: "Delete"},
+		{Position: Position{File: "service.go", Line: 36}, Kind: KindUnusedInterfaceMethod, Node: "testdata/service.Service", Field: "repo", Method: "Save"},
+	}, r.UnusedInterfaceMethods)
+	assert.Equal(t, []Finding{
+		{Position: Position{File: "service.go", Line: 26}, Kind: KindUnconsumedProvider, Node: "testdata/service.Audit"},
+	}, r.UnconsumedProviders)
+	assert.Equal(t, 4, r.Len())
+}
+
+func TestAnalyze_entryPoints(t *testing.T) {
+	as, err := parse.Parse("testdata/service", nil)
+	require.NoError(t, err)
+
+	r := Analyze(as, Config{
+		EntryPoints: []*regexp.Regexp{regexp.MustCompile(`\.Audit$`)},
+		BaseDir:     "testdata/service",
+	})
+
+	assert.Equal(t, []Finding{
+		{Position: Position{File: "service.go", Line: 35}, Kind: KindUnconsumedProvider, Node: "testdata/service.Service"},
+	}, r.UnconsumedProviders)
+}
+
+func TestWriteText(t *testing.T) {
+	as, err := parse.Parse("testdata/service", nil)
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	err = WriteText(buf, Analyze(as, Config{BaseDir: "testdata/service"}))
+	require.NoError(t, err)
+
+	assert.Equal(t, `service.go:41: testdata/service.Service: testdata/service.Cache injected in cache is never called
+service.go:36: testdata/service.Service: method Delete of repo is never called
+service.go:36: testdata/service.Service: method Save of repo is never called
+service.go:26: testdata/service.Audit is never consumed
+`, buf.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := WriteJSON(buf, Report{
+		UnusedDependencies:     []Finding{},
+		UnusedInterfaceMethods: []Finding{},
+		UnconsumedProviders:    []Finding{{Position: Position{File: "a.go", Line: 3}, Kind: KindUnconsumedProvider, Node: "a.A"}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `{
+  "unused_dependencies": [],
+  "unused_interface_methods": [],
+  "unconsumed_providers": [
+    {
+      "file": "a.go",
+      "line": 3,
+      "kind": "unconsumed_provider",
+      "node": "a.A"
+    }
+  ]
+}
+`, buf.String())
+}
@@ -0,0 +1,129 @@
+package why
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+)
+
+var ErrNodeNotFound = errors.New("node not found")
+
+// Hop is an edge of a path, annotated with the methods of the dependency used.
+type Hop struct {
+	From  string   `json:"from"`
+	To    string   `json:"to"`
+	Funcs []string `json:"funcs,omitempty"`
+	Via   string   `json:"via,omitempty"`
+}
+
+// Path is a sequence of hops.
+type Path struct {
+	Hops []Hop `json:"hops"`
+}
+
+// Query returns the simple paths from one node to another, shortest first, the k shortest when k is positive.
+// The nodes are looked up by their fully qualified name, or by their name relative to the module.
+func Query(as parse.AstSchema, from, to string, k int) ([]parse.Path, error) {
+	fromNode, err := getNode(as, from)
+	if err != nil {
+		return nil, err
+	}
+	toNode, err := getNode(as, to)
+	if err != nil {
+		return nil, err
+	}
+	return as.Graph.GetPaths(fromNode, toNode, k), nil
+}
+
+func getNode(as parse.AstSchema, name string) (*parse.Node, error) {
+	if node := as.Graph.GetNodeByName(name); node != nil {
+		return node, nil
+	}
+	if node := as.Graph.GetNodeByName(as.ModulePath + "/" + name); node != nil {
+		return node, nil
+	}
+	if node := as.Graph.GetNodeByName(as.ModulePath + "." + name); node != nil {
+		return node, nil
+	}
+	return nil, fmt.Errorf("%s: %w", name, ErrNodeNotFound)
+}
+
+// GetPaths returns the paths annotated with the methods used at each hop.
+func GetPaths(paths []parse.Path) []Path {
+	result := make([]Path, 0, len(paths))
+	for _, path := range paths {
+		p := Path{Hops: make([]Hop, 0, len(path))}
+		for _, hop := range path {
+			p.Hops = append(p.Hops, Hop{
+				From:  hop.From.Name,
+				To:    hop.Adj.Node.Name,
+				Funcs: hop.Adj.Func,
+				Via:   hop.Adj.Via,
+			})
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+// GetSchema returns a schema holding only the nodes and the edges of the paths, to draw a focused diagram.
+func GetSchema(as parse.AstSchema, paths []parse.Path) parse.AstSchema {
+	return parse.AstSchema{
+		ModulePath: as.ModulePath,
+		Graph:      as.Graph.GetPathsGraph(paths),
+	}
+}
+
+// WriteText writes each path followed by its hops.
+func WriteText(w io.Writer, paths []Path) error {
+	if len(paths) == 0 {
+		_, err := fmt.Fprintln(w, "no path found")
+		if err != nil {
+			return fmt.Errorf("fmt.Fprintln:%w", err)
+		}
+		return nil
+	}
+	for i, p := range paths {
+		names := []string{p.Hops[0].From}
+		for _, hop := range p.Hops {
+			names = append(names, hop.To)
+		}
+		_, err := fmt.Fprintf(w, "path %d: %s\n", i+1, strings.Join(names, " -> "))
+		if err != nil {
+			return fmt.Errorf("fmt.Fprintf:%w", err)
+		}
+		for _, hop := range p.Hops {
+			_, err := fmt.Fprintf(w, "\t%s -> %s%s\n", hop.From, hop.To, getHopDetails(hop))
+			if err != nil {
+				return fmt.Errorf("fmt.Fprintf:%w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func getHopDetails(hop Hop) string {
+	details := ""
+	if len(hop.Funcs) > 0 {
+		details = ": " + strings.Join(hop.Funcs, ", ")
+	}
+	if hop.Via != "" {
+		details += " (via " + hop.Via + ")"
+	}
+	return details
+}
+
+// WriteJSON writes the paths as an indented JSON document.
+func WriteJSON(w io.Writer, paths []Path) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(paths)
+	if err != nil {
+		return fmt.Errorf("encoder.Encode:%w", err)
+	}
+	return nil
+}
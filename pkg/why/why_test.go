@@ -0,0 +1,95 @@
+package why
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/emilien-puget/go-dependency-graph/pkg/parse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery(t *testing.T) {
+	as, err := parse.Parse("../parse/testdata/named_inter", nil)
+	require.NoError(t, err)
+
+	paths, err := Query(as, "testdata/named_inter.A", "pa.A", 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Path{
+		{Hops: []Hop{
+			{From: "testdata/named_inter.A", To: "testdata/named_inter.D", Funcs: []string{"FuncA"}},
+			{From: "testdata/named_inter.D", To: "testdata/named_inter/pa.A", Funcs: []string{"FuncFoo"}},
+		}},
+	}, GetPaths(paths))
+}
+
+func TestQuery_not_found(t *testing.T) {
+	as, err := parse.Parse("../parse/testdata/named_inter", nil)
+	require.NoError(t, err)
+
+	_, err = Query(as, "A", "Z", 0)
+	assert.ErrorIs(t, err, ErrNodeNotFound)
+}
+
+func TestGetSchema(t *testing.T) {
+	as, err := parse.Parse("../parse/testdata/named_inter", nil)
+	require.NoError(t, err)
+
+	paths, err := Query(as, "A", "C", 0)
+	require.NoError(t, err)
+
+	s := GetSchema(as, paths)
+	assert.Equal(t, "testdata/named_inter", s.ModulePath)
+	nodes := s.Graph.GetNodesSortedByName()
+	require.Len(t, nodes, 3)
+	assert.Equal(t, "testdata/named_inter.A", nodes[0].Name)
+	assert.Equal(t, "testdata/named_inter.B", nodes[1].Name)
+	assert.Equal(t, "testdata/named_inter.C", nodes[2].Name)
+	assert.Len(t, as.Graph.Nodes, 5)
+}
+
+func TestWriteText(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := WriteText(buf, []Path{
+		{Hops: []Hop{
+			{From: "a.A", To: "a.B", Funcs: []string{"FuncA", "FuncB"}},
+			{From: "a.B", To: "a.C", Via: "a.I"},
+		}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `path 1: a.A -> a.B -> a.C
+	a.A -> a.B: FuncA, FuncB
+	a.B -> a.C (via a.I)
+`, buf.String())
+}
+
+func TestWriteText_none(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := WriteText(buf, []Path{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "no path found\n", buf.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := WriteJSON(buf, []Path{{Hops: []Hop{{From: "a.A", To: "a.B", Funcs: []string{"FuncA"}}}}})
+	require.NoError(t, err)
+
+	assert.Equal(t, `[
+  {
+    "hops": [
+      {
+        "from": "a.A",
+        "to": "a.B",
+        "funcs": [
+          "FuncA"
+        ]
+      }
+    ]
+  }
+]
+`, buf.String())
+}